@@ -2,6 +2,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -16,27 +17,88 @@ type Client struct {
 	endpoint   string
 	apiKey     string
 	httpClient *http.Client
+	retry      RetryPolicy
+	sleep      func(context.Context, time.Duration) error
+	ctx        context.Context
+	endUser    endUser
 }
 
+// endUser is the identity requests are made on behalf of, if any.
+type endUser struct {
+	token string
+	id    string
+}
+
+// DefaultTimeout is the per-request timeout used by NewClient.
+const DefaultTimeout = 30 * time.Second
+
 // NewClient creates a new API client with the given endpoint and API key.
-func NewClient(endpoint, apiKey string) *Client {
-	return &Client{
+func NewClient(endpoint, apiKey string, opts ...Option) *Client {
+	c := &Client{
 		endpoint: normalizeEndpoint(endpoint),
 		apiKey:   apiKey,
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout: DefaultTimeout,
 		},
+		retry: DefaultRetryPolicy(),
+		sleep: sleepContext,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithTimeout sets the timeout for each HTTP request. Zero disables it.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.httpClient.Timeout = timeout
 	}
 }
 
+// WithContext returns a shallow copy of c whose context-less methods (Get,
+// Post, ...) run under ctx. The Ctx variants always use their own argument.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	clone := *c
+	clone.ctx = ctx
+	return &clone
+}
+
+// WithEndUser returns a shallow copy of c whose requests act on behalf of an
+// end user. A non-empty token is sent as the bearer token, which moves the
+// API key to the X-RunAgents-API-Key header; a non-empty id is sent as
+// X-End-User-ID.
+func (c *Client) WithEndUser(token, id string) *Client {
+	clone := *c
+	clone.endUser = endUser{token: strings.TrimSpace(token), id: strings.TrimSpace(id)}
+	return &clone
+}
+
+func (c *Client) context() context.Context {
+	if c.ctx != nil {
+		return c.ctx
+	}
+	return context.Background()
+}
+
 // Get performs a GET request to the given path and returns the response body.
 func (c *Client) Get(path string) ([]byte, error) {
-	return c.GetWithQuery(path, nil)
+	return c.GetWithQueryCtx(c.context(), path, nil)
+}
+
+// GetCtx is like Get but runs under ctx.
+func (c *Client) GetCtx(ctx context.Context, path string) ([]byte, error) {
+	return c.GetWithQueryCtx(ctx, path, nil)
 }
 
 // GetWithQuery performs a GET request to the given path and query values.
 func (c *Client) GetWithQuery(path string, query url.Values) ([]byte, error) {
-	req, err := c.newRequest(http.MethodGet, path, query, nil)
+	return c.GetWithQueryCtx(c.context(), path, query)
+}
+
+// GetWithQueryCtx is like GetWithQuery but runs under ctx.
+func (c *Client) GetWithQueryCtx(ctx context.Context, path string, query url.Values) ([]byte, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, path, query, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -45,16 +107,44 @@ func (c *Client) GetWithQuery(path string, query url.Values) ([]byte, error) {
 
 // Post performs a POST request with a JSON body and returns the response body.
 func (c *Client) Post(path string, payload interface{}) ([]byte, error) {
-	req, err := c.newRequest(http.MethodPost, path, nil, payload)
+	return c.PostCtx(c.context(), path, payload)
+}
+
+// PostCtx is like Post but runs under ctx.
+func (c *Client) PostCtx(ctx context.Context, path string, payload interface{}) ([]byte, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, path, nil, payload)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	return c.do(req)
 }
 
+// PostIdempotent performs a POST request carrying an Idempotency-Key header,
+// which also allows the request to be retried after transient failures.
+func (c *Client) PostIdempotent(path string, payload interface{}, idempotencyKey string) ([]byte, error) {
+	return c.PostIdempotentCtx(c.context(), path, payload, idempotencyKey)
+}
+
+// PostIdempotentCtx is like PostIdempotent but runs under ctx.
+func (c *Client) PostIdempotentCtx(ctx context.Context, path string, payload interface{}, idempotencyKey string) ([]byte, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, path, nil, payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if key := strings.TrimSpace(idempotencyKey); key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	return c.do(req)
+}
+
 // Patch performs a PATCH request with a JSON body and returns the response body.
 func (c *Client) Patch(path string, payload interface{}) ([]byte, error) {
-	req, err := c.newRequest(http.MethodPatch, path, nil, payload)
+	return c.PatchCtx(c.context(), path, payload)
+}
+
+// PatchCtx is like Patch but runs under ctx.
+func (c *Client) PatchCtx(ctx context.Context, path string, payload interface{}) ([]byte, error) {
+	req, err := c.newRequest(ctx, http.MethodPatch, path, nil, payload)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -63,7 +153,12 @@ func (c *Client) Patch(path string, payload interface{}) ([]byte, error) {
 
 // Put performs a PUT request with a JSON body and returns the response body.
 func (c *Client) Put(path string, payload interface{}) ([]byte, error) {
-	req, err := c.newRequest(http.MethodPut, path, nil, payload)
+	return c.PutCtx(c.context(), path, payload)
+}
+
+// PutCtx is like Put but runs under ctx.
+func (c *Client) PutCtx(ctx context.Context, path string, payload interface{}) ([]byte, error) {
+	req, err := c.newRequest(ctx, http.MethodPut, path, nil, payload)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -72,7 +167,12 @@ func (c *Client) Put(path string, payload interface{}) ([]byte, error) {
 
 // Delete performs a DELETE request to the given path.
 func (c *Client) Delete(path string) error {
-	req, err := c.newRequest(http.MethodDelete, path, nil, nil)
+	return c.DeleteCtx(c.context(), path)
+}
+
+// DeleteCtx is like Delete but runs under ctx.
+func (c *Client) DeleteCtx(ctx context.Context, path string) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, path, nil, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
@@ -80,7 +180,7 @@ func (c *Client) Delete(path string) error {
 	return err
 }
 
-func (c *Client) newRequest(method, path string, query url.Values, payload interface{}) (*http.Request, error) {
+func (c *Client) newRequest(ctx context.Context, method, path string, query url.Values, payload interface{}) (*http.Request, error) {
 	target, err := c.buildURL(path, query)
 	if err != nil {
 		return nil, err
@@ -91,7 +191,7 @@ func (c *Client) newRequest(method, path string, query url.Values, payload inter
 		return nil, err
 	}
 
-	req, err := http.NewRequest(method, target, bodyReader)
+	req, err := http.NewRequestWithContext(ctx, method, target, bodyReader)
 	if err != nil {
 		return nil, err
 	}
@@ -143,31 +243,75 @@ func payloadReader(payload interface{}) (io.Reader, error) {
 	return bytes.NewReader(data), nil
 }
 
+// do sends req, retrying transient failures according to the retry policy.
 func (c *Client) do(req *http.Request) ([]byte, error) {
+	replayable := isReplayableRequest(req)
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("failed to rewind request body: %w", err)
+			}
+			req.Body = body
+		}
+
+		body, wait, err := c.attempt(req)
+		if err == nil {
+			return body, nil
+		}
+		if wait < 0 || !replayable || attempt >= c.retry.MaxRetries || req.Context().Err() != nil {
+			return nil, err
+		}
+		if wait == 0 {
+			wait = c.retry.backoff(attempt)
+		}
+		if sleepErr := c.sleep(req.Context(), wait); sleepErr != nil {
+			return nil, fmt.Errorf("%w (gave up retrying: %w)", err, sleepErr)
+		}
+	}
+}
+
+// attempt performs a single round trip. A negative wait means the failure is
+// not retryable; zero means retry with backoff; positive is the server's
+// Retry-After.
+func (c *Client) attempt(req *http.Request) ([]byte, time.Duration, error) {
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, 0, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, 0, fmt.Errorf("failed to read response: %w", err)
 	}
 
 	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("API error (HTTP %d): %s", resp.StatusCode, string(body))
+		apiErr := newAPIError(resp, body)
+		if !isRetryableStatus(resp.StatusCode) {
+			return nil, -1, apiErr
+		}
+		if wait, ok := retryAfter(resp.Header.Get("Retry-After"), time.Now()); ok && wait > 0 {
+			return nil, wait, apiErr
+		}
+		return nil, 0, apiErr
 	}
-	return body, nil
+	return body, 0, nil
 }
 
 // setHeaders adds common headers to the request.
 func (c *Client) setHeaders(req *http.Request) {
+	if c.endUser.id != "" {
+		req.Header.Set("X-End-User-ID", c.endUser.id)
+	}
+	if c.endUser.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.endUser.token)
+	}
 	apiKey := strings.TrimSpace(c.apiKey)
 	if apiKey == "" {
 		return
 	}
-	if strings.HasPrefix(apiKey, "ra_ws_") {
+	if strings.HasPrefix(apiKey, "ra_ws_") || c.endUser.token != "" {
 		req.Header.Set("X-RunAgents-API-Key", apiKey)
 		return
 	}
@@ -2,7 +2,9 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -104,6 +106,40 @@ func TestClientUsesBearerForNonWorkspaceToken(t *testing.T) {
 	}
 }
 
+func TestClientWithEndUserSendsUserTokenAndMovesAPIKey(t *testing.T) {
+	var headers http.Header
+	c := NewClient("https://acme.runagents.io", "jwt-like-token")
+	c.httpClient = &http.Client{
+		Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			headers = r.Header.Clone()
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{}`)),
+				Header:     make(http.Header),
+			}, nil
+		}),
+	}
+	if _, err := c.WithEndUser("user-jwt", "alice@example.com").Post("/agents/billing/invoke", map[string]string{"message": "hi"}); err != nil {
+		t.Fatalf("post invoke: %v", err)
+	}
+	if got := headers.Get("Authorization"); got != "Bearer user-jwt" {
+		t.Fatalf("expected end-user bearer token, got %q", got)
+	}
+	if got := headers.Get("X-RunAgents-API-Key"); got != "jwt-like-token" {
+		t.Fatalf("expected API key header, got %q", got)
+	}
+	if got := headers.Get("X-End-User-ID"); got != "alice@example.com" {
+		t.Fatalf("expected end-user ID header, got %q", got)
+	}
+
+	if _, err := c.Get("/tools"); err != nil {
+		t.Fatalf("get tools: %v", err)
+	}
+	if got := headers.Get("Authorization"); got != "Bearer jwt-like-token" {
+		t.Fatalf("expected the original client to be unchanged, got %q", got)
+	}
+}
+
 func TestClientGetWithQuery(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if got := r.URL.Path; got != "/api/v1/runs" {
@@ -177,7 +213,7 @@ func TestClientDeleteUsesSharedRequestFlow(t *testing.T) {
 
 func TestClientPutBuildsJSONRequest(t *testing.T) {
 	c := NewClient("https://api.runagents.io", "token")
-	req, err := c.newRequest(http.MethodPut, "/policies/demo", nil, map[string]any{"name": "demo"})
+	req, err := c.newRequest(context.Background(), http.MethodPut, "/policies/demo", nil, map[string]any{"name": "demo"})
 	if err != nil {
 		t.Fatalf("expected success, got error: %v", err)
 	}
@@ -195,3 +231,50 @@ func TestClientPutBuildsJSONRequest(t *testing.T) {
 		t.Fatalf("unexpected body: %s", got)
 	}
 }
+
+func TestClientContextCancelsInFlightRequest(t *testing.T) {
+	started := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	c := NewClient(server.URL, "").WithContext(ctx)
+	_, err := c.Get("/runs")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestClientCtxMethodsOverrideBoundContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	c := NewClient(server.URL, "").WithContext(cancelled)
+	if _, err := c.Get("/runs"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected bound context to be used, got %v", err)
+	}
+	if _, err := c.GetCtx(context.Background(), "/runs"); err != nil {
+		t.Fatalf("expected explicit context to win, got %v", err)
+	}
+}
+
+func TestClientWithTimeout(t *testing.T) {
+	if got := NewClient("https://api.runagents.io", "").httpClient.Timeout; got != DefaultTimeout {
+		t.Fatalf("expected default timeout %v, got %v", DefaultTimeout, got)
+	}
+	if got := NewClient("https://api.runagents.io", "", WithTimeout(0)).httpClient.Timeout; got != 0 {
+		t.Fatalf("expected timeout to be disabled, got %v", got)
+	}
+}
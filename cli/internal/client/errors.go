@@ -0,0 +1,105 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// RequestIDHeader carries the server-assigned request ID used for support lookups.
+const RequestIDHeader = "X-Request-Id"
+
+// APIError is returned for any API response with status 400 or above.
+type APIError struct {
+	StatusCode int
+	RequestID  string
+	// Code is the machine-readable error code from the problem body, if any.
+	Code string
+	// Message is the human-readable message from the problem body, falling
+	// back to the raw response body.
+	Message string
+	// Problem is the parsed JSON response body, or nil if it was not JSON.
+	Problem map[string]any
+	Body    []byte
+}
+
+func (e *APIError) Error() string {
+	message := e.Message
+	if message == "" {
+		message = http.StatusText(e.StatusCode)
+	}
+	return fmt.Sprintf("API error (HTTP %d): %s", e.StatusCode, message)
+}
+
+// newAPIError builds an APIError from a failed response. Both {"error": "..."}
+// bodies and RFC 7807 problem documents are understood.
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		RequestID:  strings.TrimSpace(resp.Header.Get(RequestIDHeader)),
+		Message:    strings.TrimSpace(string(body)),
+		Body:       body,
+	}
+
+	var problem map[string]any
+	if err := json.Unmarshal(body, &problem); err != nil || problem == nil {
+		return apiErr
+	}
+	apiErr.Problem = problem
+	if message := problemString(problem, "message", "detail", "error", "title"); message != "" {
+		apiErr.Message = message
+	}
+	apiErr.Code = problemString(problem, "code", "error_code")
+	if apiErr.Code == "" {
+		if nested, ok := problem["error"].(map[string]any); ok {
+			apiErr.Code = problemString(nested, "code", "type")
+			if message := problemString(nested, "message", "detail"); message != "" {
+				apiErr.Message = message
+			}
+		}
+	}
+	if apiErr.RequestID == "" {
+		apiErr.RequestID = problemString(problem, "request_id")
+	}
+	return apiErr
+}
+
+func problemString(problem map[string]any, keys ...string) string {
+	for _, key := range keys {
+		if value, ok := problem[key].(string); ok && strings.TrimSpace(value) != "" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// StatusCode returns the HTTP status of an API error, or 0 if err is not one.
+func StatusCode(err error) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+// IsNotFound reports whether err is an API error with status 404.
+func IsNotFound(err error) bool {
+	return StatusCode(err) == http.StatusNotFound
+}
+
+// IsUnauthorized reports whether err is an API error with status 401.
+func IsUnauthorized(err error) bool {
+	return StatusCode(err) == http.StatusUnauthorized
+}
+
+// IsForbidden reports whether err is an API error with status 403.
+func IsForbidden(err error) bool {
+	return StatusCode(err) == http.StatusForbidden
+}
+
+// IsConflict reports whether err is an API error with status 409.
+func IsConflict(err error) bool {
+	return StatusCode(err) == http.StatusConflict
+}
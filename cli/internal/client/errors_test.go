@@ -0,0 +1,55 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientReturnsTypedAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(RequestIDHeader, "req-123")
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(`{"error":"policy already exists","code":"already_exists"}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, "")
+	_, err := c.Post("/policies", map[string]string{"name": "demo"})
+	wrapped := fmt.Errorf("failed to create policy: %w", err)
+	if !IsConflict(wrapped) || IsNotFound(wrapped) || StatusCode(wrapped) != http.StatusConflict {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+	apiErr := err.(*APIError)
+	if apiErr.RequestID != "req-123" || apiErr.Code != "already_exists" || apiErr.Message != "policy already exists" {
+		t.Fatalf("unexpected API error fields %#v", apiErr)
+	}
+	if apiErr.Problem["code"] != "already_exists" {
+		t.Fatalf("expected parsed problem body, got %#v", apiErr.Problem)
+	}
+	if got := apiErr.Error(); got != "API error (HTTP 409): policy already exists" {
+		t.Fatalf("unexpected error string %q", got)
+	}
+}
+
+func TestNewAPIErrorHandlesProblemDocumentsAndPlainBodies(t *testing.T) {
+	resp := &http.Response{StatusCode: http.StatusUnauthorized, Header: make(http.Header)}
+	apiErr := newAPIError(resp, []byte(`{"type":"about:blank","title":"Unauthorized","detail":"token expired","request_id":"req-9"}`))
+	if !IsUnauthorized(apiErr) || apiErr.Message != "token expired" || apiErr.RequestID != "req-9" {
+		t.Fatalf("unexpected problem parse %#v", apiErr)
+	}
+
+	apiErr = newAPIError(resp, []byte(`{"error":{"code":"invalid_key","message":"API key revoked"}}`))
+	if apiErr.Code != "invalid_key" || apiErr.Message != "API key revoked" {
+		t.Fatalf("unexpected nested error parse %#v", apiErr)
+	}
+
+	apiErr = newAPIError(&http.Response{StatusCode: http.StatusBadGateway, Header: make(http.Header)}, []byte("upstream unavailable\n"))
+	if apiErr.Problem != nil || apiErr.Message != "upstream unavailable" {
+		t.Fatalf("expected plain body message, got %#v", apiErr)
+	}
+	if StatusCode(fmt.Errorf("some random error")) != 0 {
+		t.Fatalf("expected 0 for non-API errors")
+	}
+}
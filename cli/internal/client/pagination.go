@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// maxPages bounds how many pages Paginate follows, so a server that keeps
+// returning cursors cannot loop forever.
+const maxPages = 10000
+
+// listPage is one page of a list response.
+type listPage struct {
+	items []json.RawMessage
+	// next is the query change that fetches the following page; nil when
+	// this is the last page.
+	next url.Values
+}
+
+// Paginate fetches a list endpoint page by page and calls fn with the items
+// of each page until the server reports no further pages or fn returns false.
+//
+// A plain JSON array is a single, complete page. Object responses carry their
+// items in "items", "data" or "results" and are followed through either a
+// cursor ("next_cursor" or "next_page_token", sent back as "cursor") or page
+// numbers ("page", "page_size" and "total").
+func (c *Client) Paginate(path string, query url.Values, fn func(items []json.RawMessage) (bool, error)) error {
+	return c.PaginateCtx(c.context(), path, query, fn)
+}
+
+// PaginateCtx is like Paginate but runs under ctx.
+func (c *Client) PaginateCtx(ctx context.Context, path string, query url.Values, fn func(items []json.RawMessage) (bool, error)) error {
+	current := url.Values{}
+	for key, values := range query {
+		current[key] = append([]string(nil), values...)
+	}
+	seen := map[string]bool{}
+	for pages := 0; pages < maxPages; pages++ {
+		data, err := c.GetWithQueryCtx(ctx, path, current)
+		if err != nil {
+			return err
+		}
+		page, err := parseListPage(data)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s page: %w", path, err)
+		}
+		more, err := fn(page.items)
+		if err != nil || !more || page.next == nil || len(page.items) == 0 {
+			return err
+		}
+		for key, values := range page.next {
+			current[key] = values
+		}
+		marker := current.Encode()
+		if seen[marker] {
+			return fmt.Errorf("pagination for %s did not advance", path)
+		}
+		seen[marker] = true
+	}
+	return fmt.Errorf("pagination for %s exceeded %d pages", path, maxPages)
+}
+
+func parseListPage(data []byte) (listPage, error) {
+	trimmed := strings.TrimSpace(string(data))
+	if trimmed == "" || trimmed == "null" {
+		return listPage{}, nil
+	}
+	if strings.HasPrefix(trimmed, "[") {
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return listPage{}, err
+		}
+		return listPage{items: items}, nil
+	}
+
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(data, &body); err != nil {
+		return listPage{}, err
+	}
+	var page listPage
+	for _, key := range []string{"items", "data", "results"} {
+		if raw, ok := body[key]; ok {
+			if err := json.Unmarshal(raw, &page.items); err != nil {
+				return listPage{}, fmt.Errorf("invalid %q: %w", key, err)
+			}
+			break
+		}
+	}
+
+	if hasMore, ok := rawBool(body["has_more"]); ok && !hasMore {
+		return page, nil
+	}
+	meta := body
+	if nested, ok := body["pagination"]; ok {
+		var pagination map[string]json.RawMessage
+		if err := json.Unmarshal(nested, &pagination); err == nil {
+			meta = pagination
+		}
+	}
+	for _, key := range []string{"next_cursor", "next_page_token"} {
+		if cursor := rawString(meta[key]); cursor != "" {
+			page.next = url.Values{"cursor": {cursor}}
+			return page, nil
+		}
+	}
+	number, hasPage := rawInt(meta["page"])
+	size, hasSize := rawInt(meta["page_size"])
+	total, hasTotal := rawInt(meta["total"])
+	if hasPage && hasSize && hasTotal && size > 0 && number*size < total {
+		page.next = url.Values{"page": {strconv.Itoa(number + 1)}}
+	}
+	return page, nil
+}
+
+func rawString(raw json.RawMessage) string {
+	var value string
+	if len(raw) == 0 || json.Unmarshal(raw, &value) != nil {
+		return ""
+	}
+	return strings.TrimSpace(value)
+}
+
+func rawInt(raw json.RawMessage) (int, bool) {
+	var value int
+	if len(raw) == 0 || json.Unmarshal(raw, &value) != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+func rawBool(raw json.RawMessage) (bool, bool) {
+	var value bool
+	if len(raw) == 0 || json.Unmarshal(raw, &value) != nil {
+		return false, false
+	}
+	return value, true
+}
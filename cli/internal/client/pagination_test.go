@@ -0,0 +1,104 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPaginateFollowsCursors(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			_, _ = w.Write([]byte(`{"items":[{"id":"run-1"},{"id":"run-2"}],"next_cursor":"c2"}`))
+		case "c2":
+			_, _ = w.Write([]byte(`{"items":[{"id":"run-3"}],"next_cursor":""}`))
+		default:
+			t.Errorf("unexpected cursor %q", r.URL.Query().Get("cursor"))
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, "")
+	var ids []string
+	err := c.Paginate("/runs", map[string][]string{"status": {"FAILED"}}, func(items []json.RawMessage) (bool, error) {
+		for _, item := range items {
+			var run struct{ ID string }
+			if err := json.Unmarshal(item, &run); err != nil {
+				return false, err
+			}
+			ids = append(ids, run.ID)
+		}
+		return true, nil
+	})
+	if err != nil {
+		t.Fatalf("Paginate: %v", err)
+	}
+	if fmt.Sprint(ids) != "[run-1 run-2 run-3]" {
+		t.Fatalf("unexpected items %v", ids)
+	}
+	if len(queries) != 2 || queries[1] != "cursor=c2&status=FAILED" {
+		t.Fatalf("expected filters to be kept across pages, got %v", queries)
+	}
+}
+
+func TestPaginateFollowsPageNumbersAndStopsEarly(t *testing.T) {
+	var pages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+		_, _ = fmt.Fprintf(w, `{"items":[{"id":"%s-a"},{"id":"%s-b"}],"page":%s,"page_size":2,"total":6}`, page, page, page)
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, "")
+	count := 0
+	err := c.Paginate("/catalog", map[string][]string{"page": {"1"}, "page_size": {"2"}}, func(items []json.RawMessage) (bool, error) {
+		count += len(items)
+		return count < 4, nil
+	})
+	if err != nil {
+		t.Fatalf("Paginate: %v", err)
+	}
+	if fmt.Sprint(pages) != "[1 2]" || count != 4 {
+		t.Fatalf("expected to stop after two pages, got pages %v and %d items", pages, count)
+	}
+}
+
+func TestPaginateTreatsArraysAsSinglePage(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(`[{"name":"a"},{"name":"b"}]`))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, "")
+	count := 0
+	if err := c.Paginate("/tools", nil, func(items []json.RawMessage) (bool, error) {
+		count += len(items)
+		return true, nil
+	}); err != nil {
+		t.Fatalf("Paginate: %v", err)
+	}
+	if requests != 1 || count != 2 {
+		t.Fatalf("expected one request with 2 items, got %d requests and %d items", requests, count)
+	}
+}
+
+func TestPaginateDetectsStuckCursor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":[{"id":"x"}],"pagination":{"next_cursor":"same"}}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, "")
+	err := c.Paginate("/runs", nil, func(items []json.RawMessage) (bool, error) { return true, nil })
+	if err == nil {
+		t.Fatalf("expected stuck cursor to be reported")
+	}
+}
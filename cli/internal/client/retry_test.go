@@ -0,0 +1,145 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newRetryTestClient(statuses []int, headers http.Header, bodies *[]string) (*Client, *int, *[]time.Duration) {
+	attempts := 0
+	var slept []time.Duration
+	c := NewClient("https://api.runagents.io", "", WithRetryPolicy(RetryPolicy{
+		MaxRetries:     3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+	}))
+	c.sleep = func(_ context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		return nil
+	}
+	c.httpClient = &http.Client{
+		Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			status := statuses[len(statuses)-1]
+			if attempts < len(statuses) {
+				status = statuses[attempts]
+			}
+			attempts++
+			if bodies != nil && r.Body != nil {
+				data, _ := io.ReadAll(r.Body)
+				*bodies = append(*bodies, string(data))
+			}
+			respHeaders := make(http.Header)
+			for key, values := range headers {
+				respHeaders[key] = values
+			}
+			return &http.Response{
+				StatusCode: status,
+				Body:       io.NopCloser(strings.NewReader(`{}`)),
+				Header:     respHeaders,
+			}, nil
+		}),
+	}
+	return c, &attempts, &slept
+}
+
+func TestClientRetriesIdempotentRequestsOnTransientErrors(t *testing.T) {
+	c, attempts, slept := newRetryTestClient([]int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK}, nil, nil)
+	if _, err := c.Get("/runs"); err != nil {
+		t.Fatalf("expected retry to succeed, got %v", err)
+	}
+	if *attempts != 3 {
+		t.Fatalf("expected 3 attempts, got %d", *attempts)
+	}
+	if len(*slept) != 2 {
+		t.Fatalf("expected 2 backoff sleeps, got %v", *slept)
+	}
+	for i, d := range *slept {
+		limit := 100 * time.Millisecond << i
+		if d < limit/2 || d > limit {
+			t.Fatalf("backoff %d = %v outside [%v, %v]", i, d, limit/2, limit)
+		}
+	}
+}
+
+func TestClientGivesUpAfterMaxRetries(t *testing.T) {
+	c, attempts, _ := newRetryTestClient([]int{http.StatusGatewayTimeout}, nil, nil)
+	_, err := c.Get("/runs")
+	if err == nil || !strings.Contains(err.Error(), "HTTP 504") {
+		t.Fatalf("expected final 504 error, got %v", err)
+	}
+	if *attempts != 4 {
+		t.Fatalf("expected 1 attempt plus 3 retries, got %d", *attempts)
+	}
+}
+
+func TestClientHonorsRetryAfter(t *testing.T) {
+	headers := http.Header{"Retry-After": []string{"7"}}
+	c, _, slept := newRetryTestClient([]int{http.StatusTooManyRequests, http.StatusOK}, headers, nil)
+	if _, err := c.Get("/runs"); err != nil {
+		t.Fatalf("expected retry to succeed, got %v", err)
+	}
+	if len(*slept) != 1 || (*slept)[0] != 7*time.Second {
+		t.Fatalf("expected a 7s Retry-After wait, got %v", *slept)
+	}
+}
+
+func TestClientDoesNotRetryNonRetryableStatus(t *testing.T) {
+	c, attempts, _ := newRetryTestClient([]int{http.StatusNotFound}, nil, nil)
+	if _, err := c.Get("/runs/missing"); err == nil {
+		t.Fatalf("expected error")
+	}
+	if *attempts != 1 {
+		t.Fatalf("expected a single attempt, got %d", *attempts)
+	}
+}
+
+func TestClientRetriesPostOnlyWithIdempotencyKey(t *testing.T) {
+	c, attempts, _ := newRetryTestClient([]int{http.StatusServiceUnavailable, http.StatusOK}, nil, nil)
+	if _, err := c.Post("/runs", map[string]string{"agent_id": "demo"}); err == nil {
+		t.Fatalf("expected plain POST not to be retried")
+	}
+	if *attempts != 1 {
+		t.Fatalf("expected a single POST attempt, got %d", *attempts)
+	}
+
+	var bodies []string
+	c, attempts, _ = newRetryTestClient([]int{http.StatusServiceUnavailable, http.StatusOK}, nil, &bodies)
+	if _, err := c.PostIdempotent("/runs", map[string]string{"agent_id": "demo"}, "run-123"); err != nil {
+		t.Fatalf("expected idempotent POST to be retried, got %v", err)
+	}
+	if *attempts != 2 {
+		t.Fatalf("expected 2 POST attempts, got %d", *attempts)
+	}
+	if len(bodies) != 2 || bodies[1] != `{"agent_id":"demo"}` {
+		t.Fatalf("expected body to be replayed, got %q", bodies)
+	}
+}
+
+func TestClientRetryPolicyCanBeDisabled(t *testing.T) {
+	c, attempts, _ := newRetryTestClient([]int{http.StatusBadGateway}, nil, nil)
+	c.retry = RetryPolicy{}
+	if _, err := c.Get("/runs"); err == nil {
+		t.Fatalf("expected error")
+	}
+	if *attempts != 1 {
+		t.Fatalf("expected no retries, got %d attempts", *attempts)
+	}
+}
+
+func TestRetryAfterParsesHTTPDate(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	got, ok := retryAfter(now.Add(30*time.Second).Format(http.TimeFormat), now)
+	if !ok || got != 30*time.Second {
+		t.Fatalf("expected 30s, got %v (ok %v)", got, ok)
+	}
+	if got, ok := retryAfter("3600", now); !ok || got != maxRetryAfter {
+		t.Fatalf("expected Retry-After to be capped, got %v", got)
+	}
+	if _, ok := retryAfter("soon", now); ok {
+		t.Fatalf("expected invalid Retry-After to be ignored")
+	}
+}
@@ -0,0 +1,153 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ErrStreamingUnsupported is returned by Stream when the server answers with
+// a regular response instead of a text/event-stream.
+var ErrStreamingUnsupported = errors.New("server does not support event streaming")
+
+// StreamEvent is one server-sent event.
+type StreamEvent struct {
+	ID    string
+	Event string
+	Data  []byte
+}
+
+// Stream opens a server-sent events stream on path and calls fn for each
+// event until the server closes the stream, fn returns an error, or the
+// client's context is done. The per-request timeout does not apply.
+func (c *Client) Stream(path string, query url.Values, fn func(StreamEvent) error) error {
+	return c.StreamCtx(c.context(), path, query, fn)
+}
+
+// StreamCtx is like Stream but runs under ctx.
+func (c *Client) StreamCtx(ctx context.Context, path string, query url.Values, fn func(StreamEvent) error) error {
+	req, err := c.newRequest(ctx, http.MethodGet, path, query, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	body, err := c.stream(ctx, req, fn)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		return ErrStreamingUnsupported
+	}
+	return nil
+}
+
+// PostStream posts payload to path and asks for a server-sent events
+// response. When the server streams, fn is called for each event and the
+// returned body is nil; when it answers with a regular response instead, that
+// response body is returned. The request is not retried.
+func (c *Client) PostStream(path string, payload interface{}, fn func(StreamEvent) error) ([]byte, error) {
+	return c.PostStreamCtx(c.context(), path, payload, fn)
+}
+
+// PostStreamCtx is like PostStream but runs under ctx.
+func (c *Client) PostStreamCtx(ctx context.Context, path string, payload interface{}, fn func(StreamEvent) error) ([]byte, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, path, nil, payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	return c.stream(ctx, req, fn)
+}
+
+// stream sends req without the per-request timeout and reads the event
+// stream. A response that is not an event stream is returned as a non-nil
+// body without calling fn.
+func (c *Client) stream(ctx context.Context, req *http.Request, fn func(StreamEvent) error) ([]byte, error) {
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-cache")
+
+	streaming := *c.httpClient
+	streaming.Timeout = 0
+	resp, err := streaming.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, newAPIError(resp, body)
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/event-stream" {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+		if body == nil {
+			body = []byte{}
+		}
+		return body, nil
+	}
+	if err := readEventStream(resp.Body, fn); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, err
+	}
+	return nil, nil
+}
+
+// readEventStream parses the text/event-stream format: "field: value" lines,
+// with a blank line dispatching the event. Comments and retry hints are ignored.
+func readEventStream(r io.Reader, fn func(StreamEvent) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+
+	var (
+		event StreamEvent
+		data  []string
+	)
+	dispatch := func() error {
+		if len(data) == 0 {
+			event = StreamEvent{ID: event.ID}
+			return nil
+		}
+		event.Data = []byte(strings.Join(data, "\n"))
+		err := fn(event)
+		event = StreamEvent{ID: event.ID}
+		data = nil
+		return err
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if err := dispatch(); err != nil {
+				return err
+			}
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event.Event = value
+		case "data":
+			data = append(data, value)
+		case "id":
+			event.ID = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read event stream: %w", err)
+	}
+	return dispatch()
+}
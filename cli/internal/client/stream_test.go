@@ -0,0 +1,93 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStreamParsesServerSentEvents(t *testing.T) {
+	var accept string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accept = r.Header.Get("Accept")
+		w.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+		_, _ = w.Write([]byte(": keep-alive\n\nid: 1\nevent: run_event\ndata: {\"seq\":1}\n\nid: 2\ndata: line one\ndata: line two\n\n"))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, "")
+	var events []StreamEvent
+	if err := c.Stream("/runs/run-1/events", nil, func(event StreamEvent) error {
+		events = append(events, event)
+		return nil
+	}); err != nil {
+		t.Fatalf("Stream: %v", err)
+	}
+	if accept != "text/event-stream" {
+		t.Fatalf("expected event-stream Accept header, got %q", accept)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %#v", events)
+	}
+	if events[0].ID != "1" || events[0].Event != "run_event" || string(events[0].Data) != `{"seq":1}` {
+		t.Fatalf("unexpected first event %#v", events[0])
+	}
+	if events[1].ID != "2" || string(events[1].Data) != "line one\nline two" {
+		t.Fatalf("unexpected multi-line event %#v", events[1])
+	}
+}
+
+func TestStreamReportsUnsupportedServers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, "")
+	err := c.Stream("/runs/run-1/events", nil, func(StreamEvent) error { return nil })
+	if !errors.Is(err, ErrStreamingUnsupported) {
+		t.Fatalf("expected ErrStreamingUnsupported, got %v", err)
+	}
+}
+
+func TestPostStreamReturnsRegularResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST, got %s", r.Method)
+		}
+		if r.Header.Get("Accept") == "text/event-stream" && r.URL.Query().Get("mode") == "stream" {
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = w.Write([]byte("data: {\"type\":\"content\",\"delta\":\"Hi\"}\n\ndata: [DONE]\n\n"))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"response":"Hi"}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, "")
+	var events []StreamEvent
+	body, err := c.PostStream("/agents/billing/invoke?mode=stream", map[string]string{"message": "hello"}, func(event StreamEvent) error {
+		events = append(events, event)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("PostStream: %v", err)
+	}
+	if body != nil || len(events) != 2 || string(events[1].Data) != "[DONE]" {
+		t.Fatalf("expected streamed events, got body %q and %#v", body, events)
+	}
+
+	body, err = c.PostStream("/agents/billing/invoke", map[string]string{"message": "hello"}, func(StreamEvent) error {
+		t.Fatalf("expected no events for a JSON response")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("PostStream: %v", err)
+	}
+	if string(body) != `{"response":"Hi"}` {
+		t.Fatalf("expected the JSON body, got %q", body)
+	}
+}
@@ -3,6 +3,7 @@ package commands
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
 
 	"github.com/olekukonko/tablewriter"
@@ -19,34 +20,38 @@ func newAgentsCmd() *cobra.Command {
 	cmd.AddCommand(newAgentsGetCmd())
 	cmd.AddCommand(newAgentsConfigCmd())
 	cmd.AddCommand(newAgentsDeleteCmd())
+	cmd.AddCommand(newAgentsInvokeCmd())
+	cmd.AddCommand(newAgentsChatCmd())
 
 	return cmd
 }
 
 func newAgentsListCmd() *cobra.Command {
-	return &cobra.Command{
+	var list listOptions
+	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all agents",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
 
-			data, err := c.Get("/agents")
+			query := url.Values{"limit": {list.pageSize()}}
+			items, truncated, err := fetchListItems(c, "/agents", query, list.limitOrAll(), nil)
 			if err != nil {
 				return err
 			}
+			items = trimListItems(items, list.limitOrAll())
 
 			if isJSONOutput() {
-				fmt.Println(string(data))
-				return nil
+				return printJSONValue(items)
 			}
 
-			var agents []map[string]interface{}
-			if err := json.Unmarshal(data, &agents); err != nil {
-				return fmt.Errorf("failed to parse response: %w", err)
+			agents, err := decodeListItems(items)
+			if err != nil {
+				return err
 			}
 
 			table := newTable("NAME", "STATUS", "IMAGE")
@@ -59,9 +64,12 @@ func newAgentsListCmd() *cobra.Command {
 			}
 
 			table.Render()
+			printListTruncatedHint(truncated, len(agents), "agents")
 			return nil
 		},
 	}
+	addListFlags(cmd, &list, "agents", 0)
+	return cmd
 }
 
 func newAgentsGetCmd() *cobra.Command {
@@ -75,7 +83,7 @@ func newAgentsGetCmd() *cobra.Command {
 				name = args[1]
 			}
 
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
@@ -135,7 +143,7 @@ func newAgentsConfigGetCmd() *cobra.Command {
 				name = args[1]
 			}
 
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
@@ -232,7 +240,7 @@ func newAgentsConfigUpdateCmd() *cobra.Command {
 				return fmt.Errorf("invalid JSON in %q: %w", filePath, err)
 			}
 
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
@@ -271,7 +279,7 @@ func newAgentsDeleteCmd() *cobra.Command {
 				name = args[1]
 			}
 
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
@@ -0,0 +1,383 @@
+package commands
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/runagents/runagents/cli/internal/client"
+	"github.com/spf13/cobra"
+)
+
+const agentChatHelp = `/run                      show the timeline of the current run
+/approve [scope] [dur]    approve the action the run is waiting on (scope: once, run, or window)
+/wait                     wait for the run to continue after an approval or consent elsewhere
+/new                      start a new conversation
+/exit                     leave the chat`
+
+// agentChatSession is the state of an agents chat shell: the conversation
+// that ties turns together and the run created by the latest turn.
+type agentChatSession struct {
+	ctx    context.Context
+	agent  string
+	invoke *client.Client
+	api    *client.Client
+	out    io.Writer
+	userID string
+
+	conversationID string
+	runID          string
+	pause          *agentInvokePause
+	lastSeq        int
+
+	interval    time.Duration
+	waitTimeout time.Duration
+}
+
+func newAgentsChatCmd() *cobra.Command {
+	var (
+		conversationID string
+		userToken      string
+		userID         string
+		waitTimeout    time.Duration
+	)
+	cmd := &cobra.Command{
+		Use:   "chat <name>",
+		Short: "Chat with a deployed agent in an interactive shell",
+		Long: `Chat with a deployed agent. Every message is sent to /agents/{name}/invoke in
+the same conversation, and replies are printed as they stream in.
+
+When the agent pauses for approval or consent, the pause is shown inline.
+Approve it from the prompt with /approve (if your API key may approve), or
+approve or connect elsewhere and type /wait.
+
+` + agentChatHelp,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Reading stdin cannot be interrupted by a cancelled context, so
+			// keep the default Ctrl-C behaviour, as the copilot shell does.
+			signal.Reset(os.Interrupt)
+
+			c, err := newAPIClient(cmd.Context())
+			if err != nil {
+				return err
+			}
+			session := &agentChatSession{
+				ctx:            cmd.Context(),
+				agent:          args[0],
+				invoke:         c.WithEndUser(userToken, userID),
+				api:            c,
+				out:            os.Stdout,
+				userID:         strings.TrimSpace(userID),
+				conversationID: strings.TrimSpace(conversationID),
+				interval:       2 * time.Second,
+				waitTimeout:    waitTimeout,
+			}
+			if session.conversationID == "" {
+				session.conversationID = newConversationID()
+			}
+			fmt.Printf("Chatting with %s (conversation %s). Type /help for commands, /exit to leave.\n", session.agent, session.conversationID)
+			return runLineShell(os.Stdin, os.Stdout, "you> ", session.handle)
+		},
+	}
+	cmd.Flags().StringVar(&conversationID, "conversation", "", "Conversation ID to continue (default a new conversation)")
+	cmd.Flags().StringVar(&userToken, "as-user", "", "End-user JWT to chat as")
+	cmd.Flags().StringVar(&userID, "user-id", "", "End-user ID to chat as")
+	cmd.Flags().DurationVar(&waitTimeout, "wait-timeout", 10*time.Minute, "Maximum time /approve and /wait wait for the run to continue")
+	return cmd
+}
+
+func newConversationID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("cli-%d", time.Now().UnixNano())
+	}
+	return "cli-" + hex.EncodeToString(buf)
+}
+
+func (s *agentChatSession) handle(line string) error {
+	if !strings.HasPrefix(line, "/") {
+		return s.send(line)
+	}
+	fields := strings.Fields(line)
+	switch fields[0] {
+	case "/help":
+		fmt.Fprintln(s.out, agentChatHelp)
+		return nil
+	case "/run":
+		return s.printRun()
+	case "/approve":
+		return s.approve(fields[1:])
+	case "/wait":
+		return s.wait()
+	case "/new":
+		s.conversationID = newConversationID()
+		s.runID, s.pause, s.lastSeq = "", nil, 0
+		fmt.Fprintf(s.out, "Started conversation %s.\n", s.conversationID)
+		return nil
+	default:
+		return fmt.Errorf("unknown command %s; type /help", fields[0])
+	}
+}
+
+// send runs one turn: it invokes the agent, streaming the reply when the
+// server supports it, and reports any pause.
+func (s *agentChatSession) send(message string) error {
+	payload := map[string]any{"message": message, "conversation_id": s.conversationID}
+	startedAt := time.Now().UTC()
+
+	stream := &agentChatStream{out: s.out}
+	body, err := s.invoke.PostStreamCtx(s.ctx, fmt.Sprintf("/agents/%s/invoke", s.agent), payload, stream.handle)
+	stream.endLine()
+	if err != nil {
+		return fmt.Errorf("failed to invoke agent %q: %w", s.agent, err)
+	}
+	if body == nil {
+		if body, err = stream.response(); err != nil {
+			return err
+		}
+	}
+	result, err := parseAgentInvokeResponse(s.agent, body)
+	if err != nil {
+		return err
+	}
+	if !stream.printed && result.Reply != "" {
+		fmt.Fprintf(s.out, "agent> %s\n", strings.TrimSpace(result.Reply))
+	}
+
+	s.conversationID = firstNonEmptyRunValue(result.ConversationID, s.conversationID)
+	if result.RunID == "" {
+		if run, err := findInvokedRun(s.api, s.agent, invokedRunMatch{ConversationID: s.conversationID, UserID: s.userID, Message: message}, startedAt); err == nil {
+			result.RunID = run.ID
+		}
+	}
+	if result.RunID != s.runID {
+		// Event sequence numbers start again in every run.
+		s.lastSeq = 0
+	}
+	s.runID = result.RunID
+	s.setPause(result)
+	if s.pause != nil && s.runID != "" {
+		if events, err := fetchRunEvents(s.api, s.runID, 0); err == nil {
+			s.lastSeq = lastRunEventSeq(events)
+		}
+	}
+	return nil
+}
+
+func (s *agentChatSession) setPause(result *agentInvokeResult) {
+	s.pause = nil
+	switch {
+	case result.Approval != nil:
+		s.pause = result.Approval
+		fmt.Fprintf(s.out, "[approval] %s needs approval", emptyFallback(result.Approval.Tool, "A tool call"))
+		if result.Approval.ActionID != "" {
+			fmt.Fprintf(s.out, " (action %s)", result.Approval.ActionID)
+		}
+		fmt.Fprintln(s.out, ". Type /approve to approve it, or /wait once it is approved elsewhere.")
+	case result.Consent != nil:
+		s.pause = result.Consent
+		fmt.Fprintf(s.out, "[consent] Connect %s", emptyFallback(result.Consent.Tool, "the tool"))
+		if result.Consent.AuthorizationURL != "" {
+			fmt.Fprintf(s.out, " at %s", result.Consent.AuthorizationURL)
+		}
+		fmt.Fprintln(s.out, ", then type /wait.")
+	}
+}
+
+func (s *agentChatSession) approve(args []string) error {
+	if s.runID == "" {
+		return fmt.Errorf("no run to approve yet; send a message first")
+	}
+	scope, duration := "", ""
+	if len(args) > 0 {
+		scope = args[0]
+	}
+	if len(args) > 1 {
+		duration = args[1]
+	}
+	decision, err := buildApprovalDecision(scope, duration)
+	if err != nil {
+		return err
+	}
+	actionID := ""
+	if s.pause != nil {
+		actionID = s.pause.ActionID
+	}
+	if actionID == "" {
+		if actionID, err = resolveRunActionID(s.api, []string{s.runID}); err != nil {
+			return err
+		}
+	}
+	data, err := approveRunAction(s.api, s.runID, actionID, decision)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(s.out, describeRunActionApproval(fmt.Sprintf("Action %s", actionID), decision, data))
+	return s.wait()
+}
+
+// wait polls the current run until it finishes or pauses on a different
+// action, then prints the agent messages recorded in the meantime.
+func (s *agentChatSession) wait() error {
+	if s.runID == "" {
+		return fmt.Errorf("no run to wait for yet; send a message first")
+	}
+	pausedOn := ""
+	if s.pause != nil {
+		pausedOn = s.pause.ActionID
+	}
+	ctx, cancel := context.WithTimeout(s.ctx, s.waitTimeout)
+	defer cancel()
+
+	var run *cliRun
+	err := waitForCondition(ctx, s.interval, func(ctx context.Context) (bool, error) {
+		latest, err := fetchRun(s.api.WithContext(ctx), s.runID)
+		if err != nil {
+			return false, err
+		}
+		run = latest
+		if isTerminalRunStatus(run.Status) {
+			return true, nil
+		}
+		return isBlockedRunStatus(run.Status) && run.BlockedActionID != "" && run.BlockedActionID != pausedOn, nil
+	})
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("run %s did not continue within %s; type /wait to keep waiting", s.runID, s.waitTimeout)
+		}
+		return err
+	}
+
+	events, err := fetchRunEvents(s.api, s.runID, 0)
+	if err != nil {
+		return err
+	}
+	for _, event := range sortedRunEvents(events) {
+		if event.Seq <= s.lastSeq {
+			continue
+		}
+		switch event.Type {
+		case "AGENT_MESSAGE":
+			fmt.Fprintf(s.out, "agent> %s\n", firstNonEmptyRunValue(dataString(event.Data, "content"), dataString(event.Data, "message")))
+		case "APPROVAL_REQUIRED":
+			fmt.Fprintf(s.out, "[approval] %s\n", summarizeRunEvent(event))
+		case "CONSENT_REQUIRED":
+			fmt.Fprintf(s.out, "[consent] %s\n", summarizeRunEvent(event))
+		case "FAILED", "INVOKE_FAILED":
+			fmt.Fprintf(s.out, "[error] %s\n", summarizeRunEvent(event))
+		}
+	}
+	s.lastSeq = lastRunEventSeq(events)
+
+	s.pause = nil
+	if isBlockedRunStatus(run.Status) {
+		s.pause = &agentInvokePause{ActionID: run.BlockedActionID}
+		fmt.Fprintf(s.out, "Run %s %s. Type /approve or /wait.\n", run.ID, describeRunWaitStatus(*run))
+	} else if run.Status == "FAILED" {
+		fmt.Fprintf(s.out, "Run %s failed.\n", run.ID)
+	}
+	return nil
+}
+
+func (s *agentChatSession) printRun() error {
+	if s.runID == "" {
+		return fmt.Errorf("no run yet; send a message first")
+	}
+	run, err := fetchRun(s.api, s.runID)
+	if err != nil {
+		return err
+	}
+	events, err := fetchRunEvents(s.api, s.runID, 0)
+	if err != nil {
+		return err
+	}
+	printRunTimeline(*run, buildRunTimeline(*run, events))
+	return nil
+}
+
+// agentChatStream prints a streamed reply as it arrives and keeps what is
+// needed to build the final response.
+type agentChatStream struct {
+	out      io.Writer
+	text     strings.Builder
+	final    map[string]any
+	printed  bool
+	lineOpen bool
+}
+
+func (s *agentChatStream) handle(event client.StreamEvent) error {
+	data := strings.TrimSpace(string(event.Data))
+	if data == "" || data == "[DONE]" {
+		return nil
+	}
+	var msg map[string]any
+	if err := json.Unmarshal([]byte(data), &msg); err != nil {
+		s.write(data)
+		return nil
+	}
+	switch firstNonEmptyRunValue(dataString(msg, "type"), event.Event) {
+	case "content", "delta", "token":
+		s.write(firstNonEmptyRunValue(dataString(msg, "delta"), dataString(msg, "content")))
+	case "tool_call":
+		s.endLine()
+		fmt.Fprintf(s.out, "[tool] calling %s\n", firstNonEmptyRunValue(dataString(msg, "tool"), dataString(msg, "function"), "tool"))
+	case "approval_required", "consent_required":
+		if s.final == nil {
+			s.final = map[string]any{}
+		}
+		s.final[firstNonEmptyRunValue(dataString(msg, "type"), event.Event)] = msg
+	case "done", "final", "result":
+		if s.final != nil {
+			for key, value := range s.final {
+				if _, ok := msg[key]; !ok {
+					msg[key] = value
+				}
+			}
+		}
+		s.final = msg
+	case "error":
+		return fmt.Errorf("agent error: %s", firstNonEmptyRunValue(dataString(msg, "error"), dataString(msg, "message"), data))
+	}
+	return nil
+}
+
+func (s *agentChatStream) write(delta string) {
+	if delta == "" {
+		return
+	}
+	if !s.lineOpen {
+		fmt.Fprint(s.out, "agent> ")
+		s.lineOpen = true
+	}
+	fmt.Fprint(s.out, delta)
+	s.text.WriteString(delta)
+	s.printed = true
+}
+
+func (s *agentChatStream) endLine() {
+	if s.lineOpen {
+		fmt.Fprintln(s.out)
+		s.lineOpen = false
+	}
+}
+
+// response returns the streamed turn as an invoke response body.
+func (s *agentChatStream) response() ([]byte, error) {
+	final := s.final
+	if final == nil {
+		final = map[string]any{}
+	}
+	if dataString(final, "response") == "" && s.text.Len() > 0 {
+		final["response"] = s.text.String()
+	}
+	return json.Marshal(final)
+}
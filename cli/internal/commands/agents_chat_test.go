@@ -0,0 +1,171 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/runagents/runagents/cli/internal/client"
+)
+
+func TestAgentChatStreamsReplyAndApprovesInline(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		approved bool
+		invoked  map[string]any
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/api/v1/agents/billing/invoke":
+			_ = json.NewDecoder(r.Body).Decode(&invoked)
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = w.Write([]byte(strings.Join([]string{
+				`data: {"type":"content","delta":"Let me "}`,
+				`data: {"type":"content","delta":"refund that."}`,
+				`data: {"type":"tool_call","tool":"stripe"}`,
+				`data: {"type":"approval_required","action_id":"act-1","tool":"stripe"}`,
+				`data: {"type":"done","run_id":"run-1"}`,
+				`data: [DONE]`,
+			}, "\n\n") + "\n\n"))
+		case "/api/v1/runs/run-1/events":
+			events := []cliRunEvent{
+				{Seq: 1, Type: "USER_MESSAGE", Data: map[string]any{"content": "refund order 42"}},
+				{Seq: 2, Type: "APPROVAL_REQUIRED", Data: map[string]any{"tool_id": "stripe"}},
+			}
+			if approved {
+				events = append(events, cliRunEvent{Seq: 3, Type: "APPROVED", Actor: "ops"}, cliRunEvent{Seq: 4, Type: "AGENT_MESSAGE", Data: map[string]any{"content": "Refund issued."}})
+			}
+			_ = json.NewEncoder(w).Encode(events)
+		case "/api/v1/runs/run-1/actions/act-1":
+			_ = json.NewEncoder(w).Encode(cliRunAction{ActionID: "act-1", RunID: "run-1", Status: "BLOCKED", PayloadHash: "sha256:abc"})
+		case "/api/v1/runs/run-1/actions/act-1/approve":
+			approved = true
+			_, _ = w.Write([]byte(`{}`))
+		case "/api/v1/runs/run-1":
+			status := "PAUSED_APPROVAL"
+			if approved {
+				status = "COMPLETED"
+			}
+			_ = json.NewEncoder(w).Encode(cliRun{ID: "run-1", Status: status, BlockedActionID: "act-1"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c := client.NewClient(server.URL, "ra_ws_test")
+	var out bytes.Buffer
+	session := &agentChatSession{
+		ctx:            context.Background(),
+		agent:          "billing",
+		invoke:         c,
+		api:            c,
+		out:            &out,
+		conversationID: "conv-1",
+		interval:       time.Millisecond,
+		waitTimeout:    time.Second,
+	}
+
+	if err := session.handle("refund order 42"); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if invoked["conversation_id"] != "conv-1" {
+		t.Fatalf("expected the conversation ID to be sent, got %v", invoked)
+	}
+	if session.runID != "run-1" || session.pause == nil || session.pause.ActionID != "act-1" || session.lastSeq != 2 {
+		t.Fatalf("unexpected session state: run=%q pause=%+v lastSeq=%d", session.runID, session.pause, session.lastSeq)
+	}
+	if err := session.handle("/approve"); err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+
+	got := out.String()
+	for _, want := range []string{
+		"agent> Let me refund that.\n[tool] calling stripe\n",
+		"[approval] stripe needs approval (action act-1).",
+		"Action act-1 approved.",
+		"agent> Refund issued.\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected output to contain %q, got:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "refund order 42") {
+		t.Fatalf("expected events from before the pause not to be replayed, got:\n%s", got)
+	}
+}
+
+func TestRunLineShellSkipsBlankLinesAndStopsOnExit(t *testing.T) {
+	var handled []string
+	var out bytes.Buffer
+	err := runLineShell(strings.NewReader("hello\n\n  /run  \nexit\nignored\n"), &out, "> ", func(line string) error {
+		handled = append(handled, line)
+		if line == "/run" {
+			return context.Canceled
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("runLineShell: %v", err)
+	}
+	if strings.Join(handled, ",") != "hello,/run" {
+		t.Fatalf("unexpected lines: %v", handled)
+	}
+	if !strings.Contains(out.String(), "error: context canceled") {
+		t.Fatalf("expected handler errors to be printed, got %q", out.String())
+	}
+}
+
+func TestAgentChatWaitShowsEventsOfANewRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/agents/billing/invoke":
+			_, _ = w.Write([]byte(`{"response": "Working on it.", "run_id": "run-2"}`))
+		case "/api/v1/runs/run-2":
+			_ = json.NewEncoder(w).Encode(cliRun{ID: "run-2", Status: "COMPLETED"})
+		case "/api/v1/runs/run-2/events":
+			_ = json.NewEncoder(w).Encode([]cliRunEvent{
+				{Seq: 1, Type: "USER_MESSAGE", Data: map[string]any{"content": "and the invoice?"}},
+				{Seq: 2, Type: "AGENT_MESSAGE", Data: map[string]any{"content": "Invoice sent."}},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c := client.NewClient(server.URL, "ra_ws_test")
+	var out bytes.Buffer
+	session := &agentChatSession{
+		ctx:            context.Background(),
+		agent:          "billing",
+		invoke:         c,
+		api:            c,
+		out:            &out,
+		conversationID: "conv-1",
+		runID:          "run-1",
+		lastSeq:        6,
+		interval:       time.Millisecond,
+		waitTimeout:    time.Second,
+	}
+	if err := session.handle("and the invoice?"); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if session.runID != "run-2" || session.lastSeq != 0 {
+		t.Fatalf("expected the event cursor to reset for the new run: run=%q lastSeq=%d", session.runID, session.lastSeq)
+	}
+	if err := session.handle("/wait"); err != nil {
+		t.Fatalf("wait: %v", err)
+	}
+	if !strings.Contains(out.String(), "agent> Invoice sent.\n") {
+		t.Fatalf("expected the new run's events to be shown, got:\n%s", out.String())
+	}
+}
@@ -0,0 +1,371 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/runagents/runagents/cli/internal/client"
+	"github.com/spf13/cobra"
+)
+
+// agentInvokeResult is what one invocation of an agent produced.
+type agentInvokeResult struct {
+	Agent          string            `json:"agent"`
+	RunID          string            `json:"run_id,omitempty"`
+	ConversationID string            `json:"conversation_id,omitempty"`
+	Status         string            `json:"status,omitempty"`
+	Reply          string            `json:"reply"`
+	Approval       *agentInvokePause `json:"approval_required,omitempty"`
+	Consent        *agentInvokePause `json:"consent_required,omitempty"`
+	Response       map[string]any    `json:"response"`
+
+	// runLookupErr explains why the run could not be identified when the
+	// response did not name it.
+	runLookupErr error
+}
+
+// agentInvokePause describes why an invocation stopped for a human.
+type agentInvokePause struct {
+	ActionID         string `json:"action_id,omitempty"`
+	Tool             string `json:"tool,omitempty"`
+	Message          string `json:"message,omitempty"`
+	AuthorizationURL string `json:"authorization_url,omitempty"`
+}
+
+type agentInvokeOptions struct {
+	Message        string
+	Payload        string
+	ConversationID string
+	UserToken      string
+	UserID         string
+	Wait           bool
+	WaitTimeout    time.Duration
+	Interval       time.Duration
+}
+
+func newAgentsInvokeCmd() *cobra.Command {
+	var opts agentInvokeOptions
+	cmd := &cobra.Command{
+		Use:   "invoke <name>",
+		Short: "Send a message to a deployed agent and print its reply",
+		Long: `Send a message to a deployed agent through /agents/{name}/invoke and print the
+reply along with the run it created.
+
+Use --payload to send a structured JSON or YAML request body, from a file or
+from stdin with "--payload -"; --message and --conversation are merged into
+it. "--message -" reads the message text from stdin.
+
+--as-user sends an end-user JWT so the agent runs with that user's identity.
+--user-id asserts the end-user ID directly (X-End-User-ID), for workspaces
+that trust the API key to do so.
+
+With --wait, the command follows the run through approval and consent pauses
+until it finishes, and exits with the same codes as 'runs wait'.
+
+Examples:
+  runagents agents invoke billing-agent --message "What did I pay last month?"
+  runagents agents invoke billing-agent --message "Refund it" --conversation conv-123 --wait
+  runagents agents invoke billing-agent --as-user "$USER_JWT" --message "Show my invoices"
+  echo '{"message": "Summarize", "document_id": "doc-7"}' | runagents agents invoke summarizer --payload -`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			payload, err := buildAgentInvokePayload(opts, os.Stdin)
+			if err != nil {
+				return err
+			}
+			c, err := newAPIClient(cmd.Context())
+			if err != nil {
+				return err
+			}
+			result, err := invokeAgent(c.WithEndUser(opts.UserToken, opts.UserID), args[0], payload, opts.UserID)
+			if err != nil {
+				return err
+			}
+			if opts.Wait {
+				return waitForInvokedRun(cmd.Context(), c, result, opts)
+			}
+			return printAgentInvokeResult(result)
+		},
+	}
+	cmd.Flags().StringVarP(&opts.Message, "message", "m", "", `Message to send ("-" reads it from stdin)`)
+	cmd.Flags().StringVar(&opts.Payload, "payload", "", `JSON or YAML request body file ("-" reads it from stdin)`)
+	cmd.Flags().StringVar(&opts.ConversationID, "conversation", "", "Conversation ID to continue")
+	cmd.Flags().StringVar(&opts.UserToken, "as-user", "", "End-user JWT to invoke the agent as")
+	cmd.Flags().StringVar(&opts.UserID, "user-id", "", "End-user ID to invoke the agent as")
+	cmd.Flags().BoolVar(&opts.Wait, "wait", false, "Wait for the run to finish, through approval and consent pauses")
+	cmd.Flags().DurationVar(&opts.WaitTimeout, "wait-timeout", 10*time.Minute, "Maximum time to wait with --wait")
+	cmd.Flags().DurationVar(&opts.Interval, "interval", 2*time.Second, "Polling interval with --wait")
+	return cmd
+}
+
+// buildAgentInvokePayload assembles the request body from --payload,
+// --message, and --conversation. Only one of them may read stdin.
+func buildAgentInvokePayload(opts agentInvokeOptions, stdin io.Reader) (map[string]any, error) {
+	if opts.Payload == "-" && opts.Message == "-" {
+		return nil, fmt.Errorf("--payload and --message cannot both read from stdin")
+	}
+	payload := map[string]any{}
+	if source := strings.TrimSpace(opts.Payload); source != "" {
+		var (
+			data []byte
+			err  error
+		)
+		if source == "-" {
+			data, err = io.ReadAll(stdin)
+		} else {
+			data, err = os.ReadFile(source)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read payload: %w", err)
+		}
+		if err := decodeStructuredData(data, filepath.Ext(source), &payload); err != nil {
+			return nil, fmt.Errorf("failed to decode payload: %w", err)
+		}
+	}
+
+	message := opts.Message
+	if message == "-" {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read message: %w", err)
+		}
+		message = string(data)
+	}
+	if message = strings.TrimSpace(message); message != "" {
+		payload["message"] = message
+	}
+	if conversationID := strings.TrimSpace(opts.ConversationID); conversationID != "" {
+		payload["conversation_id"] = conversationID
+	}
+	if len(payload) == 0 || (opts.Payload == "" && payload["message"] == nil) {
+		return nil, fmt.Errorf("pass --message or --payload")
+	}
+	return payload, nil
+}
+
+// invokeAgent posts payload to the agent and interprets the reply. When the
+// response does not name its run, the run is looked up by agent, user,
+// conversation, and message.
+func invokeAgent(c *client.Client, agent string, payload map[string]any, userID string) (*agentInvokeResult, error) {
+	startedAt := time.Now().UTC()
+	data, err := c.Post(fmt.Sprintf("/agents/%s/invoke", agent), payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to invoke agent %q: %w", agent, err)
+	}
+	result, err := parseAgentInvokeResponse(agent, data)
+	if err != nil {
+		return nil, err
+	}
+	if result.ConversationID == "" {
+		result.ConversationID, _ = payload["conversation_id"].(string)
+	}
+	if result.RunID == "" {
+		message, _ := payload["message"].(string)
+		run, err := findInvokedRun(c, agent, invokedRunMatch{ConversationID: result.ConversationID, UserID: userID, Message: message}, startedAt)
+		if err != nil {
+			result.runLookupErr = err
+		} else {
+			result.RunID = run.ID
+			result.Status = firstNonEmptyRunValue(result.Status, run.Status)
+		}
+	}
+	return result, nil
+}
+
+func parseAgentInvokeResponse(agent string, data []byte) (*agentInvokeResult, error) {
+	response := map[string]any{}
+	if len(strings.TrimSpace(string(data))) > 0 {
+		if err := json.Unmarshal(data, &response); err != nil {
+			return nil, fmt.Errorf("failed to parse invoke response: %w", err)
+		}
+	}
+	result := &agentInvokeResult{
+		Agent:          agent,
+		RunID:          firstNonEmptyRunValue(dataString(response, "run_id"), dataString(response, "runId")),
+		ConversationID: firstNonEmptyRunValue(dataString(response, "conversation_id"), dataString(response, "conversationId")),
+		Status:         strings.ToUpper(dataString(response, "status")),
+		Reply:          firstNonEmptyRunValue(dataString(response, "response"), dataString(response, "output"), dataString(response, "message"), dataString(response, "content")),
+		Response:       response,
+	}
+	if detail, ok := response["approval_required"].(map[string]any); ok {
+		result.Approval = agentInvokePauseFrom(detail)
+		result.Status = firstNonEmptyRunValue(result.Status, "PAUSED_APPROVAL")
+	}
+	if detail, ok := response["consent_required"].(map[string]any); ok {
+		result.Consent = agentInvokePauseFrom(detail)
+		result.Status = firstNonEmptyRunValue(result.Status, "PAUSED_CONSENT")
+	}
+	return result, nil
+}
+
+func agentInvokePauseFrom(detail map[string]any) *agentInvokePause {
+	return &agentInvokePause{
+		ActionID:         firstNonEmptyRunValue(dataString(detail, "action_id"), dataString(detail, "actionId"), dataString(detail, "resume_id"), dataString(detail, "resumeId")),
+		Tool:             firstNonEmptyRunValue(dataString(detail, "tool"), dataString(detail, "tool_id")),
+		Message:          dataString(detail, "message"),
+		AuthorizationURL: firstNonEmptyRunValue(dataString(detail, "authorization_url"), dataString(detail, "authorizationUrl")),
+	}
+}
+
+// invokedRunMatch is what is known about the run an invocation created.
+type invokedRunMatch struct {
+	ConversationID string
+	UserID         string
+	Message        string
+}
+
+// findInvokedRun returns the run of the agent created since the invocation
+// started (allowing for clock skew) that matches the user, conversation, and
+// first user message. Other callers may invoke the same agent at the same
+// time, so it fails rather than guess when no run or several runs match.
+func findInvokedRun(c runAPIClient, agent string, match invokedRunMatch, startedAt time.Time) (*cliRun, error) {
+	runs, _, err := fetchRuns(c, runFilters{
+		AgentID:        agent,
+		UserID:         match.UserID,
+		ConversationID: match.ConversationID,
+		Since:          startedAt.Add(-5 * time.Second),
+		Limit:          20,
+	})
+	if err != nil {
+		return nil, err
+	}
+	message := strings.TrimSpace(match.Message)
+	var candidates []cliRun
+	for _, run := range runs {
+		if message != "" {
+			events, err := fetchRunEvents(c, run.ID, 0)
+			if err != nil {
+				return nil, err
+			}
+			if firstUserMessage(events) != message {
+				continue
+			}
+		}
+		candidates = append(candidates, run)
+	}
+	switch len(candidates) {
+	case 0:
+		return nil, fmt.Errorf("no run of agent %q matches this invocation", agent)
+	case 1:
+		return &candidates[0], nil
+	default:
+		ids := make([]string, 0, len(candidates))
+		for _, run := range candidates {
+			ids = append(ids, run.ID)
+		}
+		return nil, fmt.Errorf("%d runs of agent %q match this invocation (%s); pass --conversation or --user-id to tell them apart", len(candidates), agent, strings.Join(ids, ", "))
+	}
+}
+
+func firstUserMessage(events []cliRunEvent) string {
+	for _, event := range sortedRunEvents(events) {
+		if event.Type == "USER_MESSAGE" {
+			return strings.TrimSpace(firstNonEmptyRunValue(dataString(event.Data, "content"), dataString(event.Data, "message")))
+		}
+	}
+	return ""
+}
+
+// waitForInvokedRun follows the run behind an invocation until it finishes,
+// reporting pauses as they happen, then prints the final reply.
+func waitForInvokedRun(ctx context.Context, c *client.Client, result *agentInvokeResult, opts agentInvokeOptions) error {
+	if result.RunID == "" {
+		if err := printAgentInvokeResult(result); err != nil {
+			return err
+		}
+		if result.runLookupErr != nil {
+			return fmt.Errorf("agent %q did not report a run ID and the run could not be identified: %w", result.Agent, result.runLookupErr)
+		}
+		return fmt.Errorf("agent %q did not report a run ID, so there is no run to wait for", result.Agent)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, opts.WaitTimeout)
+	defer cancel()
+	lastStatus := ""
+	runs, err := waitForRuns(waitCtx, opts.Interval, []string{result.RunID}, runWaitUntilCompleted, func(ctx context.Context, id string) (*cliRun, error) {
+		run, err := fetchRun(c.WithContext(ctx), id)
+		if err != nil {
+			return nil, err
+		}
+		if run.Status != lastStatus && isBlockedRunStatus(run.Status) && !isJSONOutput() {
+			fmt.Fprintf(os.Stderr, "Run %s %s; waiting. Approve with: runagents runs actions approve %s\n", run.ID, describeRunWaitStatus(*run), run.ID)
+		}
+		lastStatus = run.Status
+		return run, nil
+	})
+	if err != nil {
+		if errors.Is(waitCtx.Err(), context.DeadlineExceeded) {
+			return &exitCodeError{code: exitCodeWaitTimeout, err: runWaitTimeoutError([]string{result.RunID}, runs, opts.WaitTimeout)}
+		}
+		return err
+	}
+
+	run := runs[0]
+	result.Status = run.Status
+	result.ConversationID = firstNonEmptyRunValue(result.ConversationID, run.ConversationID)
+	if events, err := fetchRunEvents(c, run.ID, 0); err == nil {
+		if reply := lastAgentMessage(events); reply != "" {
+			result.Reply = reply
+		}
+	}
+	if run.Status == "COMPLETED" {
+		result.Approval, result.Consent = nil, nil
+	}
+	if err := printAgentInvokeResult(result); err != nil {
+		return err
+	}
+	return runWaitOutcome(runs)
+}
+
+func lastAgentMessage(events []cliRunEvent) string {
+	events = sortedRunEvents(events)
+	for i := len(events) - 1; i >= 0; i-- {
+		if events[i].Type == "AGENT_MESSAGE" {
+			return firstNonEmptyRunValue(dataString(events[i].Data, "content"), dataString(events[i].Data, "message"))
+		}
+	}
+	return ""
+}
+
+func printAgentInvokeResult(result *agentInvokeResult) error {
+	if isJSONOutput() {
+		return printJSONValue(result)
+	}
+	if result.Reply != "" {
+		fmt.Println(strings.TrimSpace(result.Reply))
+		fmt.Println()
+	}
+	if result.Approval != nil {
+		fmt.Printf("Approval required for %s", emptyFallback(result.Approval.Tool, "a tool call"))
+		if result.Approval.ActionID != "" {
+			fmt.Printf(" (action %s)", result.Approval.ActionID)
+		}
+		fmt.Println(".")
+		if result.RunID != "" {
+			fmt.Printf("Approve with: %s\n", strings.TrimSpace("runagents runs actions approve "+result.RunID+" "+result.Approval.ActionID))
+		}
+		fmt.Println()
+	}
+	if result.Consent != nil {
+		fmt.Printf("Consent required for %s.\n", emptyFallback(result.Consent.Tool, "a tool"))
+		if result.Consent.AuthorizationURL != "" {
+			fmt.Printf("Authorize at: %s\n", result.Consent.AuthorizationURL)
+		}
+		fmt.Println()
+	}
+	fmt.Printf("Run:          %s\n", emptyFallback(result.RunID, "(not reported)"))
+	if result.ConversationID != "" {
+		fmt.Printf("Conversation: %s\n", result.ConversationID)
+	}
+	if result.Status != "" {
+		fmt.Printf("Status:       %s\n", result.Status)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/runagents/runagents/cli/internal/client"
+)
+
+func TestBuildAgentInvokePayloadMergesFlagsIntoStdinPayload(t *testing.T) {
+	stdin := strings.NewReader(`{"message": "from payload", "document_id": "doc-7"}`)
+	payload, err := buildAgentInvokePayload(agentInvokeOptions{Payload: "-", Message: "from flag", ConversationID: "conv-1"}, stdin)
+	if err != nil {
+		t.Fatalf("buildAgentInvokePayload: %v", err)
+	}
+	if payload["message"] != "from flag" || payload["conversation_id"] != "conv-1" || payload["document_id"] != "doc-7" {
+		t.Fatalf("unexpected payload: %v", payload)
+	}
+}
+
+func TestBuildAgentInvokePayloadReadsMessageFromStdin(t *testing.T) {
+	payload, err := buildAgentInvokePayload(agentInvokeOptions{Message: "-"}, strings.NewReader("  hello\n"))
+	if err != nil {
+		t.Fatalf("buildAgentInvokePayload: %v", err)
+	}
+	if payload["message"] != "hello" {
+		t.Fatalf("unexpected payload: %v", payload)
+	}
+	if _, err := buildAgentInvokePayload(agentInvokeOptions{ConversationID: "conv-1"}, strings.NewReader("")); err == nil {
+		t.Fatalf("expected an error without a message or payload")
+	}
+}
+
+func TestParseAgentInvokeResponseReportsApprovalPause(t *testing.T) {
+	data := []byte(`{"response": "Tool 'stripe' requires approval before it can be used.", "run_id": "run-9", "approval_required": {"action_id": "act-1", "tool": "stripe"}}`)
+	result, err := parseAgentInvokeResponse("billing", data)
+	if err != nil {
+		t.Fatalf("parseAgentInvokeResponse: %v", err)
+	}
+	if result.RunID != "run-9" || result.Status != "PAUSED_APPROVAL" {
+		t.Fatalf("unexpected result: %+v (%v)", result, result.runLookupErr)
+	}
+	if result.Approval == nil || result.Approval.ActionID != "act-1" || result.Approval.Tool != "stripe" {
+		t.Fatalf("unexpected approval: %+v", result.Approval)
+	}
+}
+
+func TestInvokeAgentFindsRunWhenResponseOmitsIt(t *testing.T) {
+	var invoked map[string]any
+	var endUser string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/agents/billing/invoke":
+			endUser = r.Header.Get("X-End-User-ID")
+			_ = json.NewDecoder(r.Body).Decode(&invoked)
+			_, _ = w.Write([]byte(`{"response": "You paid $42."}`))
+		case "/api/v1/runs":
+			if r.URL.Query().Get("conversation_id") != "conv-1" {
+				t.Errorf("expected conversation filter, got %q", r.URL.RawQuery)
+			}
+			old := cliRun{ID: "run-old", AgentID: "billing", UserID: "alice@example.com", ConversationID: "conv-1", CreatedAt: time.Now().Add(-time.Hour)}
+			current := cliRun{ID: "run-new", AgentID: "billing", UserID: "alice@example.com", ConversationID: "conv-1", Status: "COMPLETED", CreatedAt: time.Now()}
+			_ = json.NewEncoder(w).Encode([]cliRun{old, current})
+		case "/api/v1/runs/run-new/events":
+			_ = json.NewEncoder(w).Encode([]cliRunEvent{{Seq: 1, Type: "USER_MESSAGE", Data: map[string]any{"content": "What did I pay?"}}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c := client.NewClient(server.URL, "ra_ws_test").WithEndUser("", "alice@example.com")
+	result, err := invokeAgent(c, "billing", map[string]any{"message": "What did I pay?", "conversation_id": "conv-1"}, "alice@example.com")
+	if err != nil {
+		t.Fatalf("invokeAgent: %v", err)
+	}
+	if invoked["message"] != "What did I pay?" || endUser != "alice@example.com" {
+		t.Fatalf("unexpected request: %v (end user %q)", invoked, endUser)
+	}
+	if result.Reply != "You paid $42." || result.RunID != "run-new" || result.Status != "COMPLETED" || result.ConversationID != "conv-1" {
+		t.Fatalf("unexpected result: %+v (%v)", result, result.runLookupErr)
+	}
+}
+
+func TestInvokeAgentDoesNotFollowAnotherCallersRun(t *testing.T) {
+	runs := []cliRun{
+		{ID: "run-bob", AgentID: "billing", UserID: "bob@example.com", CreatedAt: time.Now()},
+		{ID: "run-alice-1", AgentID: "billing", UserID: "alice@example.com", CreatedAt: time.Now()},
+		{ID: "run-alice-2", AgentID: "billing", UserID: "alice@example.com", CreatedAt: time.Now()},
+	}
+	messages := map[string]string{"run-bob": "Refund it", "run-alice-1": "What did I pay?", "run-alice-2": "Cancel my plan"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v1/agents/billing/invoke":
+			_, _ = w.Write([]byte(`{"response": "Done."}`))
+		case r.URL.Path == "/api/v1/runs":
+			_ = json.NewEncoder(w).Encode(runs)
+		case strings.HasSuffix(r.URL.Path, "/events"):
+			id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/v1/runs/"), "/events")
+			_ = json.NewEncoder(w).Encode([]cliRunEvent{{Seq: 1, Type: "USER_MESSAGE", Data: map[string]any{"content": messages[id]}}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c := client.NewClient(server.URL, "ra_ws_test")
+	result, err := invokeAgent(c, "billing", map[string]any{"message": "Refund it"}, "alice@example.com")
+	if err != nil {
+		t.Fatalf("invokeAgent: %v", err)
+	}
+	if result.RunID != "" || result.runLookupErr == nil {
+		t.Fatalf("expected no run for alice's message, got %+v", result)
+	}
+	err = waitForInvokedRun(context.Background(), c, result, agentInvokeOptions{WaitTimeout: time.Second, Interval: time.Millisecond})
+	if err == nil || !strings.Contains(err.Error(), "could not be identified") {
+		t.Fatalf("expected --wait to fail, got %v", err)
+	}
+
+	if result, _ = invokeAgent(c, "billing", map[string]any{"message": "What did I pay?"}, "alice@example.com"); result.RunID != "run-alice-1" {
+		t.Fatalf("expected alice's matching run, got %+v", result)
+	}
+
+	messages["run-alice-2"] = "What did I pay?"
+	result, _ = invokeAgent(c, "billing", map[string]any{"message": "What did I pay?"}, "alice@example.com")
+	if result.RunID != "" || result.runLookupErr == nil || !strings.Contains(result.runLookupErr.Error(), "2 runs") {
+		t.Fatalf("expected ambiguous runs to be refused, got %+v (%v)", result, result.runLookupErr)
+	}
+}
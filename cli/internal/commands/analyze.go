@@ -39,7 +39,7 @@ Example:
 				"files": sourceFiles,
 			}
 
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
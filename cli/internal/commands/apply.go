@@ -0,0 +1,661 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/runagents/runagents/cli/internal/client"
+	"github.com/spf13/cobra"
+)
+
+const (
+	workspaceKindModelProvider     = "ModelProvider"
+	workspaceKindTool              = "Tool"
+	workspaceKindApprovalConnector = "ApprovalConnector"
+	workspaceKindIdentityProvider  = "IdentityProvider"
+	workspaceKindPolicy            = "Policy"
+	workspaceKindAgent             = "Agent"
+)
+
+// workspaceKindOrder is the dependency order used when converging a bundle:
+// model providers and tools first, then identity, connectors and policies,
+// and agents last because their config references everything else.
+var workspaceKindOrder = []string{
+	workspaceKindModelProvider,
+	workspaceKindTool,
+	workspaceKindApprovalConnector,
+	workspaceKindIdentityProvider,
+	workspaceKindPolicy,
+	workspaceKindAgent,
+}
+
+var workspaceKindAliases = map[string]string{
+	"modelprovider":      workspaceKindModelProvider,
+	"modelproviders":     workspaceKindModelProvider,
+	"model":              workspaceKindModelProvider,
+	"models":             workspaceKindModelProvider,
+	"tool":               workspaceKindTool,
+	"tools":              workspaceKindTool,
+	"approvalconnector":  workspaceKindApprovalConnector,
+	"approvalconnectors": workspaceKindApprovalConnector,
+	"identityprovider":   workspaceKindIdentityProvider,
+	"identityproviders":  workspaceKindIdentityProvider,
+	"policy":             workspaceKindPolicy,
+	"policies":           workspaceKindPolicy,
+	"agent":              workspaceKindAgent,
+	"agents":             workspaceKindAgent,
+	"agentconfig":        workspaceKindAgent,
+}
+
+// workspaceWriteOnlyFields are accepted on write but never returned by the
+// API, so they are ignored when comparing local and live state. A resource
+// that declares one is always sent, since its live value cannot be checked.
+var workspaceWriteOnlyFields = map[string][]string{
+	workspaceKindTool: {"credentials"},
+}
+
+// workspaceEnvReference matches values that are exactly ${VAR}; apply
+// replaces them with the environment value, as written by 'runagents export'.
+var workspaceEnvReference = regexp.MustCompile(`^\$\{([A-Za-z_][A-Za-z0-9_]*)\}$`)
+
+type workspaceDocument struct {
+	Kind   string
+	Source string
+	Body   map[string]any
+}
+
+type workspaceResource struct {
+	Kind    string
+	Name    string
+	Source  string
+	Payload any
+}
+
+type workspaceApplyResult struct {
+	Kind        string `json:"kind"`
+	Name        string `json:"name"`
+	Status      string `json:"status"`
+	ResourceRef string `json:"resource_ref,omitempty"`
+	Source      string `json:"source,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+type workspaceApplyResponse struct {
+	Applied        bool                   `json:"applied"`
+	CreatedCount   int                    `json:"created_count"`
+	UpdatedCount   int                    `json:"updated_count"`
+	UnchangedCount int                    `json:"unchanged_count"`
+	FailedCount    int                    `json:"failed_count"`
+	Results        []workspaceApplyResult `json:"results"`
+}
+
+type workspaceAPIClient interface {
+	Get(string) ([]byte, error)
+	listAPIClient
+	Post(string, interface{}) ([]byte, error)
+	Put(string, interface{}) ([]byte, error)
+	Patch(string, interface{}) ([]byte, error)
+}
+
+func newApplyCmd() *cobra.Command {
+	var paths []string
+	cmd := &cobra.Command{
+		Use:   "apply -f <file|dir>",
+		Short: "Converge the workspace to a declarative bundle of resources",
+		Long: `Apply a bundle of workspace resources from YAML or JSON files.
+
+Each document declares a kind (ModelProvider, Tool, ApprovalConnector,
+IdentityProvider, Policy, or Agent) alongside the same fields accepted by the
+resource-specific apply and create commands. Directories are read recursively
+and multi-document YAML files are supported. Resources are applied in
+dependency order and unchanged resources are skipped.
+
+Examples:
+  runagents apply -f workspace/
+  runagents apply -f policies.yaml -f tools.yaml`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(paths) == 0 {
+				return fmt.Errorf("--file is required")
+			}
+			docs, err := loadWorkspaceDocuments(paths)
+			if err != nil {
+				return err
+			}
+			resources, err := buildWorkspaceResources(docs)
+			if err != nil {
+				return err
+			}
+			c, err := newAPIClient(cmd.Context())
+			if err != nil {
+				return err
+			}
+			resp := applyWorkspaceResources(c, resources)
+			if isJSONOutput() {
+				if err := printJSONValue(resp); err != nil {
+					return err
+				}
+			} else {
+				printWorkspaceApplyResponse(resp)
+			}
+			if resp.FailedCount > 0 {
+				return fmt.Errorf("%d of %d resources failed to apply", resp.FailedCount, len(resp.Results))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringArrayVarP(&paths, "file", "f", nil, "Resource file or directory (repeatable)")
+	return cmd
+}
+
+// loadWorkspaceDocuments reads every resource document from the given files
+// and directories. Directory entries are visited in lexical order.
+func loadWorkspaceDocuments(paths []string) ([]workspaceDocument, error) {
+	var files []string
+	for _, input := range paths {
+		trimmed := strings.TrimSpace(input)
+		if trimmed == "" {
+			return nil, fmt.Errorf("file path cannot be empty")
+		}
+		info, err := os.Stat(trimmed)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %q: %w", trimmed, err)
+		}
+		if !info.IsDir() {
+			files = append(files, trimmed)
+			continue
+		}
+		var found []string
+		err = filepath.WalkDir(trimmed, func(path string, d os.DirEntry, walkErr error) error {
+			if walkErr != nil {
+				return walkErr
+			}
+			if d.IsDir() {
+				if path != trimmed && strings.HasPrefix(d.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			switch strings.ToLower(filepath.Ext(path)) {
+			case ".yaml", ".yml", ".json":
+				found = append(found, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk %q: %w", trimmed, err)
+		}
+		sort.Strings(found)
+		files = append(files, found...)
+	}
+
+	var docs []workspaceDocument
+	for _, path := range files {
+		items, err := decodeStructuredDocuments(path)
+		if err != nil {
+			return nil, err
+		}
+		for i, item := range items {
+			source := path
+			if len(items) > 1 {
+				source = fmt.Sprintf("%s#%d", path, i+1)
+			}
+			kind, err := normalizeWorkspaceKind(stringField(item, "kind"))
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", source, err)
+			}
+			body := make(map[string]any, len(item))
+			for key, value := range item {
+				if key == "kind" {
+					continue
+				}
+				expanded, err := expandWorkspaceEnvReferences(value)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", source, err)
+				}
+				body[key] = expanded
+			}
+			docs = append(docs, workspaceDocument{Kind: kind, Source: source, Body: body})
+		}
+	}
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("no resource documents found")
+	}
+	return docs, nil
+}
+
+func expandWorkspaceEnvReferences(value any) (any, error) {
+	switch typed := value.(type) {
+	case map[string]any:
+		for key, nested := range typed {
+			expanded, err := expandWorkspaceEnvReferences(nested)
+			if err != nil {
+				return nil, err
+			}
+			typed[key] = expanded
+		}
+		return typed, nil
+	case []any:
+		for i, item := range typed {
+			expanded, err := expandWorkspaceEnvReferences(item)
+			if err != nil {
+				return nil, err
+			}
+			typed[i] = expanded
+		}
+		return typed, nil
+	case string:
+		match := workspaceEnvReference.FindStringSubmatch(typed)
+		if match == nil {
+			return typed, nil
+		}
+		resolved, ok := os.LookupEnv(match[1])
+		if !ok {
+			return nil, fmt.Errorf("environment variable %s is not set", match[1])
+		}
+		return resolved, nil
+	default:
+		return value, nil
+	}
+}
+
+func normalizeWorkspaceKind(kind string) (string, error) {
+	trimmed := strings.TrimSpace(kind)
+	if trimmed == "" {
+		return "", fmt.Errorf("document is missing kind")
+	}
+	key := strings.ToLower(strings.NewReplacer("-", "", "_", "", " ", "").Replace(trimmed))
+	if normalized, ok := workspaceKindAliases[key]; ok {
+		return normalized, nil
+	}
+	return "", fmt.Errorf("unsupported kind %q; valid kinds: %s", trimmed, strings.Join(workspaceKindOrder, ", "))
+}
+
+func workspaceKindRank(kind string) int {
+	for i, candidate := range workspaceKindOrder {
+		if candidate == kind {
+			return i
+		}
+	}
+	return len(workspaceKindOrder)
+}
+
+// buildWorkspaceResources validates each document into the request payload
+// its endpoint expects and returns them in dependency order.
+func buildWorkspaceResources(docs []workspaceDocument) ([]workspaceResource, error) {
+	resources := make([]workspaceResource, 0, len(docs))
+	seen := make(map[string]string, len(docs))
+	for _, doc := range docs {
+		resource, err := buildWorkspaceResource(doc)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", doc.Source, err)
+		}
+		key := resource.Kind + "/" + resource.Name
+		if previous, exists := seen[key]; exists {
+			return nil, fmt.Errorf("%s: %s is already declared in %s", doc.Source, key, previous)
+		}
+		seen[key] = doc.Source
+		resources = append(resources, resource)
+	}
+	sort.SliceStable(resources, func(i, j int) bool {
+		return workspaceKindRank(resources[i].Kind) < workspaceKindRank(resources[j].Kind)
+	})
+	return resources, nil
+}
+
+func buildWorkspaceResource(doc workspaceDocument) (workspaceResource, error) {
+	resource := workspaceResource{Kind: doc.Kind, Source: doc.Source}
+	switch doc.Kind {
+	case workspaceKindPolicy:
+		req, err := policyApplyRequestFromDocument(doc.Body, "")
+		if err != nil {
+			return workspaceResource{}, err
+		}
+		resource.Name = req.Name
+		resource.Payload = req
+	case workspaceKindIdentityProvider:
+		req, err := identityProviderApplyRequestFromDocument(doc.Body, "")
+		if err != nil {
+			return workspaceResource{}, err
+		}
+		resource.Name = req.Name
+		resource.Payload = req
+	case workspaceKindApprovalConnector:
+		req, err := approvalConnectorApplyRequestFromDocument(doc.Body)
+		if err != nil {
+			return workspaceResource{}, err
+		}
+		resource.Name = firstNonEmpty(req.Name, req.ID)
+		resource.Payload = req
+	case workspaceKindTool, workspaceKindModelProvider:
+		name := strings.TrimSpace(stringField(doc.Body, "name"))
+		if name == "" {
+			return workspaceResource{}, fmt.Errorf("%s name is required", doc.Kind)
+		}
+		if _, ok := doc.Body["spec"].(map[string]any); !ok {
+			return workspaceResource{}, fmt.Errorf("%s %q must include a spec object", doc.Kind, name)
+		}
+		resource.Name = name
+		resource.Payload = doc.Body
+	case workspaceKindAgent:
+		name := strings.TrimSpace(firstNonEmpty(stringField(doc.Body, "name"), stringField(doc.Body, "agent_name")))
+		if name == "" {
+			return workspaceResource{}, fmt.Errorf("agent name is required")
+		}
+		spec, ok := doc.Body["spec"].(map[string]any)
+		if !ok {
+			spec = make(map[string]any, len(doc.Body))
+			for key, value := range doc.Body {
+				if key != "name" && key != "agent_name" {
+					spec[key] = value
+				}
+			}
+		}
+		if len(spec) == 0 {
+			return workspaceResource{}, fmt.Errorf("agent %q does not declare any configuration", name)
+		}
+		resource.Name = name
+		resource.Payload = spec
+	default:
+		return workspaceResource{}, fmt.Errorf("unsupported kind %q", doc.Kind)
+	}
+	return resource, nil
+}
+
+func applyWorkspaceResources(c workspaceAPIClient, resources []workspaceResource) workspaceApplyResponse {
+	resp := workspaceApplyResponse{Results: make([]workspaceApplyResult, 0, len(resources))}
+	for _, resource := range resources {
+		result := applyWorkspaceResource(c, resource)
+		switch result.Status {
+		case "created":
+			resp.CreatedCount++
+		case "updated":
+			resp.UpdatedCount++
+		case "unchanged":
+			resp.UnchangedCount++
+		default:
+			resp.FailedCount++
+		}
+		resp.Results = append(resp.Results, result)
+	}
+	resp.Applied = resp.FailedCount == 0
+	return resp
+}
+
+func applyWorkspaceResource(c workspaceAPIClient, resource workspaceResource) workspaceApplyResult {
+	result := workspaceApplyResult{
+		Kind:        resource.Kind,
+		Name:        resource.Name,
+		Source:      resource.Source,
+		ResourceRef: workspaceResourcePath(resource.Kind, resource.Name),
+	}
+	fail := func(err error) workspaceApplyResult {
+		result.Status = "failed"
+		result.Error = err.Error()
+		return result
+	}
+
+	live, found, err := fetchLiveWorkspaceResource(c, resource)
+	if err != nil {
+		return fail(err)
+	}
+	if found && resource.Kind == workspaceKindApprovalConnector {
+		result.ResourceRef = "/approval-connectors/" + stringField(live, "id")
+	}
+	if found {
+		matches, err := workspaceResourceMatches(resource, live)
+		if err != nil {
+			return fail(err)
+		}
+		writeOnly, err := declaredWorkspaceWriteOnlyFields(resource)
+		if err != nil {
+			return fail(err)
+		}
+		if matches && len(writeOnly) == 0 {
+			result.Status = "unchanged"
+			return result
+		}
+	}
+
+	switch resource.Kind {
+	case workspaceKindPolicy:
+		if found {
+			_, err = c.Put(result.ResourceRef, resource.Payload)
+		} else {
+			_, err = c.Post("/policies", resource.Payload)
+		}
+	case workspaceKindIdentityProvider:
+		_, err = c.Post("/identity-providers", resource.Payload)
+	case workspaceKindTool:
+		_, err = c.Post("/tools", resource.Payload)
+	case workspaceKindModelProvider:
+		_, err = c.Post("/model-providers", resource.Payload)
+	case workspaceKindApprovalConnector:
+		req := resource.Payload.(cliApprovalConnectorApplyRequest)
+		if found {
+			_, err = c.Patch(result.ResourceRef, buildApprovalConnectorPatch(req))
+		} else {
+			createReq, createErr := buildApprovalConnectorCreate(req)
+			if createErr != nil {
+				return fail(createErr)
+			}
+			_, err = c.Post("/approval-connectors", createReq)
+		}
+	case workspaceKindAgent:
+		if !found {
+			return fail(fmt.Errorf("agent %q is not deployed; deploy it before applying its configuration", resource.Name))
+		}
+		_, err = c.Put(result.ResourceRef, resource.Payload)
+	default:
+		err = fmt.Errorf("unsupported kind %q", resource.Kind)
+	}
+	if err != nil {
+		return fail(err)
+	}
+	if found {
+		result.Status = "updated"
+	} else {
+		result.Status = "created"
+	}
+	return result
+}
+
+func workspaceResourcePath(kind, name string) string {
+	switch kind {
+	case workspaceKindPolicy:
+		return "/policies/" + name
+	case workspaceKindIdentityProvider:
+		return "/identity-providers/" + name
+	case workspaceKindTool:
+		return "/tools/" + name
+	case workspaceKindModelProvider:
+		return "/model-providers/" + name
+	case workspaceKindApprovalConnector:
+		return "/approval-connectors/" + name
+	case workspaceKindAgent:
+		return "/agents/" + name + "/config"
+	default:
+		return ""
+	}
+}
+
+// fetchLiveWorkspaceResource returns the live object for a resource as a
+// generic JSON map, or found=false when the workspace does not have it yet.
+func fetchLiveWorkspaceResource(c workspaceAPIClient, resource workspaceResource) (map[string]any, bool, error) {
+	if resource.Kind == workspaceKindApprovalConnector {
+		connectors, err := fetchApprovalConnectors(c)
+		if err != nil {
+			return nil, false, err
+		}
+		target, err := resolveApprovalConnectorTarget(connectors, resource.Payload.(cliApprovalConnectorApplyRequest))
+		if err != nil || target == nil {
+			return nil, false, err
+		}
+		var live map[string]any
+		if err := decodeStructuredValue(target, &live); err != nil {
+			return nil, false, fmt.Errorf("failed to normalize approval connector: %w", err)
+		}
+		return live, true, nil
+	}
+
+	data, err := c.Get(workspaceResourcePath(resource.Kind, resource.Name))
+	if err != nil {
+		if client.IsNotFound(err) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	var live map[string]any
+	if err := json.Unmarshal(data, &live); err != nil {
+		return nil, false, fmt.Errorf("failed to parse %s %q: %w", resource.Kind, resource.Name, err)
+	}
+	return live, true, nil
+}
+
+// workspaceResourceMatches reports whether every field declared locally
+// already has the same value in the live object. Fields the bundle does not
+// declare are left alone by apply, so they never count as a difference.
+func workspaceResourceMatches(resource workspaceResource, live map[string]any) (bool, error) {
+	desired, err := normalizedWorkspacePayload(resource)
+	if err != nil {
+		return false, err
+	}
+	return reflect.DeepEqual(desired, projectLiveWorkspaceDocument(desired, live)), nil
+}
+
+// projectLiveWorkspaceDocument keeps the parts of a live document that the
+// local one declares, so server defaults for omitted fields do not count as
+// differences. apply and diff both compare against it.
+func projectLiveWorkspaceDocument(desired, live any) any {
+	return pruneEmptyValues(projectDocument(desired, live))
+}
+
+func normalizedWorkspacePayload(resource workspaceResource) (any, error) {
+	var desired any
+	if err := decodeStructuredValue(resource.Payload, &desired); err != nil {
+		return nil, fmt.Errorf("failed to normalize %s %q: %w", resource.Kind, resource.Name, err)
+	}
+	if asMap, ok := desired.(map[string]any); ok {
+		for _, field := range workspaceWriteOnlyFields[resource.Kind] {
+			delete(asMap, field)
+		}
+	}
+	return pruneEmptyValues(desired), nil
+}
+
+// declaredWorkspaceWriteOnlyFields lists the write-only fields that a
+// resource sets to a non-empty value.
+func declaredWorkspaceWriteOnlyFields(resource workspaceResource) ([]string, error) {
+	var desired map[string]any
+	if err := decodeStructuredValue(resource.Payload, &desired); err != nil {
+		return nil, fmt.Errorf("failed to normalize %s %q: %w", resource.Kind, resource.Name, err)
+	}
+	var fields []string
+	for _, field := range workspaceWriteOnlyFields[resource.Kind] {
+		if !isEmptyDocumentValue(pruneEmptyValues(desired[field])) {
+			fields = append(fields, field)
+		}
+	}
+	return fields, nil
+}
+
+// projectDocument keeps only the parts of live that desired declares.
+func projectDocument(desired, live any) any {
+	switch typed := desired.(type) {
+	case map[string]any:
+		liveMap, ok := live.(map[string]any)
+		if !ok {
+			return live
+		}
+		out := make(map[string]any, len(typed))
+		for key, value := range typed {
+			if liveValue, exists := liveMap[key]; exists {
+				out[key] = projectDocument(value, liveValue)
+			}
+		}
+		return out
+	case []any:
+		liveItems, ok := live.([]any)
+		if !ok {
+			return live
+		}
+		out := make([]any, len(liveItems))
+		for i, item := range liveItems {
+			if i < len(typed) {
+				out[i] = projectDocument(typed[i], item)
+			} else {
+				out[i] = item
+			}
+		}
+		return out
+	default:
+		return live
+	}
+}
+
+// pruneEmptyValues drops nulls, empty strings and empty collections from
+// objects so omitted and zero-valued fields compare equal.
+func pruneEmptyValues(v any) any {
+	switch typed := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(typed))
+		for key, value := range typed {
+			pruned := pruneEmptyValues(value)
+			if isEmptyDocumentValue(pruned) {
+				continue
+			}
+			out[key] = pruned
+		}
+		return out
+	case []any:
+		out := make([]any, len(typed))
+		for i, item := range typed {
+			out[i] = pruneEmptyValues(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func isEmptyDocumentValue(v any) bool {
+	switch typed := v.(type) {
+	case nil:
+		return true
+	case string:
+		return typed == ""
+	case map[string]any:
+		return len(typed) == 0
+	case []any:
+		return len(typed) == 0
+	default:
+		return false
+	}
+}
+
+func printWorkspaceApplyResponse(resp workspaceApplyResponse) {
+	status := "FAILED"
+	if resp.Applied {
+		status = "APPLIED"
+	}
+	fmt.Printf("Status:       %s\n", status)
+	fmt.Printf("Created:      %d\n", resp.CreatedCount)
+	fmt.Printf("Updated:      %d\n", resp.UpdatedCount)
+	fmt.Printf("Unchanged:    %d\n", resp.UnchangedCount)
+	fmt.Printf("Failed:       %d\n", resp.FailedCount)
+	for _, result := range resp.Results {
+		fmt.Printf("- [%s] %s/%s\n", strings.ToUpper(result.Status), result.Kind, result.Name)
+		if result.ResourceRef != "" {
+			fmt.Printf("  resource: %s\n", result.ResourceRef)
+		}
+		if result.Error != "" {
+			fmt.Printf("  error: %s\n", result.Error)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package commands
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/runagents/runagents/cli/internal/client"
+)
+
+type fakeWorkspaceClient struct {
+	objects map[string]any
+	calls   []string
+}
+
+func (f *fakeWorkspaceClient) Get(path string) ([]byte, error) {
+	obj, ok := f.objects[path]
+	if !ok {
+		return nil, &client.APIError{StatusCode: http.StatusNotFound, Message: "not found"}
+	}
+	return json.Marshal(obj)
+}
+
+// Paginate serves a list stored as an array, or as an envelope whose
+// next_cursor names the next page, stored under path?cursor=<next_cursor>.
+func (f *fakeWorkspaceClient) Paginate(path string, query url.Values, fn func([]json.RawMessage) (bool, error)) error {
+	key := path
+	for {
+		data, err := f.Get(key)
+		if err != nil {
+			return err
+		}
+		var page struct {
+			Items      []json.RawMessage `json:"items"`
+			NextCursor string            `json:"next_cursor"`
+		}
+		if err := json.Unmarshal(data, &page.Items); err != nil {
+			if err := json.Unmarshal(data, &page); err != nil {
+				return err
+			}
+		}
+		more, err := fn(page.Items)
+		if err != nil || !more || page.NextCursor == "" {
+			return err
+		}
+		key = path + "?cursor=" + page.NextCursor
+	}
+}
+
+func (f *fakeWorkspaceClient) Post(path string, body interface{}) ([]byte, error) {
+	f.calls = append(f.calls, "POST "+path)
+	return []byte(`{}`), nil
+}
+
+func (f *fakeWorkspaceClient) Put(path string, body interface{}) ([]byte, error) {
+	f.calls = append(f.calls, "PUT "+path)
+	return []byte(`{}`), nil
+}
+
+func (f *fakeWorkspaceClient) Patch(path string, body interface{}) ([]byte, error) {
+	f.calls = append(f.calls, "PATCH "+path)
+	return []byte(`{}`), nil
+}
+
+func TestLoadWorkspaceDocumentsOrdersByDependency(t *testing.T) {
+	dir := t.TempDir()
+	bundle := `kind: Agent
+name: billing-agent
+spec:
+  system_prompt: Help with billing.
+---
+kind: Policy
+name: billing-read
+spec:
+  policies:
+    - permission: allow
+      resource: https://billing.example.com/*
+      operations: [GET]
+---
+kind: Tool
+name: billing
+spec:
+  connection:
+    baseUrl: https://billing.example.com
+`
+	if err := os.WriteFile(filepath.Join(dir, "bundle.yaml"), []byte(bundle), 0o600); err != nil {
+		t.Fatalf("write bundle: %v", err)
+	}
+	provider := `{"kind":"model-provider","name":"openai","spec":{"provider":"openai"}}`
+	if err := os.WriteFile(filepath.Join(dir, "models.json"), []byte(provider), 0o600); err != nil {
+		t.Fatalf("write provider: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("ignored"), 0o600); err != nil {
+		t.Fatalf("write readme: %v", err)
+	}
+
+	docs, err := loadWorkspaceDocuments([]string{dir})
+	if err != nil {
+		t.Fatalf("loadWorkspaceDocuments returned error: %v", err)
+	}
+	resources, err := buildWorkspaceResources(docs)
+	if err != nil {
+		t.Fatalf("buildWorkspaceResources returned error: %v", err)
+	}
+
+	var got []string
+	for _, resource := range resources {
+		got = append(got, resource.Kind+"/"+resource.Name)
+	}
+	want := "ModelProvider/openai,Tool/billing,Policy/billing-read,Agent/billing-agent"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("expected order %q, got %q", want, strings.Join(got, ","))
+	}
+	if !strings.HasSuffix(resources[3].Source, "bundle.yaml#1") {
+		t.Fatalf("expected agent source to reference the first document, got %q", resources[3].Source)
+	}
+}
+
+func TestLoadWorkspaceDocumentsRejectsUnknownKind(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bundle.yaml")
+	if err := os.WriteFile(path, []byte("kind: Widget\nname: x\n"), 0o600); err != nil {
+		t.Fatalf("write bundle: %v", err)
+	}
+	_, err := loadWorkspaceDocuments([]string{path})
+	if err == nil || !strings.Contains(err.Error(), `unsupported kind "Widget"`) {
+		t.Fatalf("expected unsupported kind error, got %v", err)
+	}
+}
+
+func TestBuildWorkspaceResourcesRejectsDuplicates(t *testing.T) {
+	docs := []workspaceDocument{
+		{Kind: workspaceKindTool, Source: "a.yaml", Body: map[string]any{"name": "billing", "spec": map[string]any{}}},
+		{Kind: workspaceKindTool, Source: "b.yaml", Body: map[string]any{"name": "billing", "spec": map[string]any{}}},
+	}
+	_, err := buildWorkspaceResources(docs)
+	if err == nil || !strings.Contains(err.Error(), "already declared in a.yaml") {
+		t.Fatalf("expected duplicate error, got %v", err)
+	}
+}
+
+func TestApplyWorkspaceResourcesReportsCreatedUpdatedUnchanged(t *testing.T) {
+	client := &fakeWorkspaceClient{objects: map[string]any{
+		"/tools/billing": map[string]any{
+			"name":      "billing",
+			"namespace": "default",
+			"status":    "Ready",
+			"spec": map[string]any{
+				"connection": map[string]any{"baseUrl": "https://billing.example.com", "port": 443},
+			},
+		},
+		"/agents/billing-agent/config": map[string]any{
+			"agent_name":    "billing-agent",
+			"system_prompt": "Old prompt.",
+		},
+	}}
+	resources := []workspaceResource{
+		{
+			Kind: workspaceKindTool,
+			Name: "billing",
+			Payload: map[string]any{
+				"name": "billing",
+				"spec": map[string]any{
+					"connection": map[string]any{"baseUrl": "https://billing.example.com"},
+				},
+			},
+		},
+		{
+			Kind:    workspaceKindModelProvider,
+			Name:    "openai",
+			Payload: map[string]any{"name": "openai", "spec": map[string]any{"provider": "openai"}},
+		},
+		{
+			Kind:    workspaceKindAgent,
+			Name:    "billing-agent",
+			Payload: map[string]any{"system_prompt": "New prompt."},
+		},
+		{
+			Kind:    workspaceKindAgent,
+			Name:    "missing-agent",
+			Payload: map[string]any{"system_prompt": "Hello."},
+		},
+	}
+
+	resp := applyWorkspaceResources(client, resources)
+	if resp.Applied || resp.CreatedCount != 1 || resp.UpdatedCount != 1 || resp.UnchangedCount != 1 || resp.FailedCount != 1 {
+		t.Fatalf("unexpected counts: %#v", resp)
+	}
+	statuses := make([]string, 0, len(resp.Results))
+	for _, result := range resp.Results {
+		statuses = append(statuses, result.Status)
+	}
+	if got := strings.Join(statuses, ","); got != "unchanged,created,updated,failed" {
+		t.Fatalf("unexpected statuses %q", got)
+	}
+	if !strings.Contains(resp.Results[3].Error, "is not deployed") {
+		t.Fatalf("expected missing agent error, got %q", resp.Results[3].Error)
+	}
+	if got := strings.Join(client.calls, ","); got != "POST /model-providers,PUT /agents/billing-agent/config" {
+		t.Fatalf("unexpected API calls %q", got)
+	}
+}
+
+func TestApplyWorkspacePolicyUsesPutWhenPolicyExists(t *testing.T) {
+	client := &fakeWorkspaceClient{objects: map[string]any{
+		"/policies/billing-read": map[string]any{"name": "billing-read", "spec": map[string]any{}},
+	}}
+	req := cliPolicyApplyRequest{Name: "billing-read"}
+	req.Spec.Policies = []cliPolicyRule{{Permission: "allow", Resource: "https://billing.example.com/*"}}
+
+	result := applyWorkspaceResource(client, workspaceResource{Kind: workspaceKindPolicy, Name: "billing-read", Payload: req})
+	if result.Status != "updated" {
+		t.Fatalf("expected updated policy, got %#v", result)
+	}
+	if len(client.calls) != 1 || client.calls[0] != "PUT /policies/billing-read" {
+		t.Fatalf("unexpected API calls %v", client.calls)
+	}
+}
+
+func TestApplyWorkspaceToolWithCredentialsIsAlwaysSent(t *testing.T) {
+	client := &fakeWorkspaceClient{objects: map[string]any{
+		"/tools/billing": map[string]any{
+			"name": "billing",
+			"spec": map[string]any{
+				"connection": map[string]any{"baseUrl": "https://billing.example.com"},
+			},
+		},
+	}}
+	result := applyWorkspaceResource(client, workspaceResource{
+		Kind: workspaceKindTool,
+		Name: "billing",
+		Payload: map[string]any{
+			"name":        "billing",
+			"credentials": map[string]any{"api_key": "rotated"},
+			"spec": map[string]any{
+				"connection": map[string]any{"baseUrl": "https://billing.example.com"},
+			},
+		},
+	})
+	if result.Status != "updated" {
+		t.Fatalf("expected a tool with credentials to be sent, got %#v", result)
+	}
+	if len(client.calls) != 1 || client.calls[0] != "POST /tools" {
+		t.Fatalf("unexpected API calls %v", client.calls)
+	}
+}
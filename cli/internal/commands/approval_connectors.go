@@ -3,7 +3,6 @@ package commands
 import (
 	"encoding/json"
 	"fmt"
-	"net/url"
 	"sort"
 	"strconv"
 	"strings"
@@ -132,7 +131,7 @@ func newApprovalConnectorsListCmd() *cobra.Command {
 		Short: "List approval connectors in the current workspace",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
@@ -175,7 +174,7 @@ func newApprovalConnectorsGetCmd() *cobra.Command {
 		Short: "Get a single approval connector",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
@@ -211,7 +210,7 @@ func newApprovalConnectorsApplyCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
@@ -252,7 +251,7 @@ func newApprovalConnectorsDeleteCmd() *cobra.Command {
 		Short: "Delete an approval connector",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
@@ -275,7 +274,7 @@ func newApprovalConnectorsTestCmd() *cobra.Command {
 		Short: "Test an approval connector by replaying its current configuration",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
@@ -323,7 +322,7 @@ func newApprovalConnectorsDefaultsGetCmd() *cobra.Command {
 		Short: "Show default approval connector settings",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
@@ -374,7 +373,7 @@ func newApprovalConnectorsDefaultsSetCmd() *cobra.Command {
 			if !changed {
 				return fmt.Errorf("set at least one flag: --delivery-mode, --fallback-to-ui, or --timeout-seconds")
 			}
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
@@ -403,28 +402,31 @@ func newApprovalConnectorsDefaultsSetCmd() *cobra.Command {
 }
 
 func newApprovalConnectorsActivityCmd() *cobra.Command {
-	var limit int
+	var list listOptions
 	cmd := &cobra.Command{
 		Use:   "activity",
 		Short: "Show recent approval connector activity",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
-			query := approvalConnectorActivityQuery(limit)
-			data, err := c.GetWithQuery("/approval-connectors/activity", query)
+			items, truncated, err := fetchListItems(c, "/approval-connectors/activity", list.arrayQuery(), list.limitOrAll(), nil)
 			if err != nil {
 				return err
 			}
+			items = trimListItems(items, list.limitOrAll())
 			if isJSONOutput() {
-				fmt.Println(string(data))
-				return nil
+				return printJSONValue(items)
 			}
-			var events []cliApprovalConnectorActivity
-			if err := json.Unmarshal(data, &events); err != nil {
-				return fmt.Errorf("failed to parse response: %w", err)
+			events := make([]cliApprovalConnectorActivity, 0, len(items))
+			for _, item := range items {
+				var event cliApprovalConnectorActivity
+				if err := json.Unmarshal(item, &event); err != nil {
+					return fmt.Errorf("failed to parse response: %w", err)
+				}
+				events = append(events, event)
 			}
 			if len(events) == 0 {
 				fmt.Println("No approval connector activity found.")
@@ -442,30 +444,43 @@ func newApprovalConnectorsActivityCmd() *cobra.Command {
 				})
 			}
 			table.Render()
+			printListTruncatedHint(truncated, len(events), "activity events")
 			return nil
 		},
 	}
-	cmd.Flags().IntVar(&limit, "limit", 50, "Maximum number of activity events to return")
+	addListFlags(cmd, &list, "activity events", 50)
 	return cmd
 }
 
-func fetchApprovalConnectors(c interface{ Get(string) ([]byte, error) }) ([]cliApprovalConnector, error) {
-	data, err := c.Get("/approval-connectors")
+func fetchApprovalConnectors(c listAPIClient) ([]cliApprovalConnector, error) {
+	items, _, err := fetchListItems(c, "/approval-connectors", nil, 0, nil)
 	if err != nil {
 		return nil, err
 	}
-	var connectors []cliApprovalConnector
-	if err := json.Unmarshal(data, &connectors); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	connectors := make([]cliApprovalConnector, 0, len(items))
+	for _, item := range items {
+		var connector cliApprovalConnector
+		if err := json.Unmarshal(item, &connector); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+		connectors = append(connectors, connector)
 	}
 	return connectors, nil
 }
 
 func loadApprovalConnectorApplyRequest(path string) (cliApprovalConnectorApplyRequest, error) {
-	var req cliApprovalConnectorApplyRequest
-	if err := decodeStructuredFile(path, &req); err != nil {
+	var raw map[string]any
+	if err := decodeStructuredFile(path, &raw); err != nil {
 		return cliApprovalConnectorApplyRequest{}, err
 	}
+	return approvalConnectorApplyRequestFromDocument(raw)
+}
+
+func approvalConnectorApplyRequestFromDocument(raw map[string]any) (cliApprovalConnectorApplyRequest, error) {
+	var req cliApprovalConnectorApplyRequest
+	if err := decodeStructuredValue(raw, &req); err != nil {
+		return cliApprovalConnectorApplyRequest{}, fmt.Errorf("decode approval connector request: %w", err)
+	}
 	req.ID = strings.TrimSpace(req.ID)
 	req.Name = strings.TrimSpace(req.Name)
 	req.Type = strings.TrimSpace(req.Type)
@@ -567,13 +582,6 @@ func approvalConnectorOptionalInt(v int) *int {
 	return &value
 }
 
-func approvalConnectorActivityQuery(limit int) url.Values {
-	if limit <= 0 {
-		return nil
-	}
-	return url.Values{"limit": {strconv.Itoa(limit)}}
-}
-
 func printApprovalConnector(connector cliApprovalConnector) {
 	fmt.Printf("ID:         %s\n", connector.ID)
 	fmt.Printf("Name:       %s\n", connector.Name)
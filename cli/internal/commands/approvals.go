@@ -1,7 +1,6 @@
 package commands
 
 import (
-	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -27,29 +26,30 @@ func newApprovalsCmd() *cobra.Command {
 }
 
 func newApprovalsListCmd() *cobra.Command {
-	return &cobra.Command{
+	var list listOptions
+	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List access requests",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
 
-			data, err := c.Get("/approvals/requests")
+			items, truncated, err := fetchListItems(c, "/approvals/requests", list.arrayQuery(), list.limitOrAll(), nil)
 			if err != nil {
 				return err
 			}
+			items = trimListItems(items, list.limitOrAll())
 
 			if isJSONOutput() {
-				fmt.Println(string(data))
-				return nil
+				return printJSONValue(items)
 			}
 
-			var requests []map[string]interface{}
-			if err := json.Unmarshal(data, &requests); err != nil {
-				return fmt.Errorf("failed to parse response: %w", err)
+			requests, err := decodeListItems(items)
+			if err != nil {
+				return err
 			}
 
 			if len(requests) == 0 {
@@ -69,9 +69,12 @@ func newApprovalsListCmd() *cobra.Command {
 			}
 
 			table.Render()
+			printListTruncatedHint(truncated, len(requests), "access requests")
 			return nil
 		},
 	}
+	addListFlags(cmd, &list, "access requests", 0)
+	return cmd
 }
 
 func newApprovalsApproveCmd() *cobra.Command {
@@ -86,7 +89,7 @@ func newApprovalsApproveCmd() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			id := args[0]
 
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
@@ -101,25 +104,7 @@ func newApprovalsApproveCmd() *cobra.Command {
 				return err
 			}
 
-			if body == nil {
-				fmt.Printf("Access request %q approved.\n", id)
-				return nil
-			}
-
-			switch body.Scope {
-			case "once":
-				fmt.Printf("Access request %q approved for one action.\n", id)
-			case "run":
-				fmt.Printf("Access request %q approved for the current run.\n", id)
-			case "agent_user_ttl":
-				if body.Duration != "" {
-					fmt.Printf("Access request %q approved for %s.\n", id, body.Duration)
-				} else {
-					fmt.Printf("Access request %q approved for a time window.\n", id)
-				}
-			default:
-				fmt.Printf("Access request %q approved.\n", id)
-			}
+			fmt.Println(describeApprovalDecision(fmt.Sprintf("Access request %q", id), body))
 			return nil
 		},
 	}
@@ -128,6 +113,26 @@ func newApprovalsApproveCmd() *cobra.Command {
 	return cmd
 }
 
+// describeApprovalDecision reports what subject was approved for.
+func describeApprovalDecision(subject string, body *approvalDecisionBody) string {
+	if body == nil {
+		return subject + " approved."
+	}
+	switch body.Scope {
+	case "once":
+		return subject + " approved for one action."
+	case "run":
+		return subject + " approved for the current run."
+	case "agent_user_ttl":
+		if body.Duration != "" {
+			return fmt.Sprintf("%s approved for %s.", subject, body.Duration)
+		}
+		return subject + " approved for a time window."
+	default:
+		return subject + " approved."
+	}
+}
+
 func buildApprovalDecision(scope, duration string) (*approvalDecisionBody, error) {
 	normalizedScope, err := normalizeApprovalScope(scope, duration)
 	if err != nil {
@@ -180,7 +185,7 @@ func newApprovalsRejectCmd() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			id := args[0]
 
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
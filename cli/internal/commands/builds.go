@@ -0,0 +1,349 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/runagents/runagents/cli/internal/client"
+	"github.com/spf13/cobra"
+)
+
+// cliBuild is a container image build, started by deploy or POST /builds.
+type cliBuild struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name,omitempty"`
+	Status      string          `json:"status"`
+	Stage       string          `json:"stage,omitempty"`
+	Stages      []cliBuildStage `json:"stages,omitempty"`
+	Image       string          `json:"image"`
+	Error       string          `json:"error,omitempty"`
+	Logs        buildLogLines   `json:"logs,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	CompletedAt time.Time       `json:"completed_at"`
+}
+
+type cliBuildStage struct {
+	Name        string    `json:"name"`
+	Status      string    `json:"status"`
+	StartedAt   time.Time `json:"started_at"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// buildLogLines holds build log output, which the API returns either as one
+// string or as a list of lines.
+type buildLogLines []string
+
+func (l *buildLogLines) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		text = strings.TrimRight(text, "\n")
+		if text == "" {
+			*l = nil
+		} else {
+			*l = strings.Split(text, "\n")
+		}
+		return nil
+	}
+	var lines []string
+	if err := json.Unmarshal(data, &lines); err != nil {
+		return fmt.Errorf("build logs must be a string or a list of strings")
+	}
+	*l = lines
+	return nil
+}
+
+func newBuildsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "builds",
+		Aliases: []string{"build"},
+		Short:   "Inspect agent image builds",
+	}
+
+	cmd.AddCommand(newBuildsListCmd())
+	cmd.AddCommand(newBuildsGetCmd())
+	cmd.AddCommand(newBuildsLogsCmd())
+
+	return cmd
+}
+
+func newBuildsListCmd() *cobra.Command {
+	var list listOptions
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List builds",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newAPIClient(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			items, truncated, err := fetchBuilds(c, list)
+			if err != nil {
+				return err
+			}
+
+			if isJSONOutput() {
+				return printJSONValue(items)
+			}
+
+			table := newTable("ID", "NAME", "STATUS", "IMAGE", "CREATED")
+			for _, item := range items {
+				var build cliBuild
+				if err := json.Unmarshal(item, &build); err != nil {
+					return fmt.Errorf("failed to parse build: %w", err)
+				}
+				table.Append([]string{build.ID, build.Name, build.Status, build.Image, formatRunTime(build.CreatedAt)})
+			}
+			table.Render()
+			printListTruncatedHint(truncated, len(items), "builds")
+			return nil
+		},
+	}
+	addListFlags(cmd, &list, "builds", 50)
+	return cmd
+}
+
+// fetchBuilds lists builds. The API reference only defines creating and
+// reading single builds, so a server without GET /builds is reported as
+// such rather than as a missing resource.
+func fetchBuilds(c listAPIClient, list listOptions) ([]json.RawMessage, bool, error) {
+	query := url.Values{"limit": {list.pageSize()}}
+	items, truncated, err := fetchListItems(c, "/builds", query, list.limitOrAll(), nil)
+	if client.IsNotFound(err) || client.StatusCode(err) == http.StatusMethodNotAllowed {
+		return nil, false, fmt.Errorf("listing builds is not supported by this server; use 'runagents builds get <build-id>' with the build ID that deploy printed")
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return trimListItems(items, list.limitOrAll()), truncated, nil
+}
+
+func newBuildsGetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "get <build-id>",
+		Short: "Show a build's status and stages",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newAPIClient(cmd.Context())
+			if err != nil {
+				return err
+			}
+			build, err := fetchBuild(c, args[0])
+			if err != nil {
+				return err
+			}
+			if isJSONOutput() {
+				return printJSONValue(build)
+			}
+			printBuild(os.Stdout, *build)
+			return nil
+		},
+	}
+}
+
+func newBuildsLogsCmd() *cobra.Command {
+	var (
+		follow   bool
+		interval time.Duration
+		timeout  time.Duration
+	)
+	cmd := &cobra.Command{
+		Use:   "logs <build-id>",
+		Short: "Print a build's log output",
+		Long: `Print the log output recorded for a build, followed by the build error
+if it failed.
+
+With --follow, keep printing new output until the build finishes. Status
+changes are written to stderr. The command then exits with 14 if the build
+failed, or 13 if --wait-timeout elapsed first.
+
+Examples:
+  runagents builds logs build-a1b2c3d4
+  runagents builds logs build-a1b2c3d4 --follow`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newAPIClient(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if !follow {
+				build, err := fetchBuild(c, args[0])
+				if err != nil {
+					return err
+				}
+				if len(build.Logs) == 0 {
+					fmt.Fprintf(os.Stderr, "No logs recorded for build %s (%s).\n", build.ID, emptyFallback(build.Status, "UNKNOWN"))
+				}
+				for _, line := range build.Logs {
+					fmt.Println(line)
+				}
+				if build.Error != "" {
+					fmt.Fprintf(os.Stderr, "Build error: %s\n", build.Error)
+				}
+				return nil
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+			printed := 0
+			progress := newBuildProgress(os.Stderr)
+			build, err := followBuild(ctx, interval, args[0], func(ctx context.Context, id string) (*cliBuild, error) {
+				return fetchBuild(c.WithContext(ctx), id)
+			}, func(build cliBuild) {
+				progress.update(build)
+				if printed > len(build.Logs) {
+					printed = 0
+				}
+				for _, line := range build.Logs[printed:] {
+					fmt.Println(line)
+				}
+				printed = len(build.Logs)
+			})
+			if err != nil {
+				if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					return &exitCodeError{code: exitCodeWaitTimeout, err: fmt.Errorf("timed out after %s waiting for build %s", timeout, args[0])}
+				}
+				return err
+			}
+			return buildOutcome(*build)
+		},
+	}
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Keep printing new output until the build finishes")
+	cmd.Flags().DurationVar(&interval, "interval", 2*time.Second, "Polling interval with --follow")
+	cmd.Flags().DurationVar(&timeout, "wait-timeout", 30*time.Minute, "Maximum time to follow the build")
+	return cmd
+}
+
+func fetchBuild(c interface{ Get(string) ([]byte, error) }, id string) (*cliBuild, error) {
+	data, err := c.Get(fmt.Sprintf("/builds/%s", id))
+	if err != nil {
+		return nil, err
+	}
+	var build cliBuild
+	if err := json.Unmarshal(data, &build); err != nil {
+		return nil, fmt.Errorf("failed to parse build response: %w", err)
+	}
+	if build.ID == "" {
+		build.ID = id
+	}
+	return &build, nil
+}
+
+func isTerminalBuildStatus(status string) bool {
+	switch strings.ToUpper(strings.TrimSpace(status)) {
+	case "SUCCEEDED", "FAILED":
+		return true
+	default:
+		return false
+	}
+}
+
+// followBuild polls a build until it succeeds or fails, calling update with
+// every state fetched. It returns the build in its final state.
+func followBuild(ctx context.Context, interval time.Duration, id string, fetch func(context.Context, string) (*cliBuild, error), update func(cliBuild)) (*cliBuild, error) {
+	var latest *cliBuild
+	err := waitForCondition(ctx, interval, func(ctx context.Context) (bool, error) {
+		build, err := fetch(ctx, id)
+		if err != nil {
+			return false, err
+		}
+		latest = build
+		if update != nil {
+			update(*build)
+		}
+		return isTerminalBuildStatus(build.Status), nil
+	})
+	if err != nil {
+		return latest, err
+	}
+	return latest, nil
+}
+
+// buildOutcome turns a failed build into an error that exits with
+// exitCodeDeployFailed.
+func buildOutcome(build cliBuild) error {
+	if !strings.EqualFold(build.Status, "FAILED") {
+		return nil
+	}
+	return &exitCodeError{code: exitCodeDeployFailed, err: fmt.Errorf("build %s failed: %s", build.ID, emptyFallback(build.Error, "no error reported"))}
+}
+
+// currentBuildStage names the stage a build is in: the stage field when the
+// API reports one, otherwise the last stage that has started.
+func currentBuildStage(build cliBuild) string {
+	if build.Stage != "" {
+		return build.Stage
+	}
+	current := ""
+	for _, stage := range build.Stages {
+		status := strings.ToUpper(stage.Status)
+		if status != "" && status != "PENDING" {
+			current = stage.Name
+		}
+	}
+	return current
+}
+
+// buildProgress prints a line whenever a build's status or stage changes.
+type buildProgress struct {
+	out  io.Writer
+	last string
+}
+
+func newBuildProgress(out io.Writer) *buildProgress {
+	return &buildProgress{out: out}
+}
+
+func (p *buildProgress) update(build cliBuild) {
+	line := fmt.Sprintf("Build %s: %s", build.ID, emptyFallback(build.Status, "UNKNOWN"))
+	if stage := currentBuildStage(build); stage != "" && !isTerminalBuildStatus(build.Status) {
+		line += " (" + stage + ")"
+	}
+	if line == p.last {
+		return
+	}
+	p.last = line
+	fmt.Fprintln(p.out, line)
+}
+
+func printBuild(w io.Writer, build cliBuild) {
+	fields := []runDetailField{
+		{Label: "ID", Value: build.ID},
+		{Label: "Name", Value: build.Name},
+		{Label: "Status", Value: build.Status},
+		{Label: "Stage", Value: currentBuildStage(build)},
+		{Label: "Image", Value: build.Image},
+		{Label: "Created", Value: formatRunTime(build.CreatedAt)},
+		{Label: "Completed", Value: formatRunTime(build.CompletedAt)},
+		{Label: "Error", Value: build.Error},
+	}
+	for _, field := range fields {
+		if field.Value == "" && field.Label != "ID" && field.Label != "Status" {
+			continue
+		}
+		fmt.Fprintf(w, "%-11s%s\n", field.Label+":", field.Value)
+	}
+	if len(build.Stages) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Stages:")
+		for _, stage := range build.Stages {
+			line := fmt.Sprintf("  %-24s %-10s", stage.Name, emptyFallback(stage.Status, "PENDING"))
+			if !stage.StartedAt.IsZero() && !stage.CompletedAt.IsZero() {
+				line += " " + stage.CompletedAt.Sub(stage.StartedAt).Round(time.Second).String()
+			}
+			fmt.Fprintln(w, strings.TrimRight(line, " "))
+		}
+	}
+	if len(build.Logs) > 0 {
+		fmt.Fprintf(w, "\n%d log lines; run 'runagents builds logs %s' to see them.\n", len(build.Logs), build.ID)
+	}
+}
@@ -0,0 +1,197 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/runagents/runagents/cli/internal/client"
+)
+
+func TestBuildLogLinesAcceptStringOrList(t *testing.T) {
+	var fromString, fromList cliBuild
+	if err := json.Unmarshal([]byte(`{"id":"b1","logs":"step 1\nstep 2\n"}`), &fromString); err != nil {
+		t.Fatalf("unmarshal string logs: %v", err)
+	}
+	if err := json.Unmarshal([]byte(`{"id":"b1","logs":["step 1","step 2"]}`), &fromList); err != nil {
+		t.Fatalf("unmarshal list logs: %v", err)
+	}
+	for _, build := range []cliBuild{fromString, fromList} {
+		if len(build.Logs) != 2 || build.Logs[0] != "step 1" || build.Logs[1] != "step 2" {
+			t.Fatalf("unexpected logs: %#v", build.Logs)
+		}
+	}
+}
+
+func TestCurrentBuildStagePrefersStageThenLastStartedStage(t *testing.T) {
+	build := cliBuild{Stages: []cliBuildStage{
+		{Name: "generate-dockerfile", Status: "SUCCEEDED"},
+		{Name: "build-image", Status: "RUNNING"},
+		{Name: "push-image", Status: "PENDING"},
+	}}
+	if got := currentBuildStage(build); got != "build-image" {
+		t.Fatalf("expected build-image, got %q", got)
+	}
+	build.Stage = "push-image"
+	if got := currentBuildStage(build); got != "push-image" {
+		t.Fatalf("expected push-image, got %q", got)
+	}
+}
+
+// deployServer serves a build that moves through the given statuses, one per
+// poll, and an agent whose GET responses move through agentStatuses the same
+// way. An empty agent status is served as 404 Not Found.
+func deployServer(t *testing.T, builds []cliBuild, agentStatuses ...string) *httptest.Server {
+	t.Helper()
+	var (
+		mu         sync.Mutex
+		polls      int
+		agentPolls int
+	)
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/api/v1/builds/build-1":
+			build := builds[min(polls, len(builds)-1)]
+			polls++
+			_ = json.NewEncoder(w).Encode(build)
+		case "/api/v1/agents/billing":
+			status := agentStatuses[min(agentPolls, len(agentStatuses)-1)]
+			agentPolls++
+			if status == "" {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write([]byte(status))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestWaitForDeployFollowsBuildThenAgent(t *testing.T) {
+	server := deployServer(t, []cliBuild{
+		{ID: "build-1", Status: "PENDING"},
+		{ID: "build-1", Status: "BUILDING", Stage: "install-dependencies"},
+		{ID: "build-1", Status: "SUCCEEDED", Image: "registry.runagents.io/billing:abc"},
+	}, `{"name":"billing","spec":{"image":"registry.runagents.io/billing:abc"},"status":{"phase":"Running"}}`)
+	defer server.Close()
+
+	var out, errOut bytes.Buffer
+	c := client.NewClient(server.URL, "ra_ws_test")
+	target := deployWaitTarget{Agent: "billing", BuildID: "build-1", Previous: deployedAgentState{Phase: "Running", Image: "registry.runagents.io/billing:old"}}
+	result, err := waitForDeploy(context.Background(), c, target, time.Millisecond, time.Minute, &out, &errOut)
+	if err != nil {
+		t.Fatalf("waitForDeploy: %v", err)
+	}
+	if result.Build == nil || result.Build.Status != "SUCCEEDED" || result.Agent.Phase != "Running" {
+		t.Fatalf("unexpected result: %#v", result)
+	}
+	want := "Build build-1: PENDING\nBuild build-1: BUILDING (install-dependencies)\nBuild build-1: SUCCEEDED\nAgent billing: Running\n"
+	if out.String() != want {
+		t.Fatalf("unexpected progress:\n%s", out.String())
+	}
+}
+
+func TestWaitForDeployReportsBuildFailureWithLogs(t *testing.T) {
+	server := deployServer(t, []cliBuild{
+		{ID: "build-1", Status: "BUILDING"},
+		{ID: "build-1", Status: "FAILED", Error: "pip install failed", Logs: buildLogLines{"Collecting nonexistent-package", "ERROR: No matching distribution"}},
+	}, `{"name":"billing","status":"Pending"}`)
+	defer server.Close()
+
+	var out, errOut bytes.Buffer
+	c := client.NewClient(server.URL, "ra_ws_test")
+	_, err := waitForDeploy(context.Background(), c, deployWaitTarget{Agent: "billing", BuildID: "build-1"}, time.Millisecond, time.Minute, &out, &errOut)
+	assertExitCode(t, err, exitCodeDeployFailed)
+	if !strings.Contains(err.Error(), "pip install failed") {
+		t.Fatalf("expected build error in %q", err)
+	}
+	if !strings.Contains(errOut.String(), "  ERROR: No matching distribution\n") {
+		t.Fatalf("expected failure logs, got:\n%s", errOut.String())
+	}
+	if strings.Contains(out.String(), "Agent billing") {
+		t.Fatalf("should not wait for the agent after a failed build:\n%s", out.String())
+	}
+}
+
+func TestWaitForDeployWaitsForTheDeployedImage(t *testing.T) {
+	server := deployServer(t, nil,
+		`{"name":"billing","spec":{"image":"registry.runagents.io/billing:v1"},"status":{"phase":"Running"}}`,
+		`{"name":"billing","spec":{"image":"registry.runagents.io/billing:v2"},"status":{"phase":"Running"}}`,
+	)
+	defer server.Close()
+
+	var out bytes.Buffer
+	c := client.NewClient(server.URL, "ra_ws_test")
+	target := deployWaitTarget{
+		Agent:    "billing",
+		Image:    "registry.runagents.io/billing:v2",
+		Previous: deployedAgentState{Phase: "Running", Image: "registry.runagents.io/billing:v1"},
+	}
+	result, err := waitForDeploy(context.Background(), c, target, time.Millisecond, time.Minute, &out, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("waitForDeploy: %v", err)
+	}
+	if result.Agent.Image != "registry.runagents.io/billing:v2" {
+		t.Fatalf("expected to wait for the new image, got %#v", result.Agent)
+	}
+}
+
+func TestWaitForDeployRetriesNotFoundAndWaitsForAPhaseChange(t *testing.T) {
+	server := deployServer(t, nil,
+		"",
+		`{"name":"billing","status":{"phase":"Running"}}`,
+		`{"name":"billing","status":"Pending"}`,
+		`{"name":"billing","status":{"phase":"Running"}}`,
+	)
+	defer server.Close()
+
+	var out bytes.Buffer
+	c := client.NewClient(server.URL, "ra_ws_test")
+	target := deployWaitTarget{Agent: "billing", Previous: deployedAgentState{Phase: "Running"}}
+	if _, err := waitForDeploy(context.Background(), c, target, time.Millisecond, time.Minute, &out, &bytes.Buffer{}); err != nil {
+		t.Fatalf("waitForDeploy: %v", err)
+	}
+	if want := "Agent billing: Pending\nAgent billing: Running\n"; out.String() != want {
+		t.Fatalf("unexpected progress:\n%s", out.String())
+	}
+
+	server = deployServer(t, nil, `{"name":"billing","status":{"phase":"Running"}}`)
+	defer server.Close()
+	c = client.NewClient(server.URL, "ra_ws_test")
+	_, err := waitForDeploy(context.Background(), c, deployWaitTarget{Agent: "billing", Previous: deployedAgentState{Phase: "Running"}}, time.Millisecond, 20*time.Millisecond, &bytes.Buffer{}, &bytes.Buffer{})
+	assertExitCode(t, err, exitCodeWaitTimeout)
+}
+
+func TestWaitForDeployTimesOutWaitingForAgent(t *testing.T) {
+	server := deployServer(t, []cliBuild{{ID: "build-1", Status: "SUCCEEDED"}}, `{"name":"billing","status":"Pending"}`)
+	defer server.Close()
+
+	c := client.NewClient(server.URL, "ra_ws_test")
+	_, err := waitForDeploy(context.Background(), c, deployWaitTarget{Agent: "billing"}, time.Millisecond, 20*time.Millisecond, &bytes.Buffer{}, &bytes.Buffer{})
+	assertExitCode(t, err, exitCodeWaitTimeout)
+	if !strings.Contains(err.Error(), "last phase Pending") {
+		t.Fatalf("expected last phase in %q", err)
+	}
+}
+
+func TestFetchBuildsReportsMissingListEndpoint(t *testing.T) {
+	for _, status := range []int{http.StatusNotFound, http.StatusMethodNotAllowed} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+		}))
+		_, _, err := fetchBuilds(client.NewClient(server.URL, "ra_ws_test"), listOptions{Limit: 50})
+		server.Close()
+		if err == nil || !strings.Contains(err.Error(), "listing builds is not supported by this server") {
+			t.Fatalf("status %d: expected an unsupported error, got %v", status, err)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/url"
@@ -14,14 +15,6 @@ import (
 
 const catalogManifestFilename = "runagents.catalog.json"
 
-type catalogListResponse struct {
-	GeneratedAt string             `json:"generated_at,omitempty"`
-	Items       []catalogIndexItem `json:"items"`
-	Total       int                `json:"total"`
-	Page        int                `json:"page"`
-	PageSize    int                `json:"page_size"`
-}
-
 type catalogIndexItem struct {
 	ID                   string                 `json:"id"`
 	Name                 string                 `json:"name"`
@@ -114,6 +107,7 @@ func newCatalogListCmd() *cobra.Command {
 		governance   []string
 		page         int
 		pageSize     int
+		list         listOptions
 	)
 
 	cmd := &cobra.Command{
@@ -121,33 +115,50 @@ func newCatalogListCmd() *cobra.Command {
 		Short: "List catalog agents",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
 
+			// --page-size on its own keeps its old meaning of "one page".
+			if cmd.Flags().Changed("page-size") && !cmd.Flags().Changed("limit") {
+				list.Limit = pageSize
+			}
 			query := catalogListQuery(search, categories, tags, integrations, governance, page, pageSize)
-
-			data, err := c.GetWithQuery("/catalog", query)
+			if isJSONOutput() && !cmd.Flags().Changed("limit") && !cmd.Flags().Changed("all") {
+				// Without --limit or --all, JSON output stays the server's
+				// page envelope (items, total, page, page_size).
+				data, err := c.GetWithQuery("/catalog", query)
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(data))
+				return nil
+			}
+			items, truncated, err := fetchListItems(c, "/catalog", query, list.limitOrAll(), nil)
 			if err != nil {
 				return err
 			}
+			items = trimListItems(items, list.limitOrAll())
 			if isJSONOutput() {
-				fmt.Println(string(data))
-				return nil
+				return printJSONValue(items)
 			}
 
-			var resp catalogListResponse
-			if err := json.Unmarshal(data, &resp); err != nil {
-				return fmt.Errorf("failed to parse response: %w", err)
+			entries := make([]catalogIndexItem, 0, len(items))
+			for _, raw := range items {
+				var item catalogIndexItem
+				if err := json.Unmarshal(raw, &item); err != nil {
+					return fmt.Errorf("failed to parse response: %w", err)
+				}
+				entries = append(entries, item)
 			}
-			if len(resp.Items) == 0 {
+			if len(entries) == 0 {
 				fmt.Println("No catalog agents found.")
 				return nil
 			}
 
 			table := newTable("ID", "NAME", "CATEGORY", "LATEST", "INTEGRATIONS")
-			for _, item := range resp.Items {
+			for _, item := range entries {
 				table.Append([]string{
 					item.ID,
 					item.Name,
@@ -157,6 +168,7 @@ func newCatalogListCmd() *cobra.Command {
 				})
 			}
 			table.Render()
+			printListTruncatedHint(truncated, len(entries), "catalog agents")
 			return nil
 		},
 	}
@@ -166,8 +178,9 @@ func newCatalogListCmd() *cobra.Command {
 	cmd.Flags().StringSliceVar(&tags, "tag", nil, "Filter by tag (repeatable)")
 	cmd.Flags().StringSliceVar(&integrations, "integration", nil, "Filter by required integration (repeatable)")
 	cmd.Flags().StringSliceVar(&governance, "governance", nil, "Filter by governance trait (repeatable)")
-	cmd.Flags().IntVar(&page, "page", 1, "Page number")
-	cmd.Flags().IntVar(&pageSize, "page-size", 24, "Items per page")
+	cmd.Flags().IntVar(&page, "page", 1, "Page to start from")
+	cmd.Flags().IntVar(&pageSize, "page-size", 24, "Items to request per page")
+	addListFlags(cmd, &list, "catalog agents", 24)
 	return cmd
 }
 
@@ -178,7 +191,7 @@ func newCatalogShowCmd() *cobra.Command {
 		Short: "Show details for a catalog agent",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			manifest, err := fetchCatalogManifest(args[0], version)
+			manifest, err := fetchCatalogManifest(cmd.Context(), args[0], version)
 			if err != nil {
 				return err
 			}
@@ -204,7 +217,7 @@ func newCatalogVersionsCmd() *cobra.Command {
 		Short: "List published versions for a catalog agent",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
@@ -246,7 +259,7 @@ func newCatalogInitCmd() *cobra.Command {
 		Short: "Initialize a local working copy from a catalog agent",
 		Args:  cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			manifest, err := fetchCatalogManifest(args[0], version)
+			manifest, err := fetchCatalogManifest(cmd.Context(), args[0], version)
 			if err != nil {
 				return err
 			}
@@ -291,7 +304,7 @@ func newCatalogDeployCmd() *cobra.Command {
 		Short: "Deploy a catalog agent directly from its manifest",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			manifest, err := fetchCatalogManifest(args[0], version)
+			manifest, err := fetchCatalogManifest(cmd.Context(), args[0], version)
 			if err != nil {
 				return err
 			}
@@ -315,7 +328,7 @@ func newCatalogDeployCmd() *cobra.Command {
 				return nil
 			}
 
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
@@ -370,8 +383,8 @@ type catalogDeployOptions struct {
 	IdentityProvider string
 }
 
-func fetchCatalogManifest(agentID, version string) (*catalogManifest, error) {
-	c, err := newAPIClient()
+func fetchCatalogManifest(ctx context.Context, agentID, version string) (*catalogManifest, error) {
+	c, err := newAPIClient(ctx)
 	if err != nil {
 		return nil, err
 	}
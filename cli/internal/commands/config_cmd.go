@@ -8,6 +8,15 @@ import (
 	"github.com/spf13/cobra"
 )
 
+type cliConfigProfile struct {
+	Name            string `json:"name"`
+	Current         bool   `json:"current"`
+	Endpoint        string `json:"endpoint,omitempty"`
+	APIKey          string `json:"api_key,omitempty"`
+	AssistantMode   string `json:"assistant_mode,omitempty"`
+	CredentialStore string `json:"credential_store,omitempty"`
+}
+
 func newConfigCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "config",
@@ -16,6 +25,9 @@ func newConfigCmd() *cobra.Command {
 
 	cmd.AddCommand(newConfigSetCmd())
 	cmd.AddCommand(newConfigGetCmd())
+	cmd.AddCommand(newConfigUseProfileCmd())
+	cmd.AddCommand(newConfigProfilesCmd())
+	cmd.AddCommand(newConfigMigrateSecretsCmd())
 
 	return cmd
 }
@@ -24,37 +36,65 @@ func newConfigSetCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "set <key> <value>",
 		Short: "Set a configuration value",
-		Long:  "Set a configuration value. Valid keys: endpoint, api-key, assistant-mode.",
-		Args:  cobra.ExactArgs(2),
+		Long: "Set a configuration value. Valid keys: endpoint, api-key, assistant-mode, credential-store.\n\n" +
+			"Values are stored in the active profile; use --profile to target (or create) another one.\n" +
+			"credential-store accepts keychain, file, the name of a runagents-credential-<name> helper, or none;\n" +
+			"the profile's API key is moved to the new store and removed from the old one.",
+		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			key := args[0]
 			value := args[1]
 
-			cfg, err := config.Load()
+			file, err := config.LoadFile()
 			if err != nil {
 				return err
 			}
+			profileName := file.ResolveProfileName(flagProfile)
+			profile := file.Profile(profileName)
 
+			var movedTo string
 			switch key {
 			case "endpoint":
-				cfg.Endpoint = value
+				profile.Endpoint = value
 			case "api-key":
-				cfg.APIKey = value
+				if err := profile.SetAPIKey(profileName, value); err != nil {
+					return err
+				}
 			case "assistant-mode":
 				mode, err := config.NormalizeAssistantMode(value)
 				if err != nil {
 					return err
 				}
-				cfg.AssistantMode = mode
+				profile.AssistantMode = mode
+			case "credential-store":
+				store := strings.TrimSpace(value)
+				if store == "none" {
+					store = ""
+				}
+				if store != "" {
+					if _, err := config.NewCredentialStore(store); err != nil {
+						return err
+					}
+				}
+				moved, err := profile.SwitchCredentialStore(profileName, store)
+				if err != nil {
+					return fmt.Errorf("credential store not changed: %w", err)
+				}
+				if moved {
+					movedTo = emptyFallback(store, "config.json (plaintext)")
+				}
 			default:
-				return fmt.Errorf("unknown config key %q; valid keys: endpoint, api-key, assistant-mode", key)
+				return fmt.Errorf("unknown config key %q; valid keys: endpoint, api-key, assistant-mode, credential-store", key)
 			}
 
-			if err := config.Save(cfg); err != nil {
+			if err := config.SaveFile(file); err != nil {
 				return err
 			}
 
-			fmt.Printf("Config %q set successfully.\n", key)
+			fmt.Printf("Config %q set successfully for profile %q.\n", key, profileName)
+			if movedTo != "" {
+				fmt.Printf("Moved the API key for profile %q to %s.\n", profileName, movedTo)
+			}
 			return nil
 		},
 	}
@@ -67,17 +107,24 @@ func newConfigGetCmd() *cobra.Command {
 		Short: "Show current configuration",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cfg, err := config.Load()
+			cfg, err := loadConfig()
 			if err != nil {
 				return err
 			}
 
+			fmt.Printf("Profile:  %s\n", cfg.Profile)
 			fmt.Printf("Endpoint: %s\n", cfg.Endpoint)
 			fmt.Printf("Assistant Mode: %s\n", cfg.AssistantMode)
-			if cfg.APIKey != "" {
+			if cfg.CredentialStore != "" {
+				fmt.Printf("Credential Store: %s\n", cfg.CredentialStore)
+			}
+			switch {
+			case cfg.APIKey != "":
 				masked := maskAPIKey(cfg.APIKey)
 				fmt.Printf("API Key:  %s\n", masked)
-			} else {
+			case cfg.CredentialStore != "":
+				fmt.Printf("API Key:  (stored in %s)\n", cfg.CredentialStore)
+			default:
 				fmt.Println("API Key:  (not set)")
 			}
 			return nil
@@ -86,6 +133,143 @@ func newConfigGetCmd() *cobra.Command {
 	return cmd
 }
 
+func newConfigUseProfileCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "use-profile <name>",
+		Short: "Switch the current config profile",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := strings.TrimSpace(args[0])
+			file, err := config.LoadFile()
+			if err != nil {
+				return err
+			}
+			if _, exists := file.Profiles[name]; !exists {
+				return fmt.Errorf("profile %q not found; create it with 'runagents config set --profile %s endpoint <url>'", name, name)
+			}
+			file.CurrentProfile = name
+			if err := config.SaveFile(file); err != nil {
+				return err
+			}
+			fmt.Printf("Switched to profile %q.\n", name)
+			return nil
+		},
+	}
+}
+
+func newConfigProfilesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profiles",
+		Short: "Manage config profiles",
+	}
+	cmd.AddCommand(newConfigProfilesListCmd())
+	return cmd
+}
+
+func newConfigProfilesListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List config profiles",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, err := config.LoadFile()
+			if err != nil {
+				return err
+			}
+			profiles := listConfigProfiles(file)
+			if isJSONOutput() {
+				return printJSONValue(profiles)
+			}
+			if len(profiles) == 0 {
+				fmt.Println("No profiles configured. Run 'runagents config set endpoint <url>' to create the default profile.")
+				return nil
+			}
+
+			table := newTable("CURRENT", "NAME", "ENDPOINT", "API KEY", "ASSISTANT MODE")
+			for _, profile := range profiles {
+				current := ""
+				if profile.Current {
+					current = "*"
+				}
+				table.Append([]string{
+					current,
+					profile.Name,
+					emptyFallback(profile.Endpoint, "(default)"),
+					emptyFallback(profile.APIKey, "(not set)"),
+					emptyFallback(profile.AssistantMode, config.AssistantModeExternal),
+				})
+			}
+			table.Render()
+			return nil
+		},
+	}
+}
+
+func newConfigMigrateSecretsCmd() *cobra.Command {
+	var store string
+	cmd := &cobra.Command{
+		Use:   "migrate-secrets",
+		Short: "Move plaintext API keys from config.json into a credential store",
+		Long: `Move every plaintext API key in ~/.runagents/config.json into a credential store.
+
+Each key goes to --store when given, otherwise to the profile's configured
+credential-store, otherwise to the OS keychain when available and the
+encrypted credentials file if not.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, err := config.LoadFile()
+			if err != nil {
+				return err
+			}
+			migrated, migrateErr := config.MigrateSecrets(file, store)
+			if len(migrated) > 0 {
+				if err := config.SaveFile(file); err != nil {
+					return err
+				}
+			}
+			if isJSONOutput() {
+				if err := printJSONValue(migrated); err != nil {
+					return err
+				}
+			} else if len(migrated) == 0 && migrateErr == nil {
+				fmt.Println("No plaintext API keys found.")
+			} else {
+				for _, item := range migrated {
+					fmt.Printf("Moved API key for profile %q to %s.\n", item.Profile, item.Store)
+				}
+			}
+			return migrateErr
+		},
+	}
+	cmd.Flags().StringVar(&store, "store", "", "Credential store: keychain, file, or a credential helper name")
+	return cmd
+}
+
+// listConfigProfiles returns stored profiles with masked API keys.
+func listConfigProfiles(file *config.File) []cliConfigProfile {
+	current := file.ResolveProfileName(flagProfile)
+	names := file.ProfileNames()
+	profiles := make([]cliConfigProfile, 0, len(names))
+	for _, name := range names {
+		stored := file.Profiles[name]
+		profile := cliConfigProfile{
+			Name:            name,
+			Current:         name == current,
+			Endpoint:        stored.Endpoint,
+			AssistantMode:   stored.AssistantMode,
+			CredentialStore: stored.CredentialStore,
+		}
+		switch {
+		case stored.APIKey != "":
+			profile.APIKey = maskAPIKey(stored.APIKey)
+		case stored.CredentialStore != "":
+			profile.APIKey = "(" + stored.CredentialStore + ")"
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles
+}
+
 // maskAPIKey masks all but the first 4 and last 4 characters of a key.
 func maskAPIKey(key string) string {
 	if len(key) <= 8 {
@@ -50,7 +50,7 @@ func newContextExportCmd() *cobra.Command {
 		Short: "Export workspace snapshot (agents/tools/models/approvals/drafts)",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
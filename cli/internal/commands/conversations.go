@@ -0,0 +1,327 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Kinds of entries in a conversation transcript.
+const (
+	transcriptUser     = "user"
+	transcriptAgent    = "agent"
+	transcriptTool     = "tool"
+	transcriptApproval = "approval"
+	transcriptConsent  = "consent"
+	transcriptError    = "error"
+)
+
+// conversationTranscript is every run of a conversation stitched into one
+// chat transcript.
+type conversationTranscript struct {
+	ConversationID string            `json:"conversation_id"`
+	Users          []string          `json:"users"`
+	Agents         []string          `json:"agents"`
+	StartedAt      time.Time         `json:"started_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+	Runs           []conversationRun `json:"runs"`
+	GeneratedAt    time.Time         `json:"generated_at"`
+}
+
+// conversationRun is one run of the conversation with its transcript entries.
+type conversationRun struct {
+	Run     cliRun                  `json:"run"`
+	Entries []conversationTurnEntry `json:"entries"`
+}
+
+type conversationTurnEntry struct {
+	RunID     string    `json:"run_id"`
+	Seq       int       `json:"seq,omitempty"`
+	Kind      string    `json:"kind"`
+	EventType string    `json:"event_type,omitempty"`
+	Actor     string    `json:"actor,omitempty"`
+	Text      string    `json:"text"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+func newConversationsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "conversations",
+		Aliases: []string{"conversation", "conv"},
+		Short:   "Inspect conversations across runs",
+	}
+	cmd.AddCommand(newConversationsShowCmd())
+	return cmd
+}
+
+func newConversationsShowCmd() *cobra.Command {
+	var (
+		format      string
+		file        string
+		concurrency int
+	)
+	cmd := &cobra.Command{
+		Use:   "show <conversation-id>",
+		Short: "Show a conversation as a chat transcript",
+		Long: `Show every run in a conversation, oldest first, as one chat transcript.
+User and agent messages are shown in full, with tool calls, approvals, and
+consent requests inline where they happened.
+
+Examples:
+  runagents conversations show <conversation-id>
+  runagents conversations show <conversation-id> --format markdown --file escalation.md
+  runagents conversations show <conversation-id> --format json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format = strings.ToLower(strings.TrimSpace(format))
+			if format == "md" {
+				format = "markdown"
+			}
+			if isJSONOutput() {
+				format = "json"
+			}
+			if format != "text" && format != "markdown" && format != "json" {
+				return fmt.Errorf("invalid --format %q: use text, markdown, or json", format)
+			}
+			c, err := newAPIClient(cmd.Context())
+			if err != nil {
+				return err
+			}
+			runs, _, err := fetchRuns(c, runFilters{ConversationID: args[0]})
+			if err != nil {
+				return err
+			}
+			if len(runs) == 0 {
+				return fmt.Errorf("no runs found for conversation %q", args[0])
+			}
+			ids := make([]string, 0, len(runs))
+			for _, run := range runs {
+				ids = append(ids, run.ID)
+			}
+			events, err := fetchRunEventsForRuns(cmd.Context(), c, ids, concurrency)
+			if err != nil {
+				return err
+			}
+			transcript := buildConversationTranscript(args[0], runs, events, time.Now().UTC())
+
+			var out io.Writer = os.Stdout
+			if file != "" {
+				f, err := os.Create(file)
+				if err != nil {
+					return fmt.Errorf("failed to create transcript file: %w", err)
+				}
+				defer f.Close()
+				out = f
+			}
+			switch format {
+			case "json":
+				var data []byte
+				data, err = json.MarshalIndent(transcript, "", "  ")
+				if err == nil {
+					_, err = fmt.Fprintln(out, string(data))
+				}
+			case "markdown":
+				err = renderConversationMarkdown(out, transcript)
+			default:
+				err = renderConversationText(out, transcript)
+			}
+			if err != nil {
+				return err
+			}
+			if file != "" {
+				fmt.Printf("Transcript for conversation %s written to %s.\n", args[0], file)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "text", "Output format: text, markdown, or json")
+	cmd.Flags().StringVar(&file, "file", "", "Write the transcript to this file instead of stdout")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 8, "Maximum number of runs to fetch events for in parallel")
+	return cmd
+}
+
+// buildConversationTranscript orders the runs by start time and turns their
+// events into transcript entries.
+func buildConversationTranscript(conversationID string, runs []cliRun, events map[string][]cliRunEvent, now time.Time) conversationTranscript {
+	runs = append([]cliRun(nil), runs...)
+	sort.SliceStable(runs, func(i, j int) bool {
+		left, right := runStartTime(runs[i]), runStartTime(runs[j])
+		if !left.Equal(right) {
+			return left.Before(right)
+		}
+		if runs[i].SurfaceTurnID != runs[j].SurfaceTurnID {
+			return runs[i].SurfaceTurnID < runs[j].SurfaceTurnID
+		}
+		return runs[i].ID < runs[j].ID
+	})
+
+	transcript := conversationTranscript{
+		ConversationID: conversationID,
+		Users:          []string{},
+		Agents:         []string{},
+		Runs:           []conversationRun{},
+		GeneratedAt:    now,
+	}
+	users, agents := map[string]bool{}, map[string]bool{}
+	for _, run := range runs {
+		if run.UserID != "" && !users[run.UserID] {
+			users[run.UserID] = true
+			transcript.Users = append(transcript.Users, run.UserID)
+		}
+		if run.AgentID != "" && !agents[run.AgentID] {
+			agents[run.AgentID] = true
+			transcript.Agents = append(transcript.Agents, run.AgentID)
+		}
+		if start := runStartTime(run); !start.IsZero() && (transcript.StartedAt.IsZero() || start.Before(transcript.StartedAt)) {
+			transcript.StartedAt = start
+		}
+		if run.UpdatedAt.After(transcript.UpdatedAt) {
+			transcript.UpdatedAt = run.UpdatedAt
+		}
+
+		entries := conversationRunEntries(run, events[run.ID])
+		transcript.Runs = append(transcript.Runs, conversationRun{Run: run, Entries: entries})
+	}
+	return transcript
+}
+
+// conversationRunEntries keeps the events a reader of the conversation cares
+// about. Bookkeeping events such as RUN_CREATED and INVOKE_* are dropped.
+func conversationRunEntries(run cliRun, events []cliRunEvent) []conversationTurnEntry {
+	entries := []conversationTurnEntry{}
+	sawUserMessage := false
+	for _, event := range sortedRunEvents(events) {
+		kind, text := "", ""
+		switch strings.ToUpper(event.Type) {
+		case "USER_MESSAGE":
+			kind = transcriptUser
+			text = firstNonEmptyRunValue(dataString(event.Data, "content"), dataString(event.Data, "message"))
+			sawUserMessage = true
+		case "AGENT_MESSAGE":
+			kind = transcriptAgent
+			text = firstNonEmptyRunValue(dataString(event.Data, "content"), dataString(event.Data, "message"))
+		case "TOOL_REQUEST", "TOOL_CALLED", "TOOL_RESPONSE":
+			kind = transcriptTool
+		case "APPROVAL_REQUIRED", "APPROVED", "REJECTED", "RESUMED":
+			kind = transcriptApproval
+		case "CONSENT_REQUIRED":
+			kind = transcriptConsent
+		case "FAILED", "INVOKE_FAILED":
+			kind = transcriptError
+		default:
+			continue
+		}
+		if text == "" {
+			text = summarizeRunEvent(event)
+		}
+		entries = append(entries, conversationTurnEntry{
+			RunID:     run.ID,
+			Seq:       event.Seq,
+			Kind:      kind,
+			EventType: event.Type,
+			Actor:     event.Actor,
+			Text:      strings.TrimSpace(text),
+			Timestamp: event.Timestamp,
+		})
+	}
+	if !sawUserMessage && strings.TrimSpace(run.InitialMessage) != "" {
+		initial := conversationTurnEntry{
+			RunID:     run.ID,
+			Kind:      transcriptUser,
+			Actor:     run.UserID,
+			Text:      strings.TrimSpace(run.InitialMessage),
+			Timestamp: runStartTime(run),
+		}
+		entries = append([]conversationTurnEntry{initial}, entries...)
+	}
+	return entries
+}
+
+func conversationEntryLabel(kind string) string {
+	switch kind {
+	case transcriptUser:
+		return "User"
+	case transcriptAgent:
+		return "Agent"
+	case transcriptTool:
+		return "Tool"
+	case transcriptApproval:
+		return "Approval"
+	case transcriptConsent:
+		return "Consent"
+	case transcriptError:
+		return "Error"
+	default:
+		return kind
+	}
+}
+
+func renderConversationText(w io.Writer, transcript conversationTranscript) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Conversation %s\n", transcript.ConversationID)
+	fmt.Fprintf(&b, "%-10s%s\n", "Users:", strings.Join(transcript.Users, ", "))
+	fmt.Fprintf(&b, "%-10s%s\n", "Agents:", strings.Join(transcript.Agents, ", "))
+	fmt.Fprintf(&b, "%-10s%d\n", "Runs:", len(transcript.Runs))
+
+	for _, run := range transcript.Runs {
+		fmt.Fprintf(&b, "\n--- Run %s (%s, %s) ---\n", run.Run.ID, emptyFallback(run.Run.AgentID, "unknown agent"), emptyFallback(run.Run.Status, "UNKNOWN"))
+		if len(run.Entries) == 0 {
+			b.WriteString("(no messages recorded)\n")
+		}
+		for _, entry := range run.Entries {
+			label := conversationEntryLabel(entry.Kind)
+			if entry.Kind != transcriptUser && entry.Kind != transcriptAgent {
+				label = "[" + label + "]"
+			}
+			text := strings.ReplaceAll(entry.Text, "\n", "\n"+strings.Repeat(" ", 33))
+			fmt.Fprintf(&b, "%-21s %-10s %s\n", formatRunTime(entry.Timestamp), label, text)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func renderConversationMarkdown(w io.Writer, transcript conversationTranscript) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Conversation: %s\n\n", transcript.ConversationID)
+	b.WriteString("| Field | Value |\n|-------|-------|\n")
+	fmt.Fprintf(&b, "| Users | %s |\n", markdownCell(strings.Join(transcript.Users, ", ")))
+	fmt.Fprintf(&b, "| Agents | %s |\n", markdownCell(strings.Join(transcript.Agents, ", ")))
+	fmt.Fprintf(&b, "| Runs | %d |\n", len(transcript.Runs))
+	if !transcript.StartedAt.IsZero() {
+		fmt.Fprintf(&b, "| Started | %s |\n", formatRunTime(transcript.StartedAt))
+	}
+	if !transcript.UpdatedAt.IsZero() {
+		fmt.Fprintf(&b, "| Last updated | %s |\n", formatRunTime(transcript.UpdatedAt))
+	}
+	b.WriteString("\n")
+
+	for _, run := range transcript.Runs {
+		fmt.Fprintf(&b, "## Run %s\n\n", run.Run.ID)
+		fmt.Fprintf(&b, "Agent `%s` · status `%s`", emptyFallback(run.Run.AgentID, "unknown"), emptyFallback(run.Run.Status, "UNKNOWN"))
+		if start := runStartTime(run.Run); !start.IsZero() {
+			fmt.Fprintf(&b, " · started %s", formatRunTime(start))
+		}
+		b.WriteString("\n\n")
+		if len(run.Entries) == 0 {
+			b.WriteString("No messages recorded.\n\n")
+		}
+		for _, entry := range run.Entries {
+			switch entry.Kind {
+			case transcriptUser, transcriptAgent:
+				fmt.Fprintf(&b, "**%s** (%s):\n\n> %s\n\n", conversationEntryLabel(entry.Kind), formatRunTime(entry.Timestamp), strings.ReplaceAll(entry.Text, "\n", "\n> "))
+			default:
+				fmt.Fprintf(&b, "- _%s_ (%s): %s\n\n", conversationEntryLabel(entry.Kind), formatRunTime(entry.Timestamp), markdownCell(entry.Text))
+			}
+		}
+	}
+	fmt.Fprintf(&b, "_Generated by the runagents CLI at %s._\n", formatRunTime(transcript.GeneratedAt))
+	_, err := io.WriteString(w, b.String())
+	return err
+}
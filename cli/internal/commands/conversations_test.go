@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func conversationFixture() ([]cliRun, map[string][]cliRunEvent) {
+	start := time.Date(2026, 4, 9, 10, 0, 0, 0, time.UTC)
+	runs := []cliRun{
+		{ID: "run-2", ConversationID: "conv-1", AgentID: "billing", UserID: "alice@example.com", Status: "PAUSED_APPROVAL", CreatedAt: start.Add(5 * time.Minute)},
+		{ID: "run-1", ConversationID: "conv-1", AgentID: "billing", UserID: "alice@example.com", Status: "COMPLETED", CreatedAt: start, InitialMessage: "What did I pay last month?"},
+	}
+	events := map[string][]cliRunEvent{
+		"run-1": {
+			{Seq: 3, Type: "AGENT_MESSAGE", Timestamp: start.Add(3 * time.Second), Data: map[string]any{"content": "You paid $42.\nAnything else?"}},
+			{Seq: 1, Type: "RUN_CREATED", Timestamp: start},
+			{Seq: 2, Type: "TOOL_REQUEST", Timestamp: start.Add(time.Second), Data: map[string]any{"tool_id": "stripe", "tool_method": "GET", "tool_url": "https://api.stripe.com/v1/invoices"}},
+		},
+		"run-2": {
+			{Seq: 1, Type: "USER_MESSAGE", Timestamp: start.Add(5 * time.Minute), Data: map[string]any{"content": "Refund it"}},
+			{Seq: 2, Type: "APPROVAL_REQUIRED", Timestamp: start.Add(5*time.Minute + time.Second), Data: map[string]any{"tool_id": "stripe", "capability": "refunds.create"}},
+		},
+	}
+	return runs, events
+}
+
+func TestBuildConversationTranscriptOrdersRunsAndInlinesGovernance(t *testing.T) {
+	runs, events := conversationFixture()
+	transcript := buildConversationTranscript("conv-1", runs, events, time.Now())
+
+	if len(transcript.Runs) != 2 || transcript.Runs[0].Run.ID != "run-1" {
+		t.Fatalf("expected runs oldest first, got %+v", transcript.Runs)
+	}
+	var kinds []string
+	for _, run := range transcript.Runs {
+		for _, entry := range run.Entries {
+			kinds = append(kinds, entry.Kind)
+		}
+	}
+	want := []string{transcriptUser, transcriptTool, transcriptAgent, transcriptUser, transcriptApproval}
+	if strings.Join(kinds, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected entries %v, got %v", want, kinds)
+	}
+	first := transcript.Runs[0].Entries[0]
+	if first.Text != "What did I pay last month?" || first.Actor != "alice@example.com" {
+		t.Fatalf("expected the initial message as the first user turn, got %+v", first)
+	}
+	if got := transcript.Runs[1].Entries[1].Text; got != "Approval required for stripe (refunds.create)" {
+		t.Fatalf("unexpected approval entry: %q", got)
+	}
+	if len(transcript.Users) != 1 || len(transcript.Agents) != 1 {
+		t.Fatalf("expected deduplicated participants, got %v %v", transcript.Users, transcript.Agents)
+	}
+}
+
+func TestRenderConversationMarkdownQuotesMessages(t *testing.T) {
+	runs, events := conversationFixture()
+	transcript := buildConversationTranscript("conv-1", runs, events, time.Date(2026, 4, 9, 12, 0, 0, 0, time.UTC))
+
+	var b strings.Builder
+	if err := renderConversationMarkdown(&b, transcript); err != nil {
+		t.Fatalf("renderConversationMarkdown: %v", err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"# Conversation: conv-1",
+		"## Run run-1",
+		"> You paid $42.\n> Anything else?",
+		"- _Tool_ (2026-04-09T10:00:01Z): Called stripe GET https://api.stripe.com/v1/invoices",
+		"- _Approval_ (2026-04-09T10:05:01Z): Approval required for stripe (refunds.create)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected markdown to contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "## Run run-1") > strings.Index(out, "## Run run-2") {
+		t.Fatalf("expected run-1 before run-2")
+	}
+}
@@ -2,16 +2,17 @@ package commands
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"regexp"
 	"sort"
-	"strconv"
 	"strings"
 	"time"
 
@@ -30,7 +31,6 @@ const (
 var (
 	reCopilotAgentAs    = regexp.MustCompile(`(?i)\bas\s+([a-z0-9][a-z0-9-]{1,62})\b`)
 	reCopilotAgentNamed = regexp.MustCompile(`(?i)\bnamed\s+([a-z0-9][a-z0-9-]{1,62})\b`)
-	reCopilotHTTPStatus = regexp.MustCompile(`HTTP\s+(\d{3})`)
 
 	copilotIgnoreDirs = map[string]struct{}{
 		".git":         {},
@@ -196,7 +196,7 @@ func newCopilotChatCmd() *cobra.Command {
 			if prompt == "" {
 				return fmt.Errorf("prompt cannot be empty")
 			}
-			return runCopilotChatPrompt(prompt, false, assumeYes)
+			return runCopilotChatPrompt(cmd.Context(), prompt, false, assumeYes)
 		},
 	}
 	cmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Auto-confirm local deploy-assist staging when prompt targets current folder")
@@ -209,7 +209,7 @@ func newCopilotDoctorCmd() *cobra.Command {
 		Short: "Run local and API readiness checks for Copilot CLI",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runCopilotDoctor()
+			return runCopilotDoctor(cmd.Context())
 		},
 	}
 }
@@ -220,7 +220,7 @@ func newCopilotPendingCmd() *cobra.Command {
 		Short: "List pending staged Copilot actions",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runCopilotPendingFetch()
+			return runCopilotPendingFetch(cmd.Context())
 		},
 	}
 }
@@ -231,7 +231,7 @@ func newCopilotConfirmCmd() *cobra.Command {
 		Short: "Confirm a staged Copilot action",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runCopilotResolveAction(args[0], true)
+			return runCopilotResolveAction(cmd.Context(), args[0], true)
 		},
 	}
 }
@@ -242,7 +242,7 @@ func newCopilotRejectCmd() *cobra.Command {
 		Short: "Reject a staged Copilot action",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runCopilotResolveAction(args[0], false)
+			return runCopilotResolveAction(cmd.Context(), args[0], false)
 		},
 	}
 }
@@ -253,7 +253,7 @@ func newCopilotShellCmd() *cobra.Command {
 		Short: "Start interactive Copilot shell",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runCopilotShell()
+			return runCopilotShell(cmd.Context())
 		},
 	}
 }
@@ -265,7 +265,7 @@ func newCopilotStatusCmd() *cobra.Command {
 		Short: "Show local Copilot session status for this project",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runCopilotStatus(refresh)
+			return runCopilotStatus(cmd.Context(), refresh)
 		},
 	}
 	cmd.Flags().BoolVar(&refresh, "refresh", false, "Refresh pending actions from API for active session")
@@ -291,87 +291,60 @@ func newCopilotResetCmd() *cobra.Command {
 	}
 }
 
-func runDefaultInteractiveShell() error {
-	return runCopilotShell()
+func runDefaultInteractiveShell(ctx context.Context) error {
+	return runCopilotShell(ctx)
 }
 
-func runCopilotShell() error {
+func runCopilotShell(ctx context.Context) error {
+	// The shell blocks reading stdin, which a cancelled context cannot
+	// interrupt, so keep the default Ctrl-C behaviour of exiting immediately.
+	signal.Reset(os.Interrupt)
+
 	fmt.Println("RunAgents Copilot shell")
 	fmt.Println("Type natural language requests, or use /help, /doctor, /status, /pending, /confirm <id>, /reject <id>, /reset, /exit")
 
-	scanner := bufio.NewScanner(os.Stdin)
-	for {
-		fmt.Print("runagents> ")
-		if !scanner.Scan() {
-			fmt.Println()
-			return nil
-		}
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" {
-			continue
-		}
+	return runLineShell(os.Stdin, os.Stdout, "runagents> ", func(line string) error {
 		switch {
-		case line == "/exit" || line == "exit" || line == "quit":
-			return nil
 		case line == "/help":
 			fmt.Println("/doctor, /status, /pending, /confirm <id>, /reject <id>, /reset, /exit")
-			continue
+			return nil
 		case line == "/doctor":
-			if err := runCopilotDoctor(); err != nil {
-				fmt.Printf("error: %v\n", err)
-			}
-			continue
+			return runCopilotDoctor(ctx)
 		case line == "/status":
-			if err := runCopilotStatus(false); err != nil {
-				fmt.Printf("error: %v\n", err)
-			}
-			continue
+			return runCopilotStatus(ctx, false)
 		case line == "/pending":
-			if err := runCopilotPendingFetch(); err != nil {
-				fmt.Printf("error: %v\n", err)
-			}
-			continue
+			return runCopilotPendingFetch(ctx)
 		case strings.HasPrefix(line, "/confirm "):
 			actionID := strings.TrimSpace(strings.TrimPrefix(line, "/confirm "))
 			if actionID == "" {
 				fmt.Println("usage: /confirm <action_id>")
-				continue
+				return nil
 			}
-			if err := runCopilotResolveAction(actionID, true); err != nil {
-				fmt.Printf("error: %v\n", err)
-			}
-			continue
+			return runCopilotResolveAction(ctx, actionID, true)
 		case strings.HasPrefix(line, "/reject "):
 			actionID := strings.TrimSpace(strings.TrimPrefix(line, "/reject "))
 			if actionID == "" {
 				fmt.Println("usage: /reject <action_id>")
-				continue
+				return nil
 			}
-			if err := runCopilotResolveAction(actionID, false); err != nil {
-				fmt.Printf("error: %v\n", err)
-			}
-			continue
+			return runCopilotResolveAction(ctx, actionID, false)
 		case line == "/reset":
 			cwd, err := os.Getwd()
 			if err != nil {
-				fmt.Printf("error: %v\n", err)
-				continue
+				return err
 			}
 			if err := config.ResetProjectState(cwd); err != nil {
-				fmt.Printf("error: %v\n", err)
-				continue
+				return err
 			}
 			fmt.Println("Session reset.")
-			continue
+			return nil
 		default:
-			if err := runCopilotChatPrompt(line, true, false); err != nil {
-				fmt.Printf("error: %v\n", err)
-			}
+			return runCopilotChatPrompt(ctx, line, true, false)
 		}
-	}
+	})
 }
 
-func runCopilotDoctor() error {
+func runCopilotDoctor(ctx context.Context) error {
 	endpoint, apiKey, err := resolvedAPISettings()
 	if err != nil {
 		return err
@@ -425,7 +398,7 @@ func runCopilotDoctor() error {
 	}
 
 	if endpointTrimmed != "" {
-		statusCode, probeErr := probeEndpointReachability(endpointTrimmed)
+		statusCode, probeErr := probeEndpointReachability(ctx, endpointTrimmed)
 		if probeErr != nil {
 			checks = append(checks, copilotDoctorCheck{
 				Name:    "network.endpoint_reachability",
@@ -443,9 +416,12 @@ func runCopilotDoctor() error {
 	}
 
 	if endpointTrimmed != "" && apiKeyTrimmed != "" {
-		c := client.NewClient(endpointTrimmed, apiKeyTrimmed)
+		c, clientErr := newAPIClient(ctx)
+		if clientErr != nil {
+			return clientErr
+		}
 		if _, getErr := c.Get("/tools"); getErr != nil {
-			statusCode := extractHTTPStatus(getErr)
+			statusCode := client.StatusCode(getErr)
 			status := "warn"
 			if statusCode == 0 || statusCode >= 500 || statusCode == 401 || statusCode == 403 || statusCode == 404 {
 				status = "fail"
@@ -525,7 +501,7 @@ func runCopilotDoctor() error {
 	return nil
 }
 
-func runCopilotStatus(refresh bool) error {
+func runCopilotStatus(ctx context.Context, refresh bool) error {
 	cwd, err := os.Getwd()
 	if err != nil {
 		return fmt.Errorf("failed to resolve current directory: %w", err)
@@ -536,7 +512,7 @@ func runCopilotStatus(refresh bool) error {
 	}
 
 	if refresh && strings.TrimSpace(state.SessionID) != "" {
-		c, err := newAPIClient()
+		c, err := newAPIClient(ctx)
 		if err != nil {
 			return err
 		}
@@ -610,8 +586,8 @@ func runCopilotStatus(refresh bool) error {
 	return nil
 }
 
-func runCopilotChatPrompt(prompt string, interactive, assumeYes bool) error {
-	c, cwd, state, err := copilotCommandContext()
+func runCopilotChatPrompt(ctx context.Context, prompt string, interactive, assumeYes bool) error {
+	c, cwd, state, err := copilotCommandContext(ctx)
 	if err != nil {
 		return err
 	}
@@ -670,8 +646,8 @@ func runCopilotChatPrompt(prompt string, interactive, assumeYes bool) error {
 	return nil
 }
 
-func runCopilotPendingFetch() error {
-	c, cwd, state, err := copilotCommandContext()
+func runCopilotPendingFetch(ctx context.Context) error {
+	c, cwd, state, err := copilotCommandContext(ctx)
 	if err != nil {
 		return err
 	}
@@ -703,12 +679,12 @@ func runCopilotPendingFetch() error {
 	return nil
 }
 
-func runCopilotResolveAction(actionID string, approve bool) error {
+func runCopilotResolveAction(ctx context.Context, actionID string, approve bool) error {
 	actionID = strings.TrimSpace(actionID)
 	if actionID == "" {
 		return fmt.Errorf("action_id is required")
 	}
-	c, cwd, state, err := copilotCommandContext()
+	c, cwd, state, err := copilotCommandContext(ctx)
 	if err != nil {
 		return err
 	}
@@ -747,8 +723,8 @@ func runCopilotResolveAction(actionID string, approve bool) error {
 	return nil
 }
 
-func copilotCommandContext() (*client.Client, string, *config.ProjectState, error) {
-	c, err := newAPIClient()
+func copilotCommandContext(ctx context.Context) (*client.Client, string, *config.ProjectState, error) {
+	c, err := newAPIClient(ctx)
 	if err != nil {
 		return nil, "", nil, err
 	}
@@ -835,7 +811,7 @@ func memorySummaryFromResponse(resp copilotChatResponsePayload) string {
 	return "no updates"
 }
 
-func probeEndpointReachability(endpoint string) (int, error) {
+func probeEndpointReachability(ctx context.Context, endpoint string) (int, error) {
 	u := strings.TrimSpace(endpoint)
 	if u == "" {
 		return 0, fmt.Errorf("endpoint is empty")
@@ -844,7 +820,7 @@ func probeEndpointReachability(endpoint string) (int, error) {
 		return 0, fmt.Errorf("invalid endpoint URL: %w", err)
 	}
 	target := strings.TrimRight(u, "/") + "/"
-	req, err := http.NewRequest(http.MethodGet, target, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
 	if err != nil {
 		return 0, fmt.Errorf("failed to prepare request: %w", err)
 	}
@@ -857,21 +833,6 @@ func probeEndpointReachability(endpoint string) (int, error) {
 	return resp.StatusCode, nil
 }
 
-func extractHTTPStatus(err error) int {
-	if err == nil {
-		return 0
-	}
-	matches := reCopilotHTTPStatus.FindStringSubmatch(err.Error())
-	if len(matches) < 2 {
-		return 0
-	}
-	code, convErr := strconv.Atoi(matches[1])
-	if convErr != nil {
-		return 0
-	}
-	return code
-}
-
 func shouldProceedWithDeployAssist(interactive, assumeYes bool, input io.Reader, output io.Writer) (bool, error) {
 	if assumeYes {
 		return true, nil
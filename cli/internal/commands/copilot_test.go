@@ -2,7 +2,6 @@ package commands
 
 import (
 	"bytes"
-	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -135,16 +134,6 @@ func TestShouldProceedWithDeployAssistInteractiveNo(t *testing.T) {
 	}
 }
 
-func TestExtractHTTPStatus(t *testing.T) {
-	code := extractHTTPStatus(errors.New("API error (HTTP 403): forbidden"))
-	if code != 403 {
-		t.Fatalf("expected 403, got %d", code)
-	}
-	if got := extractHTTPStatus(errors.New("some random error")); got != 0 {
-		t.Fatalf("expected 0 for unparsable error, got %d", got)
-	}
-}
-
 func TestEnsureCopilotEnabledByAssistantMode(t *testing.T) {
 	original, had := os.LookupEnv("RUNAGENTS_ASSISTANT_MODE")
 	defer func() {
@@ -1,19 +1,25 @@
 package commands
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/runagents/runagents/cli/internal/client"
+	"github.com/runagents/runagents/cli/internal/config"
 	"github.com/spf13/cobra"
 )
 
 type deployOptions struct {
 	Name             string
 	Files            []string
+	SourceDir        string
 	Tools            []string
 	ModelFlag        string
+	SystemPrompt     string
 	Policies         []string
 	IdentityProvider string
 	RequirementsFile string
@@ -23,69 +29,157 @@ type deployOptions struct {
 	ArtifactID       string
 }
 
+// deployRunOptions control what deploy does with each agent's payload.
+type deployRunOptions struct {
+	Preview     bool
+	Wait        bool
+	WaitTimeout time.Duration
+	Interval    time.Duration
+	ForceUpload bool
+}
+
 func newDeployCmd() *cobra.Command {
-	var opts deployOptions
+	var (
+		opts         deployOptions
+		run          deployRunOptions
+		manifestPath string
+		noManifest   bool
+		agents       []string
+		limits       = defaultDeployBundleLimits()
+	)
 
 	cmd := &cobra.Command{
 		Use:   "deploy",
 		Short: "Deploy an agent",
 		Long: `Deploy an agent from source files, a deploy draft, or an existing artifact.
 
+Without flags, deploy reads the runagents.yaml project manifest in the
+current directory and deploys every agent it defines. Flags override the
+manifest's values; --agent picks agents from a multi-agent manifest.
+
 Examples:
+  runagents deploy
+  runagents deploy --agent account-agent --model openai/gpt-4o
   runagents deploy --name my-agent --file agent.py --tool echo-tool --model openai/gpt-4o-mini
   runagents deploy --name billing-agent --draft-id draft_billing_v2 --policy billing-write-approval
-  runagents deploy --name support-agent --artifact-id art_support_v3 --identity-provider google-oidc`,
+  runagents deploy --name support-agent --artifact-id art_support_v3 --identity-provider google-oidc
+  runagents deploy --name my-agent --file agent.py --wait
+  runagents deploy --name my-agent --dir . --entry-point agent.py
+  runagents deploy --name billing-agent --file agent.py --policy billing-write-approval --preview
+
+With --dir, deploy every file under a directory except those matched by
+.gitignore or .runagentsignore files, or skipped for size (--max-file-size)
+or being binary. The bundle manifest is printed before sending. When the
+bundle is unchanged since the last deploy of the agent and that build
+succeeded, the earlier image is reused instead of uploading again;
+--force-upload always uploads.
+
+With --preview, show what the deploy would create or change without
+deploying: the runtime it would get, tools it would register, policy
+bindings, tools that will need approval, identity provider, and model
+budgets, followed by warnings to review.
+
+With --wait, follow the build and the agent until the agent is running.
+Build failures print the build logs and exit with 14; running out of
+--wait-timeout exits with 13.`,
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if run.Preview && run.Wait {
+				return fmt.Errorf("--preview and --wait cannot be used together")
+			}
 			cwd, err := os.Getwd()
 			if err != nil {
 				return fmt.Errorf("failed to determine current directory: %w", err)
 			}
 
-			payload, err := buildDeployPayload(opts, cwd)
+			var manifest *deployManifest
+			if manifestPath == "" && !noManifest {
+				if manifestPath, err = findDeployManifest(cwd); err != nil {
+					return err
+				}
+			}
+			if manifestPath != "" && !noManifest {
+				if manifest, err = loadDeployManifest(manifestPath); err != nil {
+					return err
+				}
+			}
+			targets, err := resolveDeployTargets(manifest, agents, opts, cmd.Flags().Changed, cwd)
 			if err != nil {
 				return err
 			}
 
-			c, err := newAPIClient()
+			payloads := make([]map[string]any, 0, len(targets))
+			bundles := make([]*deployBundle, len(targets))
+			for i := range targets {
+				payload, bundle, err := buildDeployTargetPayload(&targets[i], limits)
+				if err != nil {
+					if len(targets) > 1 {
+						return fmt.Errorf("agent %q: %w", targets[i].Options.Name, err)
+					}
+					return err
+				}
+				payloads, bundles[i] = append(payloads, payload), bundle
+			}
+
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
-
-			data, err := c.Post("/deploy", payload)
+			endpoint, _, err := resolvedAPISettings()
 			if err != nil {
 				return err
 			}
-
+			bundleOut := os.Stdout
 			if isJSONOutput() {
-				fmt.Println(string(data))
-				return nil
-			}
-
-			var result map[string]interface{}
-			if err := json.Unmarshal(data, &result); err != nil {
-				fmt.Println("Deploy request submitted.")
-				return nil
+				bundleOut = os.Stderr
 			}
 
-			fmt.Printf("Agent %q deployed successfully.\n", strings.TrimSpace(opts.Name))
-			if agent, ok := result["agent"]; ok {
-				fmt.Printf("Agent: %v\n", agent)
-			}
-			if buildID, ok := result["build_id"]; ok && buildID != "" {
-				fmt.Printf("Build ID: %v\n", buildID)
+			results := make([]any, 0, len(targets))
+			for i, target := range targets {
+				if i > 0 && !isJSONOutput() {
+					fmt.Println()
+				}
+				var (
+					recordUpload func(map[string]any)
+					reused       bool
+				)
+				if bundle := bundles[i]; bundle != nil {
+					printDeployBundle(bundleOut, bundle)
+					if !run.Preview {
+						key := config.DeployBundleKey(endpoint, target.Options.Name)
+						recordUpload = reuseDeployedBundle(c, bundle, key, payloads[i], run.ForceUpload, bundleOut)
+						reused = recordUpload == nil
+					}
+					fmt.Fprintln(bundleOut)
+				}
+				result, err := deployAgent(cmd.Context(), c, target.Options.Name, payloads[i], run, recordUpload, reused)
+				if result != nil {
+					results = append(results, result)
+				}
+				if err != nil {
+					if isJSONOutput() && len(results) > 0 {
+						_ = printDeployResults(results)
+					}
+					return err
+				}
 			}
-			if createdTools, ok := result["tools_created"]; ok {
-				fmt.Printf("Tools created: %v\n", createdTools)
+			if isJSONOutput() {
+				return printDeployResults(results)
 			}
-
 			return nil
 		},
 	}
 
-	cmd.Flags().StringVar(&opts.Name, "name", "", "Agent name (required)")
+	cmd.Flags().StringVar(&opts.Name, "name", "", "Agent name (required without a runagents.yaml manifest)")
 	cmd.Flags().StringArrayVar(&opts.Files, "file", nil, "Source file(s) to deploy (repeatable)")
+	cmd.Flags().StringVar(&opts.SourceDir, "dir", "", "Deploy every file in a directory, honoring .gitignore and .runagentsignore")
+	cmd.Flags().Var(&limits.MaxFileSize, "max-file-size", "With --dir, skip files larger than this")
+	cmd.Flags().Var(&limits.MaxBundleSize, "max-bundle-size", "With --dir, fail when the files to upload add up to more than this")
+	cmd.Flags().IntVar(&limits.MaxFiles, "max-files", limits.MaxFiles, "With --dir, fail when there are more files than this to upload")
+	cmd.Flags().BoolVar(&run.ForceUpload, "force-upload", false, "With --dir, upload the bundle even when it is unchanged since the last deploy")
 	cmd.Flags().StringArrayVar(&opts.Tools, "tool", nil, "Required tool name(s) (repeatable)")
 	cmd.Flags().StringVar(&opts.ModelFlag, "model", "", "Model in provider/model format (for example openai/gpt-4o-mini)")
+	cmd.Flags().StringVar(&opts.SystemPrompt, "system-prompt", "", "System prompt for the agent")
 	cmd.Flags().StringArrayVar(&opts.Policies, "policy", nil, "Attach policies during deploy (repeatable)")
 	cmd.Flags().StringVar(&opts.IdentityProvider, "identity-provider", "", "Bind an identity provider during deploy")
 	cmd.Flags().StringVar(&opts.RequirementsFile, "requirements-file", "", "Path to a requirements file to include with source deploys")
@@ -93,15 +187,130 @@ Examples:
 	cmd.Flags().StringVar(&opts.Framework, "framework", "", "Framework hint for source deploys (for example langgraph)")
 	cmd.Flags().StringVar(&opts.DraftID, "draft-id", "", "Deploy from an existing deploy draft")
 	cmd.Flags().StringVar(&opts.ArtifactID, "artifact-id", "", "Deploy from an existing workflow artifact")
-	_ = cmd.MarkFlagRequired("name")
+	cmd.Flags().StringVar(&manifestPath, "manifest", "", "Project manifest to deploy (default runagents.yaml in the current directory)")
+	cmd.Flags().BoolVar(&noManifest, "no-manifest", false, "Ignore runagents.yaml and deploy from flags only")
+	cmd.Flags().StringArrayVar(&agents, "agent", nil, "Deploy only this agent from the manifest (repeatable)")
+	cmd.Flags().BoolVar(&run.Preview, "preview", false, "Show what the deploy would create or change, without deploying")
+	cmd.Flags().BoolVar(&run.Wait, "wait", false, "Wait for the build to finish and the agent to start")
+	cmd.Flags().DurationVar(&run.WaitTimeout, "wait-timeout", 15*time.Minute, "Maximum time to wait with --wait")
+	cmd.Flags().DurationVar(&run.Interval, "interval", 5*time.Second, "Polling interval with --wait")
 
 	return cmd
 }
 
+// deployAgent previews or deploys one agent, printing text output as it
+// goes, and returns the value to print with -o json. reused reports that
+// payload carries the image of an unchanged --dir bundle.
+func deployAgent(ctx context.Context, c *client.Client, name string, payload map[string]any, run deployRunOptions, recordUpload func(map[string]any), reused bool) (any, error) {
+	if run.Preview {
+		result, err := previewDeploy(c, payload)
+		if err != nil {
+			return nil, err
+		}
+		if !isJSONOutput() {
+			printDeployPreview(os.Stdout, result)
+		}
+		return result, nil
+	}
+
+	var waitTarget deployWaitTarget
+	if run.Wait {
+		waitTarget = agentStateBeforeDeploy(c, name)
+	}
+	data, err := c.Post("/deploy", payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var result map[string]interface{}
+	parseErr := json.Unmarshal(data, &result)
+	if parseErr == nil && recordUpload != nil {
+		recordUpload(result)
+	}
+	waitTarget.Agent = deployedAgentName(result, name)
+	waitTarget.BuildID = stringField(result, "build_id")
+	waitTarget.Image = firstNonEmptyRunValue(stringField(result, "image_uri"), stringField(payload, "image"))
+	waitTarget.Reused = reused
+	if isJSONOutput() {
+		if !run.Wait {
+			return json.RawMessage(data), nil
+		}
+		waited, err := waitForDeploy(ctx, c, waitTarget, run.Interval, run.WaitTimeout, os.Stderr, os.Stderr)
+		return map[string]any{"deploy": json.RawMessage(data), "build": waited.Build, "agent": waited.Agent}, err
+	}
+
+	if parseErr != nil {
+		fmt.Println("Deploy request submitted.")
+		if run.Wait {
+			return nil, fmt.Errorf("cannot wait for the deploy: failed to parse deploy response: %w", parseErr)
+		}
+		return nil, nil
+	}
+
+	fmt.Printf("Agent %q deployed successfully.\n", strings.TrimSpace(name))
+	if agent, ok := result["agent"]; ok {
+		fmt.Printf("Agent: %v\n", agent)
+	}
+	if buildID, ok := result["build_id"]; ok && buildID != "" {
+		fmt.Printf("Build ID: %v\n", buildID)
+	}
+	if createdTools, ok := result["tools_created"]; ok {
+		fmt.Printf("Tools created: %v\n", createdTools)
+	}
+	if !run.Wait {
+		return nil, nil
+	}
+
+	fmt.Println()
+	if _, err := waitForDeploy(ctx, c, waitTarget, run.Interval, run.WaitTimeout, os.Stdout, os.Stderr); err != nil {
+		return nil, err
+	}
+	fmt.Printf("Agent %q is running.\n", waitTarget.Agent)
+	return nil, nil
+}
+
+// printDeployResults prints one JSON value per deployed agent: the value
+// itself for a single agent, or an array.
+func printDeployResults(results []any) error {
+	if len(results) == 1 {
+		return printJSONValue(results[0])
+	}
+	return printJSONValue(results)
+}
+
+// deployedAgentName is the agent name from the deploy response, falling back
+// to the requested name.
+func deployedAgentName(result map[string]interface{}, requested string) string {
+	if name := stringField(result, "agent"); name != "" {
+		return name
+	}
+	return strings.TrimSpace(requested)
+}
+
+// buildDeployTargetPayload builds the deploy payload for a target, first
+// collecting the bundle when it deploys a --dir.
+func buildDeployTargetPayload(target *deployTarget, limits deployBundleLimits) (map[string]any, *deployBundle, error) {
+	var bundle *deployBundle
+	if strings.TrimSpace(target.Options.SourceDir) != "" {
+		var err error
+		if bundle, err = expandDeployDir(target, limits); err != nil {
+			return nil, nil, err
+		}
+	}
+	payload, err := buildDeployPayload(target.Options, target.Dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	if bundle != nil {
+		bundle.Hash = deployBundleHash(payload)
+	}
+	return payload, bundle, nil
+}
+
 func buildDeployPayload(opts deployOptions, cwd string) (map[string]any, error) {
 	agentName := strings.TrimSpace(opts.Name)
 	if agentName == "" {
-		return nil, fmt.Errorf("--name is required")
+		return nil, fmt.Errorf("--name is required when there is no runagents.yaml manifest")
 	}
 
 	mode, err := resolveDeploySourceMode(opts)
@@ -148,6 +357,9 @@ func buildDeployPayload(opts deployOptions, cwd string) (map[string]any, error)
 	if len(opts.Policies) > 0 {
 		payload["policies"] = normalizedNonEmptyStrings(opts.Policies)
 	}
+	if strings.TrimSpace(opts.SystemPrompt) != "" {
+		payload["system_prompt"] = strings.TrimSpace(opts.SystemPrompt)
+	}
 	if strings.TrimSpace(opts.IdentityProvider) != "" {
 		payload["identity_provider"] = strings.TrimSpace(opts.IdentityProvider)
 	}
@@ -167,6 +379,9 @@ func resolveDeploySourceMode(opts deployOptions) (string, error) {
 	if len(opts.Files) > 0 {
 		sourceModes++
 	}
+	if strings.TrimSpace(opts.SourceDir) != "" {
+		sourceModes++
+	}
 	if strings.TrimSpace(opts.DraftID) != "" {
 		sourceModes++
 	}
@@ -174,27 +389,30 @@ func resolveDeploySourceMode(opts deployOptions) (string, error) {
 		sourceModes++
 	}
 	if sourceModes == 0 {
-		return "", fmt.Errorf("provide exactly one deploy source: --file, --draft-id, or --artifact-id")
+		return "", fmt.Errorf("provide exactly one deploy source: --file, --dir, --draft-id, or --artifact-id")
 	}
 	if sourceModes > 1 {
-		return "", fmt.Errorf("provide only one deploy source: --file, --draft-id, or --artifact-id")
+		return "", fmt.Errorf("provide only one deploy source: --file, --dir, --draft-id, or --artifact-id")
 	}
 
-	if len(opts.Files) == 0 {
+	if len(opts.Files) == 0 && strings.TrimSpace(opts.SourceDir) == "" {
 		if strings.TrimSpace(opts.RequirementsFile) != "" {
-			return "", fmt.Errorf("--requirements-file can only be used with --file")
+			return "", fmt.Errorf("--requirements-file can only be used with --file or --dir")
 		}
 		if strings.TrimSpace(opts.EntryPoint) != "" {
-			return "", fmt.Errorf("--entry-point can only be used with --file")
+			return "", fmt.Errorf("--entry-point can only be used with --file or --dir")
 		}
 		if strings.TrimSpace(opts.Framework) != "" {
-			return "", fmt.Errorf("--framework can only be used with --file")
+			return "", fmt.Errorf("--framework can only be used with --file or --dir")
 		}
 	}
 
 	if len(opts.Files) > 0 {
 		return "files", nil
 	}
+	if strings.TrimSpace(opts.SourceDir) != "" {
+		return "dir", nil
+	}
 	if strings.TrimSpace(opts.DraftID) != "" {
 		return "draft", nil
 	}
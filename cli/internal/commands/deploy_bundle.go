@@ -0,0 +1,566 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/runagents/runagents/cli/internal/config"
+)
+
+// deployIgnoreFiles are read in every directory of a --dir deploy. Later
+// files and deeper directories take precedence, so .runagentsignore can
+// re-include (!pattern) something .gitignore leaves out.
+var deployIgnoreFiles = []string{".gitignore", ".runagentsignore"}
+
+// defaultDeployIgnorePatterns apply before any ignore file.
+var defaultDeployIgnorePatterns = []string{
+	".git/",
+	".runagents/",
+	"__pycache__/",
+	"*.pyc",
+	".venv/",
+	"venv/",
+	"node_modules/",
+	".DS_Store",
+}
+
+// deploySecretIgnorePatterns leave out files that commonly hold credentials.
+// They apply before any ignore file, so .runagentsignore can re-include one
+// with !pattern.
+var deploySecretIgnorePatterns = []string{
+	".env",
+	".env.*",
+	"*.pem",
+	"*.key",
+	"*.p12",
+	"*.pfx",
+	"id_rsa*",
+	"id_ecdsa*",
+	"id_ed25519*",
+	"credentials.json",
+	".npmrc",
+	".pypirc",
+	".netrc",
+}
+
+// deploySecretIgnoreSource marks rules from deploySecretIgnorePatterns.
+const deploySecretIgnoreSource = "secret patterns"
+
+const (
+	defaultDeployMaxFileSize   = 512 << 10
+	defaultDeployMaxBundleSize = 5 << 20
+	defaultDeployMaxFiles      = 500
+)
+
+// deployBundleLimits cap what a --dir deploy uploads.
+type deployBundleLimits struct {
+	MaxFileSize   byteSize
+	MaxBundleSize byteSize
+	MaxFiles      int
+}
+
+func defaultDeployBundleLimits() deployBundleLimits {
+	return deployBundleLimits{
+		MaxFileSize:   defaultDeployMaxFileSize,
+		MaxBundleSize: defaultDeployMaxBundleSize,
+		MaxFiles:      defaultDeployMaxFiles,
+	}
+}
+
+type deployBundleFile struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+	abs    string
+}
+
+type deployBundleSkip struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+// deployBundle is the set of files a --dir deploy uploads, with everything
+// it left out and why.
+type deployBundle struct {
+	Root    string             `json:"root"`
+	Hash    string             `json:"hash,omitempty"`
+	Size    int64              `json:"size"`
+	Files   []deployBundleFile `json:"files"`
+	Skipped []deployBundleSkip `json:"skipped,omitempty"`
+}
+
+// Paths returns the absolute paths of the bundled files.
+func (b *deployBundle) Paths() []string {
+	paths := make([]string, 0, len(b.Files))
+	for _, file := range b.Files {
+		paths = append(paths, file.abs)
+	}
+	return paths
+}
+
+// collectDeployBundle walks dir, honoring ignore files, and returns the
+// source files to upload. Files over the size limit, binary files, and
+// symlinks are skipped and reported; going over the file count or total
+// size fails rather than uploading a partial bundle.
+func collectDeployBundle(dir string, limits deployBundleLimits) (*deployBundle, error) {
+	root, err := filepath.Abs(strings.TrimSpace(dir))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve --dir %q: %w", dir, err)
+	}
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read --dir %q: %w", dir, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("--dir %q is not a directory", dir)
+	}
+
+	var rules ignoreRules
+	for _, defaults := range []struct {
+		source   string
+		patterns []string
+	}{
+		{"default", defaultDeployIgnorePatterns},
+		{deploySecretIgnoreSource, deploySecretIgnorePatterns},
+	} {
+		for _, pattern := range defaults.patterns {
+			rule, err := parseIgnoreRule(pattern, "", defaults.source)
+			if err != nil {
+				return nil, err
+			}
+			rules = append(rules, *rule)
+		}
+	}
+
+	bundle := &deployBundle{Root: root}
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel != "." {
+			if ignored, source := rules.match(rel, d.IsDir()); ignored {
+				skipped := rel
+				if d.IsDir() {
+					skipped += "/"
+				}
+				reason := "ignored by " + source
+				if source == deploySecretIgnoreSource {
+					reason = "possible secret"
+				}
+				bundle.Skipped = append(bundle.Skipped, deployBundleSkip{Path: skipped, Reason: reason})
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+		}
+
+		if d.IsDir() {
+			base := rel
+			if base == "." {
+				base = ""
+			}
+			for _, name := range deployIgnoreFiles {
+				more, err := loadIgnoreFile(root, base, name)
+				if err != nil {
+					return err
+				}
+				rules = append(rules, more...)
+			}
+			return nil
+		}
+
+		if d.Type()&fs.ModeSymlink != 0 {
+			bundle.Skipped = append(bundle.Skipped, deployBundleSkip{Path: rel, Reason: "symlink"})
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			bundle.Skipped = append(bundle.Skipped, deployBundleSkip{Path: rel, Reason: "not a regular file"})
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.Size() > int64(limits.MaxFileSize) {
+			bundle.Skipped = append(bundle.Skipped, deployBundleSkip{
+				Path:   rel,
+				Reason: fmt.Sprintf("%s is over --max-file-size %s", formatBytes(info.Size()), limits.MaxFileSize.String()),
+			})
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read file %q: %w", rel, err)
+		}
+		if !utf8.Valid(data) || bytes.IndexByte(data, 0) >= 0 {
+			bundle.Skipped = append(bundle.Skipped, deployBundleSkip{Path: rel, Reason: "binary file"})
+			return nil
+		}
+
+		if len(bundle.Files) >= limits.MaxFiles {
+			return fmt.Errorf("more than %d files to upload; raise --max-files or add paths to .runagentsignore", limits.MaxFiles)
+		}
+		if bundle.Size+int64(len(data)) > int64(limits.MaxBundleSize) {
+			return fmt.Errorf("bundle is over --max-bundle-size %s at %s; raise the limit or add paths to .runagentsignore", limits.MaxBundleSize.String(), rel)
+		}
+		sum := sha256.Sum256(data)
+		bundle.Files = append(bundle.Files, deployBundleFile{Path: rel, Size: int64(len(data)), SHA256: hex.EncodeToString(sum[:]), abs: path})
+		bundle.Size += int64(len(data))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(bundle.Files) == 0 {
+		return nil, fmt.Errorf("no files to deploy in %s after applying ignore rules and size limits", root)
+	}
+	return bundle, nil
+}
+
+// expandDeployDir collects the bundle for a --dir target and turns the target
+// into a file deploy of its files. A requirements.txt at the top of the
+// bundle is used as the requirements file unless one was given.
+func expandDeployDir(target *deployTarget, limits deployBundleLimits) (*deployBundle, error) {
+	if _, err := resolveDeploySourceMode(target.Options); err != nil {
+		return nil, err
+	}
+	bundle, err := collectDeployBundle(target.Options.SourceDir, limits)
+	if err != nil {
+		return nil, err
+	}
+	opts := &target.Options
+	opts.Files, opts.SourceDir = bundle.Paths(), ""
+	target.Dir = bundle.Root
+	if strings.TrimSpace(opts.RequirementsFile) == "" && slices.ContainsFunc(bundle.Files, func(file deployBundleFile) bool { return file.Path == "requirements.txt" }) {
+		opts.RequirementsFile = filepath.Join(bundle.Root, "requirements.txt")
+	}
+	return bundle, nil
+}
+
+// reuseDeployedBundle swaps an unchanged bundle for the image it built last
+// time, unless force is set. Otherwise it returns a function that records
+// the bundle from the deploy response, so the next deploy can reuse it.
+func reuseDeployedBundle(c interface{ Get(string) ([]byte, error) }, bundle *deployBundle, key string, payload map[string]any, force bool, w io.Writer) func(map[string]any) {
+	recorded, err := config.LoadDeployedBundles(bundle.Root)
+	if err != nil {
+		fmt.Fprintf(w, "Ignoring recorded bundles: %v\n", err)
+	}
+	if !force && err == nil {
+		if image := reusableBundleImage(c, recorded[key], bundle.Hash); image != "" {
+			useBundleImage(payload, image)
+			fmt.Fprintf(w, "Bundle unchanged since %s; reusing image %s instead of uploading.\n", formatRunTime(recorded[key].DeployedAt), image)
+			return nil
+		}
+	}
+
+	return func(result map[string]any) {
+		image := stringField(result, "image_uri")
+		if image == "" {
+			return
+		}
+		err := config.SaveDeployedBundle(bundle.Root, key, config.DeployedBundle{
+			Hash:    bundle.Hash,
+			Image:   image,
+			BuildID: stringField(result, "build_id"),
+			Files:   len(bundle.Files),
+		})
+		if err != nil {
+			fmt.Fprintf(w, "Failed to record the deployed bundle: %v\n", err)
+		}
+	}
+}
+
+// deployBundleHash hashes the parts of a source deploy payload that go into
+// the build: source files, requirements, entry point, and framework.
+func deployBundleHash(payload map[string]any) string {
+	h := sha256.New()
+	sources, _ := payload["source_files"].(map[string]string)
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		sum := sha256.Sum256([]byte(sources[name]))
+		fmt.Fprintf(h, "file %s %x\n", filepath.ToSlash(name), sum)
+	}
+	for _, key := range []string{"requirements", "entry_point", "framework"} {
+		value, _ := payload[key].(string)
+		sum := sha256.Sum256([]byte(value))
+		fmt.Fprintf(h, "%s %x\n", key, sum)
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
+
+// reusableBundleImage returns the image built from the recorded bundle when
+// it has the same hash and its build succeeded, or "" to upload the bundle.
+func reusableBundleImage(c interface{ Get(string) ([]byte, error) }, recorded config.DeployedBundle, hash string) string {
+	if recorded.Hash != hash || recorded.Image == "" {
+		return ""
+	}
+	if recorded.BuildID != "" {
+		build, err := fetchBuild(c, recorded.BuildID)
+		if err != nil || strings.ToUpper(build.Status) != "SUCCEEDED" {
+			return ""
+		}
+	}
+	return recorded.Image
+}
+
+// useBundleImage turns a source deploy payload into an image deploy.
+func useBundleImage(payload map[string]any, image string) {
+	for _, key := range []string{"source_files", "requirements", "entry_point", "framework"} {
+		delete(payload, key)
+	}
+	payload["image"] = image
+}
+
+// printDeployBundle prints the files a --dir deploy will upload and the
+// ones it skipped.
+func printDeployBundle(w io.Writer, bundle *deployBundle) {
+	fmt.Fprintf(w, "Bundle %s: %d files, %s", bundle.Root, len(bundle.Files), formatBytes(bundle.Size))
+	if bundle.Hash != "" {
+		fmt.Fprintf(w, ", %s", shortBundleHash(bundle.Hash))
+	}
+	fmt.Fprintln(w)
+
+	width := 0
+	for _, file := range bundle.Files {
+		width = max(width, len(file.Path))
+	}
+	for _, skip := range bundle.Skipped {
+		width = max(width, len(skip.Path))
+	}
+	for _, file := range bundle.Files {
+		fmt.Fprintf(w, "  %-*s  %10s  %s\n", width, file.Path, formatBytes(file.Size), file.SHA256[:12])
+	}
+	if len(bundle.Skipped) > 0 {
+		fmt.Fprintf(w, "Skipped (%d):\n", len(bundle.Skipped))
+		for _, skip := range bundle.Skipped {
+			fmt.Fprintf(w, "  %-*s  %s\n", width, skip.Path, skip.Reason)
+		}
+	}
+}
+
+func shortBundleHash(hash string) string {
+	if len(hash) > len("sha256:")+12 {
+		return hash[:len("sha256:")+12]
+	}
+	return hash
+}
+
+// ignoreRule is one line of a .gitignore-style file.
+type ignoreRule struct {
+	base    string
+	pattern *regexp.Regexp
+	negate  bool
+	dirOnly bool
+	source  string
+}
+
+type ignoreRules []ignoreRule
+
+// match reports whether rel (slash-separated, relative to the bundle root)
+// is ignored, and the rule that decided it. The last matching rule wins.
+func (rules ignoreRules) match(rel string, isDir bool) (bool, string) {
+	ignored, source := false, ""
+	for _, rule := range rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		path := rel
+		if rule.base != "" {
+			if !strings.HasPrefix(rel, rule.base+"/") {
+				continue
+			}
+			path = strings.TrimPrefix(rel, rule.base+"/")
+		}
+		if rule.pattern.MatchString(path) {
+			ignored, source = !rule.negate, rule.source
+		}
+	}
+	return ignored, source
+}
+
+// loadIgnoreFile reads name from the base directory under root. A missing
+// file has no rules.
+func loadIgnoreFile(root, base, name string) ([]ignoreRule, error) {
+	rel := name
+	if base != "" {
+		rel = base + "/" + name
+	}
+	file, err := os.Open(filepath.Join(root, filepath.FromSlash(rel)))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", rel, err)
+	}
+	defer file.Close()
+
+	var rules []ignoreRule
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		rule, err := parseIgnoreRule(scanner.Text(), base, rel+":"+strconv.Itoa(line))
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", rel, line, err)
+		}
+		if rule != nil {
+			rules = append(rules, *rule)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", rel, err)
+	}
+	return rules, nil
+}
+
+// parseIgnoreRule parses one gitignore line: blank lines and # comments are
+// skipped, ! negates, a trailing / matches only directories, and a pattern
+// with a / other than at the end is anchored to the ignore file's directory.
+func parseIgnoreRule(line, base, source string) (*ignoreRule, error) {
+	line = strings.TrimRight(line, "\r")
+	if !strings.HasSuffix(line, `\ `) {
+		line = strings.TrimRight(line, " \t")
+	}
+	if line == "" || strings.HasPrefix(line, "#") {
+		return nil, nil
+	}
+
+	rule := &ignoreRule{base: base, source: source}
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return nil, nil
+	}
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+
+	var expr strings.Builder
+	if anchored {
+		expr.WriteString("^")
+	} else {
+		expr.WriteString("^(?:.*/)?")
+	}
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case strings.HasPrefix(line[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case line[i:] == "/**":
+			expr.WriteString("/.*")
+			i += 2
+		case strings.HasPrefix(line[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case c == '*':
+			expr.WriteString("[^/]*")
+		case c == '?':
+			expr.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(line[i+1:], ']')
+			if end < 0 {
+				expr.WriteString(`\[`)
+				continue
+			}
+			class := line[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			expr.WriteString("[" + class + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(line):
+			expr.WriteString(regexp.QuoteMeta(line[i+1 : i+2]))
+			i++
+		default:
+			expr.WriteString(regexp.QuoteMeta(line[i : i+1]))
+		}
+	}
+	expr.WriteString("$")
+
+	pattern, err := regexp.Compile(expr.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid ignore pattern %q: %w", line, err)
+	}
+	rule.pattern = pattern
+	return rule, nil
+}
+
+// byteSize is a flag value that accepts sizes like 512KiB, 5MB, or 1048576.
+// Units are powers of 1024.
+type byteSize int64
+
+func (s *byteSize) String() string { return formatBytes(int64(*s)) }
+
+func (s *byteSize) Type() string { return "size" }
+
+func (s *byteSize) Set(value string) error {
+	n, err := parseByteSize(value)
+	if err != nil {
+		return err
+	}
+	*s = byteSize(n)
+	return nil
+}
+
+func parseByteSize(value string) (int64, error) {
+	trimmed := strings.TrimSpace(value)
+	split := strings.IndexFunc(trimmed, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+	number, unit := trimmed, ""
+	if split >= 0 {
+		number, unit = trimmed[:split], strings.TrimSpace(trimmed[split:])
+	}
+	multipliers := map[string]int64{
+		"": 1, "B": 1,
+		"K": 1 << 10, "KB": 1 << 10, "KIB": 1 << 10,
+		"M": 1 << 20, "MB": 1 << 20, "MIB": 1 << 20,
+		"G": 1 << 30, "GB": 1 << 30, "GIB": 1 << 30,
+	}
+	multiplier, ok := multipliers[strings.ToUpper(unit)]
+	n, err := strconv.ParseFloat(number, 64)
+	if !ok || err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid size %q (use a value like 512KiB or 5MiB)", value)
+	}
+	return int64(n * float64(multiplier)), nil
+}
+
+func formatBytes(n int64) string {
+	if n < 1<<10 {
+		return fmt.Sprintf("%d B", n)
+	}
+	value, unit := float64(n), ""
+	for _, u := range []string{"KiB", "MiB", "GiB"} {
+		value, unit = value/1024, u
+		if value < 1024 {
+			break
+		}
+	}
+	if value == float64(int64(value)) {
+		return fmt.Sprintf("%d %s", int64(value), unit)
+	}
+	return fmt.Sprintf("%.1f %s", value, unit)
+}
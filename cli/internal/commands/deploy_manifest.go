@@ -0,0 +1,395 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// deployManifestNames are the project manifests deploy looks for in the
+// current directory, in order.
+var deployManifestNames = []string{"runagents.yaml", "runagents.yml"}
+
+var deployAgentNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// deployManifest is a runagents.yaml project manifest. It describes a single
+// agent at the top level, or several agents under agents.
+type deployManifest struct {
+	Path   string
+	Agents []deployManifestAgent
+}
+
+// deployManifestAgent is one agent in a manifest. Paths are relative to Dir,
+// which is itself relative to the manifest.
+type deployManifestAgent struct {
+	Name             string
+	Dir              string
+	Files            []string
+	Tools            []string
+	Model            string
+	SystemPrompt     string
+	EntryPoint       string
+	RequirementsFile string
+	Framework        string
+	Policies         []string
+	IdentityProvider string
+
+	line int
+}
+
+// deployTarget is one agent to deploy: its options and the directory its
+// source file paths are relative to.
+type deployTarget struct {
+	Options deployOptions
+	Dir     string
+}
+
+// findDeployManifest returns the path of the manifest in dir, or "" when
+// there is none.
+func findDeployManifest(dir string) (string, error) {
+	for _, name := range deployManifestNames {
+		path := filepath.Join(dir, name)
+		info, err := os.Stat(path)
+		if err == nil && !info.IsDir() {
+			return path, nil
+		}
+		if err != nil && !os.IsNotExist(err) {
+			return "", fmt.Errorf("failed to read %q: %w", path, err)
+		}
+	}
+	return "", nil
+}
+
+// loadDeployManifest reads and validates a manifest. Every problem found is
+// reported, each prefixed with the file and line it is on.
+func loadDeployManifest(path string) (*deployManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest %q: %w", path, err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", path, err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, fmt.Errorf("invalid manifest %s: file is empty", path)
+	}
+
+	var problems []string
+	report := func(line int, format string, args ...any) {
+		problems = append(problems, fmt.Sprintf("%s:%d: %s", path, line, fmt.Sprintf(format, args...)))
+	}
+
+	manifest := &deployManifest{Path: path}
+	root := doc.Content[0]
+	switch agents := mappingValue(root, "agents"); {
+	case root.Kind != yaml.MappingNode:
+		report(root.Line, "manifest must be a mapping of agent settings")
+	case agents != nil:
+		for i := 0; i < len(root.Content); i += 2 {
+			if key := root.Content[i]; key.Value != "agents" {
+				report(key.Line, "%q must be set on each agent when the manifest lists agents", key.Value)
+			}
+		}
+		if agents.Kind != yaml.SequenceNode || len(agents.Content) == 0 {
+			report(agents.Line, "agents must be a non-empty list")
+			break
+		}
+		for _, node := range agents.Content {
+			manifest.Agents = append(manifest.Agents, parseDeployManifestAgent(node, report))
+		}
+	default:
+		manifest.Agents = append(manifest.Agents, parseDeployManifestAgent(root, report))
+	}
+
+	seen := map[string]int{}
+	for _, agent := range manifest.Agents {
+		if agent.Name == "" {
+			continue
+		}
+		if line, ok := seen[agent.Name]; ok {
+			report(agent.line, "agent %q is already defined on line %d", agent.Name, line)
+			continue
+		}
+		seen[agent.Name] = agent.line
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid manifest:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return manifest, nil
+}
+
+func parseDeployManifestAgent(node *yaml.Node, report func(int, string, ...any)) deployManifestAgent {
+	agent := deployManifestAgent{line: node.Line}
+	if node.Kind != yaml.MappingNode {
+		report(node.Line, "each agent must be a mapping of settings")
+		return agent
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		switch key.Value {
+		case "name":
+			agent.Name = manifestString(key, value, report)
+		case "dir":
+			agent.Dir = manifestString(key, value, report)
+		case "system_prompt":
+			agent.SystemPrompt = manifestString(key, value, report)
+		case "entry_point":
+			agent.EntryPoint = manifestString(key, value, report)
+		case "requirements_file":
+			agent.RequirementsFile = manifestString(key, value, report)
+		case "framework":
+			agent.Framework = manifestString(key, value, report)
+		case "identity_provider":
+			agent.IdentityProvider = manifestString(key, value, report)
+		case "files":
+			agent.Files = manifestStrings(key, value, report)
+		case "tools":
+			agent.Tools = manifestStrings(key, value, report)
+		case "policies":
+			agent.Policies = manifestStrings(key, value, report)
+		case "model":
+			agent.Model = manifestModel(key, value, report)
+		default:
+			report(key.Line, "unknown field %q", key.Value)
+		}
+	}
+
+	switch {
+	case agent.Name == "":
+		report(node.Line, "agent name is required")
+	case !deployAgentNamePattern.MatchString(agent.Name) || len(agent.Name) > 63:
+		report(mappingKeyLine(node, "name"), "agent name %q must be at most 63 lowercase letters, digits, or '-', starting and ending with a letter or digit", agent.Name)
+	}
+	if agent.EntryPoint == "" && len(agent.Files) == 0 {
+		report(node.Line, "agent %q needs entry_point or files", agent.Name)
+	}
+	if agent.Dir != "" && (filepath.IsAbs(agent.Dir) || isPathOutsideBase(filepath.Clean(agent.Dir))) {
+		report(mappingKeyLine(node, "dir"), "dir %q must be inside the project", agent.Dir)
+	}
+	return agent
+}
+
+func manifestString(key, value *yaml.Node, report func(int, string, ...any)) string {
+	if value.Kind != yaml.ScalarNode || value.Tag == "!!null" {
+		report(value.Line, "%s must be a string", key.Value)
+		return ""
+	}
+	return strings.TrimSpace(value.Value)
+}
+
+func manifestStrings(key, value *yaml.Node, report func(int, string, ...any)) []string {
+	if value.Kind != yaml.SequenceNode {
+		report(value.Line, "%s must be a list", key.Value)
+		return nil
+	}
+	var values []string
+	for _, item := range value.Content {
+		if item.Kind != yaml.ScalarNode || strings.TrimSpace(item.Value) == "" {
+			report(item.Line, "%s entries must be non-empty strings", key.Value)
+			continue
+		}
+		values = append(values, strings.TrimSpace(item.Value))
+	}
+	return values
+}
+
+// manifestModel accepts either "provider/model" or a mapping with provider
+// and model, and returns the provider/model form.
+func manifestModel(key, value *yaml.Node, report func(int, string, ...any)) string {
+	model := ""
+	switch value.Kind {
+	case yaml.ScalarNode:
+		model = strings.TrimSpace(value.Value)
+	case yaml.MappingNode:
+		provider, name := "", ""
+		for i := 0; i+1 < len(value.Content); i += 2 {
+			field, fieldValue := value.Content[i], value.Content[i+1]
+			switch field.Value {
+			case "provider":
+				provider = manifestString(field, fieldValue, report)
+			case "model":
+				name = manifestString(field, fieldValue, report)
+			default:
+				report(field.Line, "unknown model field %q", field.Value)
+			}
+		}
+		if provider == "" || name == "" {
+			report(value.Line, "model needs both provider and model")
+			return ""
+		}
+		model = provider + "/" + name
+	default:
+		report(value.Line, "%s must be provider/model or a mapping with provider and model", key.Value)
+		return ""
+	}
+	if _, err := parseDeployModelFlag(model); err != nil {
+		report(value.Line, "model %q must be in provider/model format", model)
+		return ""
+	}
+	return model
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func mappingKeyLine(node *yaml.Node, key string) int {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i].Line
+		}
+	}
+	return node.Line
+}
+
+// target turns a manifest agent into deploy options. Without files, only the
+// entry point is deployed, along with requirements.txt when there is one;
+// other modules next to it, such as local mock servers, are left out unless
+// they are listed.
+func (a deployManifestAgent) target(manifestDir string) (deployTarget, error) {
+	dir, err := filepath.Abs(filepath.Join(manifestDir, a.Dir))
+	if err != nil {
+		return deployTarget{}, fmt.Errorf("failed to resolve directory of agent %q: %w", a.Name, err)
+	}
+
+	files := make([]string, 0, len(a.Files)+1)
+	for _, file := range a.Files {
+		files = append(files, filepath.Join(dir, file))
+	}
+	if len(files) == 0 {
+		files = append(files, filepath.Join(dir, a.EntryPoint))
+	}
+
+	requirements := ""
+	if a.RequirementsFile != "" {
+		requirements = filepath.Join(dir, a.RequirementsFile)
+	} else if _, err := os.Stat(filepath.Join(dir, "requirements.txt")); err == nil {
+		requirements = filepath.Join(dir, "requirements.txt")
+	}
+
+	return deployTarget{
+		Dir: dir,
+		Options: deployOptions{
+			Name:             a.Name,
+			Files:            files,
+			Tools:            a.Tools,
+			ModelFlag:        a.Model,
+			SystemPrompt:     a.SystemPrompt,
+			Policies:         a.Policies,
+			IdentityProvider: a.IdentityProvider,
+			RequirementsFile: requirements,
+			EntryPoint:       a.EntryPoint,
+			Framework:        a.Framework,
+		},
+	}, nil
+}
+
+// deployFlagsForOneAgent are the deploy flags that only make sense for a
+// single agent.
+var deployFlagsForOneAgent = []string{"name", "file", "dir", "entry-point", "requirements-file", "draft-id", "artifact-id"}
+
+// resolveDeployTargets works out which agents to deploy. Without a manifest
+// the flags describe the only agent. With one, each selected manifest agent
+// is deployed, with any flag that was set replacing the manifest's value.
+func resolveDeployTargets(manifest *deployManifest, selected []string, flags deployOptions, changed func(string) bool, cwd string) ([]deployTarget, error) {
+	if manifest == nil {
+		if len(selected) > 0 {
+			return nil, fmt.Errorf("--agent needs a runagents.yaml manifest")
+		}
+		return []deployTarget{{Options: flags, Dir: cwd}}, nil
+	}
+
+	agents := manifest.Agents
+	if len(selected) > 0 {
+		agents = nil
+		for _, name := range selected {
+			index := slices.IndexFunc(manifest.Agents, func(agent deployManifestAgent) bool { return agent.Name == name })
+			if index < 0 {
+				return nil, fmt.Errorf("agent %q is not defined in %s", name, manifest.Path)
+			}
+			agents = append(agents, manifest.Agents[index])
+		}
+	}
+	if len(agents) > 1 {
+		for _, flag := range deployFlagsForOneAgent {
+			if changed(flag) {
+				return nil, fmt.Errorf("--%s applies to a single agent; pick one from %s with --agent", flag, manifest.Path)
+			}
+		}
+	}
+
+	targets := make([]deployTarget, 0, len(agents))
+	for _, agent := range agents {
+		target, err := agent.target(filepath.Dir(manifest.Path))
+		if err != nil {
+			return nil, err
+		}
+		applyDeployFlagOverrides(&target, flags, changed, cwd)
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+// applyDeployFlagOverrides replaces manifest values with the flags that were
+// set. Flag paths are relative to the current directory, and deploying from a
+// draft or artifact drops the manifest's source settings.
+func applyDeployFlagOverrides(target *deployTarget, flags deployOptions, changed func(string) bool, cwd string) {
+	opts := &target.Options
+	if changed("name") {
+		opts.Name = flags.Name
+	}
+	if changed("tool") {
+		opts.Tools = flags.Tools
+	}
+	if changed("model") {
+		opts.ModelFlag = flags.ModelFlag
+	}
+	if changed("system-prompt") {
+		opts.SystemPrompt = flags.SystemPrompt
+	}
+	if changed("policy") {
+		opts.Policies = flags.Policies
+	}
+	if changed("identity-provider") {
+		opts.IdentityProvider = flags.IdentityProvider
+	}
+	if changed("framework") {
+		opts.Framework = flags.Framework
+	}
+	if changed("file") {
+		opts.Files = flags.Files
+		target.Dir = cwd
+	}
+	if changed("dir") {
+		opts.SourceDir, opts.RequirementsFile = flags.SourceDir, ""
+		if !changed("file") {
+			opts.Files = nil
+		}
+	}
+	if changed("requirements-file") {
+		opts.RequirementsFile = flags.RequirementsFile
+	}
+	if changed("entry-point") {
+		opts.EntryPoint = flags.EntryPoint
+	}
+	if changed("draft-id") || changed("artifact-id") {
+		opts.DraftID, opts.ArtifactID = flags.DraftID, flags.ArtifactID
+		opts.Files, opts.SourceDir, opts.RequirementsFile, opts.EntryPoint, opts.Framework = nil, "", "", "", ""
+	}
+}
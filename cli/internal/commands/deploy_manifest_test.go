@@ -0,0 +1,196 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func writeProjectFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+}
+
+func noFlagsChanged(string) bool { return false }
+
+func TestDeployManifestSingleAgentBuildsPayload(t *testing.T) {
+	dir := t.TempDir()
+	writeProjectFiles(t, dir, map[string]string{
+		"runagents.yaml": `name: support-agent
+
+tools:
+  - faq-service       # Open
+  - account-service   # Restricted
+
+model:
+  provider: openai
+  model: gpt-4o-mini
+
+system_prompt: >
+  You are a customer support coordinator.
+
+entry_point: agent.py
+`,
+		"agent.py":         "print('support')\n",
+		"mock_server.py":   "# local test server, not deployed\n",
+		"requirements.txt": "langchain\n",
+		"notes.md":         "not deployed\n",
+	})
+
+	path, err := findDeployManifest(dir)
+	if err != nil || path != filepath.Join(dir, "runagents.yaml") {
+		t.Fatalf("findDeployManifest = %q, %v", path, err)
+	}
+	manifest, err := loadDeployManifest(path)
+	if err != nil {
+		t.Fatalf("loadDeployManifest: %v", err)
+	}
+	targets, err := resolveDeployTargets(manifest, nil, deployOptions{}, noFlagsChanged, dir)
+	if err != nil {
+		t.Fatalf("resolveDeployTargets: %v", err)
+	}
+	if len(targets) != 1 {
+		t.Fatalf("expected one target, got %d", len(targets))
+	}
+
+	payload, err := buildDeployPayload(targets[0].Options, targets[0].Dir)
+	if err != nil {
+		t.Fatalf("buildDeployPayload: %v", err)
+	}
+	if payload["agent_name"] != "support-agent" || payload["entry_point"] != "agent.py" {
+		t.Fatalf("unexpected payload: %#v", payload)
+	}
+	if payload["system_prompt"] != "You are a customer support coordinator." {
+		t.Fatalf("unexpected system prompt: %#v", payload["system_prompt"])
+	}
+	if payload["requirements"] != "langchain\n" {
+		t.Fatalf("expected requirements.txt to be picked up, got %#v", payload["requirements"])
+	}
+	sources := payload["source_files"].(map[string]string)
+	if len(sources) != 1 || sources["agent.py"] == "" {
+		t.Fatalf("unexpected source files: %#v", sources)
+	}
+	tools := payload["required_tools"].([]string)
+	if !slices.Equal(tools, []string{"faq-service", "account-service"}) {
+		t.Fatalf("unexpected tools: %#v", tools)
+	}
+	models := payload["llm_configs"].([]map[string]string)
+	if models[0]["provider"] != "openai" || models[0]["model"] != "gpt-4o-mini" {
+		t.Fatalf("unexpected model: %#v", models)
+	}
+}
+
+func TestDeployManifestMultiAgentSelectionAndFlagOverrides(t *testing.T) {
+	dir := t.TempDir()
+	writeProjectFiles(t, dir, map[string]string{
+		"runagents.yaml": `agents:
+  - name: coordinator
+    dir: coordinator
+    entry_point: agent.py
+    model: openai/gpt-4o-mini
+  - name: account-agent
+    dir: account
+    files: [main.py, helpers/db.py]
+    entry_point: main.py
+    tools: [account-service]
+    model: openai/gpt-4o-mini
+    policies: [account-read]
+`,
+		"coordinator/agent.py":   "print('hi')\n",
+		"account/main.py":        "print('hi')\n",
+		"account/helpers/db.py":  "DB = None\n",
+		"account/unlisted.py":    "print('skip')\n",
+		"coordinator/helpers.py": "X = 1\n",
+	})
+	manifest, err := loadDeployManifest(filepath.Join(dir, "runagents.yaml"))
+	if err != nil {
+		t.Fatalf("loadDeployManifest: %v", err)
+	}
+
+	all, err := resolveDeployTargets(manifest, nil, deployOptions{}, noFlagsChanged, dir)
+	if err != nil {
+		t.Fatalf("resolveDeployTargets: %v", err)
+	}
+	if len(all) != 2 || all[0].Options.Name != "coordinator" || all[1].Options.Name != "account-agent" {
+		t.Fatalf("unexpected targets: %#v", all)
+	}
+
+	flags := deployOptions{Name: "renamed", ModelFlag: "anthropic/claude-haiku"}
+	changed := func(flag string) bool { return flag == "model" || flag == "name" }
+	if _, err := resolveDeployTargets(manifest, nil, flags, changed, dir); err == nil || !strings.Contains(err.Error(), "--name applies to a single agent") {
+		t.Fatalf("expected --name to be rejected for several agents, got %v", err)
+	}
+
+	selected, err := resolveDeployTargets(manifest, []string{"account-agent"}, flags, changed, dir)
+	if err != nil {
+		t.Fatalf("resolveDeployTargets with --agent: %v", err)
+	}
+	payload, err := buildDeployPayload(selected[0].Options, selected[0].Dir)
+	if err != nil {
+		t.Fatalf("buildDeployPayload: %v", err)
+	}
+	if payload["agent_name"] != "renamed" {
+		t.Fatalf("expected --name to override, got %#v", payload["agent_name"])
+	}
+	if models := payload["llm_configs"].([]map[string]string); models[0]["provider"] != "anthropic" {
+		t.Fatalf("expected --model to override, got %#v", models)
+	}
+	sources := payload["source_files"].(map[string]string)
+	if len(sources) != 2 || sources["main.py"] == "" || sources["helpers/db.py"] == "" {
+		t.Fatalf("unexpected source files: %#v", sources)
+	}
+	if policies := payload["policies"].([]string); !slices.Equal(policies, []string{"account-read"}) {
+		t.Fatalf("expected manifest policies to be kept, got %#v", policies)
+	}
+
+	if _, err := resolveDeployTargets(manifest, []string{"billing"}, deployOptions{}, noFlagsChanged, dir); err == nil {
+		t.Fatalf("expected an unknown --agent to fail")
+	}
+}
+
+func TestDeployManifestValidationReportsLineNumbers(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "runagents.yaml")
+	writeProjectFiles(t, dir, map[string]string{"runagents.yaml": `model: openai/gpt-4o-mini
+agents:
+  - name: Support_Agent
+    entry_point: agent.py
+    tools: [faq, ""]
+  - name: billing
+    model:
+      provider: openai
+    promt: typo
+  - name: billing
+    entry_point: agent.py
+    dir: ../elsewhere
+`})
+
+	_, err := loadDeployManifest(path)
+	if err == nil {
+		t.Fatalf("expected validation errors")
+	}
+	for _, want := range []string{
+		path + `:1: "model" must be set on each agent when the manifest lists agents`,
+		path + `:3: agent name "Support_Agent" must be at most 63 lowercase letters`,
+		path + `:5: tools entries must be non-empty strings`,
+		path + `:8: model needs both provider and model`,
+		path + `:9: unknown field "promt"`,
+		path + `:6: agent "billing" needs entry_point or files`,
+		path + `:12: dir "../elsewhere" must be inside the project`,
+		path + `:10: agent "billing" is already defined on line 6`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in:\n%s", want, err)
+		}
+	}
+}
@@ -0,0 +1,387 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/runagents/runagents/cli/internal/client"
+)
+
+// Policy decisions a deploy preview reports for each required tool.
+const (
+	previewDecisionAllow    = "allow"
+	previewDecisionApproval = "approval_required"
+	previewDecisionDeny     = "deny"
+	previewDecisionNone     = "none"
+)
+
+// cliDeployPreviewResponse is the runtime decision returned by /deploy/preview.
+type cliDeployPreviewResponse struct {
+	UserState       string `json:"user_state"`
+	StateLabel      string `json:"state_label"`
+	EtaRange        string `json:"eta_range"`
+	Confidence      string `json:"confidence"`
+	ShortReason     string `json:"short_reason"`
+	AdvancedDetails struct {
+		ExecutionMode  string `json:"execution_mode"`
+		BuildRequired  bool   `json:"build_required"`
+		BuildProfile   string `json:"build_profile,omitempty"`
+		DecisionReason string `json:"decision_reason,omitempty"`
+		ExecutionKind  string `json:"execution_kind,omitempty"`
+		SupportLevel   string `json:"support_level,omitempty"`
+	} `json:"advanced_details"`
+}
+
+// deployPreview is what a deploy would create or change, combining the
+// runtime preview with the governance state of the workspace.
+type deployPreview struct {
+	Agent            string                   `json:"agent"`
+	AgentExists      bool                     `json:"agent_exists"`
+	Runtime          cliDeployPreviewResponse `json:"runtime"`
+	ToolsToRegister  []string                 `json:"tools_to_register"`
+	Tools            []deployPreviewTool      `json:"tools"`
+	Policies         []deployPreviewPolicy    `json:"policies"`
+	IdentityProvider *deployPreviewIdentity   `json:"identity_provider,omitempty"`
+	ModelBudgets     []deployPreviewBudget    `json:"model_budgets"`
+	Warnings         []string                 `json:"warnings"`
+}
+
+type deployPreviewTool struct {
+	Name       string `json:"name"`
+	Registered bool   `json:"registered"`
+	AccessMode string `json:"access_mode,omitempty"`
+	BaseURL    string `json:"base_url,omitempty"`
+	Decision   string `json:"decision"`
+	Policy     string `json:"policy,omitempty"`
+}
+
+type deployPreviewPolicy struct {
+	Name          string `json:"name"`
+	Exists        bool   `json:"exists"`
+	Rules         int    `json:"rules"`
+	ApprovalRules int    `json:"approval_rules"`
+}
+
+type deployPreviewIdentity struct {
+	Name     string `json:"name"`
+	Exists   bool   `json:"exists"`
+	Previous string `json:"previous,omitempty"`
+}
+
+type deployPreviewBudget struct {
+	Provider         string   `json:"provider"`
+	Model            string   `json:"model"`
+	MonthlyBudgetUSD *float64 `json:"monthly_budget_usd"`
+}
+
+// deployPreviewClient is the subset of the API client deploy --preview needs.
+// Apart from the preview request itself, it only reads.
+type deployPreviewClient interface {
+	Get(string) ([]byte, error)
+	Post(string, interface{}) ([]byte, error)
+}
+
+// previewDeploy asks the API how the deploy would run and looks up the tools,
+// policies, identity provider, and budgets it touches.
+func previewDeploy(c deployPreviewClient, payload map[string]any) (*deployPreview, error) {
+	data, err := c.Post("/deploy/preview", payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to preview deploy: %w", err)
+	}
+	var runtime cliDeployPreviewResponse
+	if err := json.Unmarshal(data, &runtime); err != nil {
+		return nil, fmt.Errorf("failed to parse deploy preview: %w", err)
+	}
+	preview, err := assessDeployGovernance(c, payload)
+	if err != nil {
+		return nil, err
+	}
+	preview.Runtime = runtime
+	if strings.EqualFold(runtime.Confidence, "low") {
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("the runtime prediction has low confidence: %s", emptyFallback(runtime.ShortReason, "no reason given")))
+	}
+	return preview, nil
+}
+
+// assessDeployGovernance reads the workspace state the deploy payload refers
+// to and works out the governance impact of deploying it.
+func assessDeployGovernance(c interface{ Get(string) ([]byte, error) }, payload map[string]any) (*deployPreview, error) {
+	agent, _ := payload["agent_name"].(string)
+	preview := &deployPreview{
+		Agent:           agent,
+		ToolsToRegister: []string{},
+		Tools:           []deployPreviewTool{},
+		Policies:        []deployPreviewPolicy{},
+		ModelBudgets:    []deployPreviewBudget{},
+		Warnings:        []string{},
+	}
+	warn := func(format string, args ...any) {
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf(format, args...))
+	}
+
+	var current map[string]any
+	found, err := getPreviewObject(c, fmt.Sprintf("/agents/%s/config", agent), &current)
+	if err != nil {
+		return nil, err
+	}
+	preview.AgentExists = found
+
+	var policies []cliPolicyResponse
+	for _, name := range payloadStrings(payload["policies"]) {
+		var policy cliPolicyResponse
+		found, err := getPreviewObject(c, fmt.Sprintf("/policies/%s", name), &policy)
+		if err != nil {
+			return nil, err
+		}
+		entry := deployPreviewPolicy{Name: name, Exists: found}
+		if found {
+			if policy.Name == "" {
+				policy.Name = name
+			}
+			policies = append(policies, policy)
+			entry.Rules = len(policy.Spec.Policies)
+			entry.ApprovalRules = len(policy.Spec.Approvals)
+		} else {
+			warn("policy %q does not exist, so it cannot be bound", name)
+		}
+		preview.Policies = append(preview.Policies, entry)
+	}
+
+	for _, name := range payloadStrings(payload["required_tools"]) {
+		var tool map[string]any
+		found, err := getPreviewObject(c, fmt.Sprintf("/tools/%s", name), &tool)
+		if err != nil {
+			return nil, err
+		}
+		entry := deployPreviewTool{Name: name, Registered: found, Decision: previewDecisionNone}
+		if !found {
+			preview.ToolsToRegister = append(preview.ToolsToRegister, name)
+			warn("tool %q is not registered and will be registered by the deploy; review its base URL, auth, and access mode afterwards", name)
+			preview.Tools = append(preview.Tools, entry)
+			continue
+		}
+		entry.AccessMode = stringField(tool, "access_mode")
+		entry.BaseURL = stringField(tool, "base_url")
+		entry.Decision, entry.Policy = previewToolDecision(name, entry.BaseURL, policies)
+		restricted := strings.EqualFold(entry.AccessMode, "Restricted") || strings.EqualFold(entry.AccessMode, "Critical")
+		switch {
+		case entry.Decision == previewDecisionDeny:
+			warn("tool %q is denied by policy %q", name, entry.Policy)
+		case entry.Decision == previewDecisionNone && restricted:
+			warn("tool %q is %s and no bound policy covers it, so its calls will be denied", name, entry.AccessMode)
+		}
+		preview.Tools = append(preview.Tools, entry)
+	}
+
+	if name, _ := payload["identity_provider"].(string); name != "" {
+		found, err := getPreviewObject(c, fmt.Sprintf("/identity-providers/%s", name), nil)
+		if err != nil {
+			return nil, err
+		}
+		preview.IdentityProvider = &deployPreviewIdentity{Name: name, Exists: found, Previous: stringField(current, "identity_provider")}
+		if !found {
+			warn("identity provider %q does not exist", name)
+		}
+		if previous := preview.IdentityProvider.Previous; previous != "" && previous != name {
+			warn("the agent's identity provider changes from %q to %q; end users signed in through %q will be rejected", previous, name, previous)
+		}
+	}
+
+	if configs, ok := payload["llm_configs"].([]map[string]string); ok {
+		currentConfigs, _ := current["llm_configs"].([]any)
+		for _, config := range configs {
+			budget := deployPreviewBudget{Provider: config["provider"], Model: config["model"]}
+			for _, row := range currentConfigs {
+				item, _ := row.(map[string]any)
+				provider := firstNonEmpty(stringField(item, "model_provider"), stringField(item, "provider"))
+				if provider == budget.Provider && stringField(item, "model") == budget.Model {
+					if value, ok := item["monthly_budget_usd"].(float64); ok {
+						budget.MonthlyBudgetUSD = &value
+					}
+				}
+			}
+			if budget.MonthlyBudgetUSD == nil {
+				warn("model %s/%s has no monthly budget, so its spend is uncapped", budget.Provider, budget.Model)
+			}
+			preview.ModelBudgets = append(preview.ModelBudgets, budget)
+		}
+	}
+	return preview, nil
+}
+
+// getPreviewObject fetches path into out (when out is not nil) and reports
+// whether it exists.
+func getPreviewObject(c interface{ Get(string) ([]byte, error) }, path string, out any) (bool, error) {
+	data, err := c.Get(path)
+	if err != nil {
+		if client.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			return false, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	}
+	return true, nil
+}
+
+func payloadStrings(value any) []string {
+	values, _ := value.([]string)
+	return values
+}
+
+// previewToolDecision returns the strictest decision the bound policies make
+// for a tool, and the policy that makes it. Approval rules match on tool ID;
+// policy rules match when their resource overlaps the tool's base URL.
+func previewToolDecision(tool, baseURL string, policies []cliPolicyResponse) (string, string) {
+	rank := map[string]int{previewDecisionNone: 0, previewDecisionAllow: 1, previewDecisionApproval: 2, previewDecisionDeny: 3}
+	decision, source := previewDecisionNone, ""
+	consider := func(candidate, policy string) {
+		if rank[candidate] > rank[decision] {
+			decision, source = candidate, policy
+		}
+	}
+	for _, policy := range policies {
+		for _, rule := range policy.Spec.Approvals {
+			if slices.Contains(rule.ToolIDs, tool) {
+				consider(previewDecisionApproval, policy.Name)
+			}
+		}
+		for _, rule := range policy.Spec.Policies {
+			if !policyResourceOverlaps(rule.Resource, baseURL) {
+				continue
+			}
+			switch permission := strings.ToLower(rule.Permission); permission {
+			case previewDecisionAllow, previewDecisionApproval, previewDecisionDeny:
+				consider(permission, policy.Name)
+			}
+		}
+	}
+	return decision, source
+}
+
+// policyResourceOverlaps reports whether a policy resource pattern such as
+// https://api.example.com/* covers any part of a tool's base URL.
+func policyResourceOverlaps(resource, baseURL string) bool {
+	resource = strings.TrimRight(strings.TrimSpace(resource), "*/")
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	if resource == "" {
+		return true
+	}
+	if baseURL == "" {
+		return false
+	}
+	return strings.HasPrefix(resource, baseURL) || strings.HasPrefix(baseURL, resource)
+}
+
+func printDeployPreview(w io.Writer, preview *deployPreview) {
+	action := "create"
+	if preview.AgentExists {
+		action = "update"
+	}
+	fmt.Fprintf(w, "Deploy preview for agent %q (%s)\n\n", preview.Agent, action)
+
+	runtime := preview.Runtime
+	fmt.Fprintf(w, "%-19s%s", "Runtime:", emptyFallback(runtime.StateLabel, runtime.UserState))
+	if runtime.EtaRange != "" || runtime.Confidence != "" {
+		fmt.Fprintf(w, " (ETA %s, %s confidence)", emptyFallback(runtime.EtaRange, "unknown"), emptyFallback(runtime.Confidence, "unknown"))
+	}
+	fmt.Fprintln(w)
+	if runtime.ShortReason != "" {
+		fmt.Fprintf(w, "%-19s%s\n", "", runtime.ShortReason)
+	}
+	build := "not required"
+	if runtime.AdvancedDetails.BuildRequired {
+		build = "required"
+		if runtime.AdvancedDetails.BuildProfile != "" {
+			build += " (" + runtime.AdvancedDetails.BuildProfile + ")"
+		}
+	}
+	fmt.Fprintf(w, "%-19s%s\n", "Build:", build)
+	if runtime.AdvancedDetails.ExecutionMode != "" {
+		fmt.Fprintf(w, "%-19s%s\n", "Execution mode:", runtime.AdvancedDetails.ExecutionMode)
+	}
+
+	identity := "none"
+	if idp := preview.IdentityProvider; idp != nil {
+		identity = idp.Name
+		if idp.Previous != "" && idp.Previous != idp.Name {
+			identity += fmt.Sprintf(" (was %s)", idp.Previous)
+		}
+		if !idp.Exists {
+			identity += " (missing)"
+		}
+	}
+	fmt.Fprintf(w, "%-19s%s\n", "Identity provider:", identity)
+
+	if len(preview.ToolsToRegister) > 0 {
+		fmt.Fprintln(w, "\nTools to auto-register:")
+		for _, name := range preview.ToolsToRegister {
+			fmt.Fprintf(w, "  - %s\n", name)
+		}
+	}
+
+	var approvals []string
+	for _, tool := range preview.Tools {
+		if tool.Decision == previewDecisionApproval {
+			approvals = append(approvals, fmt.Sprintf("%s (policy %s)", tool.Name, tool.Policy))
+		}
+	}
+	if len(approvals) > 0 {
+		fmt.Fprintln(w, "\nRestricted tools that will need approval:")
+		for _, line := range approvals {
+			fmt.Fprintf(w, "  - %s\n", line)
+		}
+	}
+
+	if len(preview.Tools) > 0 {
+		fmt.Fprintln(w, "\nTools:")
+		for _, tool := range preview.Tools {
+			access := emptyFallback(tool.AccessMode, "-")
+			if !tool.Registered {
+				access = "new"
+			}
+			decision := tool.Decision
+			if tool.Policy != "" {
+				decision += " (" + tool.Policy + ")"
+			}
+			fmt.Fprintf(w, "  %-24s %-11s %s\n", tool.Name, access, decision)
+		}
+	}
+
+	if len(preview.Policies) > 0 {
+		fmt.Fprintln(w, "\nPolicy bindings:")
+		for _, policy := range preview.Policies {
+			if !policy.Exists {
+				fmt.Fprintf(w, "  - %s (missing)\n", policy.Name)
+				continue
+			}
+			fmt.Fprintf(w, "  - %s (%d rules, %d approval rules)\n", policy.Name, policy.Rules, policy.ApprovalRules)
+		}
+	}
+
+	if len(preview.ModelBudgets) > 0 {
+		fmt.Fprintln(w, "\nModel budgets:")
+		for _, budget := range preview.ModelBudgets {
+			amount := "uncapped"
+			if budget.MonthlyBudgetUSD != nil {
+				amount = formatUSD(*budget.MonthlyBudgetUSD) + " / month"
+			}
+			fmt.Fprintf(w, "  %-32s %s\n", budget.Provider+"/"+budget.Model, amount)
+		}
+	}
+
+	if len(preview.Warnings) == 0 {
+		fmt.Fprintln(w, "\nNo warnings.")
+		return
+	}
+	fmt.Fprintf(w, "\nWarnings (%d):\n", len(preview.Warnings))
+	for _, warning := range preview.Warnings {
+		fmt.Fprintf(w, "  ! %s\n", warning)
+	}
+}
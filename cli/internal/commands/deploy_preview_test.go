@@ -0,0 +1,123 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestAssessDeployGovernanceReportsImpactAndWarnings(t *testing.T) {
+	c := &fakeWorkspaceClient{objects: map[string]any{
+		"/agents/billing-agent/config": map[string]any{
+			"agent_name":        "billing-agent",
+			"identity_provider": "okta-oidc",
+			"llm_configs": []any{
+				map[string]any{"provider": "openai", "model": "gpt-4o-mini", "monthly_budget_usd": 50.0},
+			},
+		},
+		"/tools/stripe-api": map[string]any{"name": "stripe-api", "base_url": "https://api.stripe.com", "access_mode": "Restricted"},
+		"/tools/erp":        map[string]any{"name": "erp", "base_url": "https://erp.example.com", "access_mode": "Restricted"},
+		"/tools/echo-tool":  map[string]any{"name": "echo-tool", "base_url": "https://echo.example.com", "access_mode": "Open"},
+		"/policies/billing-write-approval": cliPolicyResponse{
+			Name: "billing-write-approval",
+			Spec: cliPolicySpec{
+				Policies: []cliPolicyRule{
+					{Permission: "allow", Resource: "https://echo.example.com/*"},
+					{Permission: "allow", Resource: "https://api.stripe.com/v1/*", Operations: []string{"GET"}},
+				},
+				Approvals: []cliApprovalRule{{Name: "refunds", ToolIDs: []string{"stripe-api"}}},
+			},
+		},
+		"/identity-providers/google-oidc": map[string]any{"name": "google-oidc"},
+	}}
+
+	preview, err := assessDeployGovernance(c, map[string]any{
+		"agent_name":        "billing-agent",
+		"required_tools":    []string{"stripe-api", "erp", "echo-tool", "ledger"},
+		"policies":          []string{"billing-write-approval", "missing-policy"},
+		"identity_provider": "google-oidc",
+		"llm_configs": []map[string]string{
+			{"provider": "openai", "model": "gpt-4o-mini"},
+			{"provider": "anthropic", "model": "claude-haiku"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("assessDeployGovernance: %v", err)
+	}
+
+	if !preview.AgentExists {
+		t.Fatalf("expected the agent to exist")
+	}
+	if len(preview.ToolsToRegister) != 1 || preview.ToolsToRegister[0] != "ledger" {
+		t.Fatalf("unexpected tools to register: %#v", preview.ToolsToRegister)
+	}
+	decisions := map[string]string{}
+	for _, tool := range preview.Tools {
+		decisions[tool.Name] = tool.Decision
+	}
+	want := map[string]string{"stripe-api": previewDecisionApproval, "erp": previewDecisionNone, "echo-tool": previewDecisionAllow, "ledger": previewDecisionNone}
+	for name, decision := range want {
+		if decisions[name] != decision {
+			t.Fatalf("tool %s: expected %s, got %s", name, decision, decisions[name])
+		}
+	}
+	if preview.IdentityProvider == nil || preview.IdentityProvider.Previous != "okta-oidc" || !preview.IdentityProvider.Exists {
+		t.Fatalf("unexpected identity provider: %#v", preview.IdentityProvider)
+	}
+	if len(preview.ModelBudgets) != 2 || preview.ModelBudgets[0].MonthlyBudgetUSD == nil || *preview.ModelBudgets[0].MonthlyBudgetUSD != 50 || preview.ModelBudgets[1].MonthlyBudgetUSD != nil {
+		t.Fatalf("unexpected model budgets: %#v", preview.ModelBudgets)
+	}
+
+	warnings := strings.Join(preview.Warnings, "\n")
+	for _, fragment := range []string{
+		`policy "missing-policy" does not exist`,
+		`tool "ledger" is not registered`,
+		`tool "erp" is Restricted and no bound policy covers it`,
+		`changes from "okta-oidc" to "google-oidc"`,
+		`model anthropic/claude-haiku has no monthly budget`,
+	} {
+		if !strings.Contains(warnings, fragment) {
+			t.Fatalf("expected warning %q in:\n%s", fragment, warnings)
+		}
+	}
+	if len(preview.Warnings) != 5 {
+		t.Fatalf("expected 5 warnings, got:\n%s", warnings)
+	}
+	if len(c.calls) != 0 {
+		t.Fatalf("governance checks must not mutate the workspace: %v", c.calls)
+	}
+
+	var out bytes.Buffer
+	printDeployPreview(&out, preview)
+	for _, fragment := range []string{
+		`Deploy preview for agent "billing-agent" (update)`,
+		"Tools to auto-register:\n  - ledger\n",
+		"Restricted tools that will need approval:\n  - stripe-api (policy billing-write-approval)\n",
+		"Identity provider: google-oidc (was okta-oidc)",
+		"$50.00 / month",
+		"Warnings (5):",
+	} {
+		if !strings.Contains(out.String(), fragment) {
+			t.Fatalf("expected %q in preview:\n%s", fragment, out.String())
+		}
+	}
+}
+
+func TestPolicyResourceOverlaps(t *testing.T) {
+	cases := []struct {
+		resource, baseURL string
+		want              bool
+	}{
+		{"", "https://api.stripe.com", true},
+		{"*", "https://api.stripe.com", true},
+		{"https://api.stripe.com/*", "https://api.stripe.com", true},
+		{"https://api.stripe.com/v1/refunds", "https://api.stripe.com/", true},
+		{"https://api.example.com/*", "https://api.example.com/v2", true},
+		{"https://erp.example.com/*", "https://api.stripe.com", false},
+	}
+	for _, tc := range cases {
+		if got := policyResourceOverlaps(tc.resource, tc.baseURL); got != tc.want {
+			t.Fatalf("policyResourceOverlaps(%q, %q) = %v, want %v", tc.resource, tc.baseURL, got, tc.want)
+		}
+	}
+}
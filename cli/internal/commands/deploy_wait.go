@@ -0,0 +1,205 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/runagents/runagents/cli/internal/client"
+)
+
+// deployedAgentState is the runtime phase of a deployed agent and the image
+// its spec points at.
+type deployedAgentState struct {
+	Name    string `json:"name"`
+	Phase   string `json:"phase"`
+	Image   string `json:"image,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// deployWaitTarget ties deploy --wait to one deploy: the build it started,
+// the image it rolls out, and the agent as it was before the deploy, so the
+// previous revision running is not mistaken for the new one. Reused marks a
+// deploy that sent the image of an unchanged bundle instead of uploading it.
+type deployWaitTarget struct {
+	Agent       string
+	BuildID     string
+	Image       string
+	FirstDeploy bool
+	Reused      bool
+	Previous    deployedAgentState
+}
+
+// deployWaitResult is what deploy --wait saw once the deploy settled.
+type deployWaitResult struct {
+	Build *cliBuild          `json:"build,omitempty"`
+	Agent deployedAgentState `json:"agent"`
+}
+
+// fetchDeployedAgentState reads an agent's phase and image. The API reports
+// status either as a plain string or as an object with phase and message.
+func fetchDeployedAgentState(c interface{ Get(string) ([]byte, error) }, name string) (deployedAgentState, error) {
+	data, err := c.Get(fmt.Sprintf("/agents/%s", name))
+	if err != nil {
+		return deployedAgentState{}, err
+	}
+	var agent struct {
+		Spec struct {
+			Image string `json:"image"`
+		} `json:"spec"`
+		Status  json.RawMessage `json:"status"`
+		Message string          `json:"message"`
+	}
+	if err := json.Unmarshal(data, &agent); err != nil {
+		return deployedAgentState{}, fmt.Errorf("failed to parse agent response: %w", err)
+	}
+	state := deployedAgentState{Name: name, Image: agent.Spec.Image, Message: agent.Message}
+	if len(agent.Status) == 0 || json.Unmarshal(agent.Status, &state.Phase) == nil {
+		return state, nil
+	}
+	var status struct {
+		Phase   string `json:"phase"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(agent.Status, &status); err != nil {
+		return deployedAgentState{}, fmt.Errorf("failed to parse agent status: %w", err)
+	}
+	state.Phase = status.Phase
+	state.Message = firstNonEmptyRunValue(status.Message, state.Message)
+	return state, nil
+}
+
+// agentStateBeforeDeploy records the agent as it is before a deploy. An agent
+// that does not exist yet makes the deploy a first deploy.
+func agentStateBeforeDeploy(c interface{ Get(string) ([]byte, error) }, name string) deployWaitTarget {
+	target := deployWaitTarget{Agent: strings.TrimSpace(name)}
+	state, err := fetchDeployedAgentState(c, target.Agent)
+	switch {
+	case err == nil:
+		target.Previous = state
+	case client.IsNotFound(err):
+		target.FirstDeploy = true
+	}
+	return target
+}
+
+// rolledOut reports whether state belongs to this deploy rather than to the
+// revision that was running before it: the agent is new, its spec now has
+// the deployed image, or its phase has moved since the deploy. A reused
+// bundle image that is already in the spec has nothing left to roll out.
+func (t deployWaitTarget) rolledOut(state deployedAgentState, sawRollout bool) bool {
+	if t.Image != "" && state.Image != "" && state.Image != t.Image {
+		return false
+	}
+	switch {
+	case t.FirstDeploy, sawRollout:
+		return true
+	case t.Reused && t.Image != "" && state.Image == t.Image:
+		return true
+	case t.Image != "" && state.Image == t.Image && t.Image != t.Previous.Image:
+		return true
+	default:
+		return t.Previous.Phase != "" && !strings.EqualFold(state.Phase, t.Previous.Phase)
+	}
+}
+
+func isReadyAgentPhase(phase string) bool {
+	switch strings.ToUpper(strings.TrimSpace(phase)) {
+	case "RUNNING", "READY":
+		return true
+	default:
+		return false
+	}
+}
+
+func isFailedAgentPhase(phase string) bool {
+	switch strings.ToUpper(strings.TrimSpace(phase)) {
+	case "FAILED", "ERROR":
+		return true
+	default:
+		return false
+	}
+}
+
+// waitForDeploy follows the build behind a deploy, when there is one, and
+// then the agent until the deployed revision is running. Progress goes to
+// out. A failed build or agent returns an error that exits with
+// exitCodeDeployFailed; running out of time exits with exitCodeWaitTimeout.
+func waitForDeploy(ctx context.Context, c *client.Client, target deployWaitTarget, interval, timeout time.Duration, out, errOut io.Writer) (*deployWaitResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	timedOut := func(what string) error {
+		return &exitCodeError{code: exitCodeWaitTimeout, err: fmt.Errorf("timed out after %s waiting for %s", timeout, what)}
+	}
+
+	agent := target.Agent
+	result := &deployWaitResult{Agent: deployedAgentState{Name: agent}}
+	if buildID := target.BuildID; buildID != "" {
+		progress := newBuildProgress(out)
+		build, err := followBuild(ctx, interval, buildID, func(ctx context.Context, id string) (*cliBuild, error) {
+			return fetchBuild(c.WithContext(ctx), id)
+		}, progress.update)
+		result.Build = build
+		if err != nil {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return result, timedOut(fmt.Sprintf("build %s", buildID))
+			}
+			return result, err
+		}
+		if err := buildOutcome(*build); err != nil {
+			printBuildFailureLogs(errOut, *build)
+			return result, err
+		}
+		target.Image = firstNonEmptyRunValue(build.Image, target.Image)
+	}
+
+	last := ""
+	sawRollout := false
+	err := waitForCondition(ctx, interval, func(ctx context.Context) (bool, error) {
+		state, err := fetchDeployedAgentState(c.WithContext(ctx), agent)
+		if client.IsNotFound(err) {
+			// A new agent can take a moment to show up after the deploy.
+			state, err = deployedAgentState{Name: agent}, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		result.Agent = state
+		if line := fmt.Sprintf("Agent %s: %s", agent, emptyFallback(state.Phase, "Pending")); line != last {
+			last = line
+			fmt.Fprintln(out, line)
+		}
+		settled := isReadyAgentPhase(state.Phase) || isFailedAgentPhase(state.Phase)
+		if !settled {
+			sawRollout = true
+			return false, nil
+		}
+		return target.rolledOut(state, sawRollout), nil
+	})
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return result, timedOut(fmt.Sprintf("agent %q to roll out the deploy (last phase %s)", agent, emptyFallback(result.Agent.Phase, "unknown")))
+		}
+		return result, err
+	}
+	if isFailedAgentPhase(result.Agent.Phase) {
+		return result, &exitCodeError{code: exitCodeDeployFailed, err: fmt.Errorf("agent %q failed to start: %s", agent, emptyFallback(result.Agent.Message, "no message reported"))}
+	}
+	return result, nil
+}
+
+// printBuildFailureLogs writes the log output of a failed build. The build
+// error itself is reported by the returned error.
+func printBuildFailureLogs(w io.Writer, build cliBuild) {
+	if len(build.Logs) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\nBuild %s logs:\n", build.ID)
+	for _, line := range build.Logs {
+		fmt.Fprintf(w, "  %s\n", line)
+	}
+}
@@ -0,0 +1,433 @@
+package commands
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const unifiedDiffContext = 3
+
+// workspaceServerFields are managed by the API and never declared locally.
+var workspaceServerFields = map[string][]string{
+	"": {"status", "used_by", "created_at", "updated_at", "namespace"},
+
+	workspaceKindApprovalConnector: {"id"},
+	workspaceKindAgent:             {"agent_name", "image", "model_usage"},
+}
+
+type workspaceDiffChange struct {
+	Path   string `json:"path"`
+	Change string `json:"change"`
+	Live   any    `json:"live,omitempty"`
+	Local  any    `json:"local,omitempty"`
+}
+
+type workspaceDiffResult struct {
+	Kind    string                `json:"kind"`
+	Name    string                `json:"name"`
+	Source  string                `json:"source,omitempty"`
+	Status  string                `json:"status"`
+	Changes []workspaceDiffChange `json:"changes,omitempty"`
+	Diff    string                `json:"-"`
+	Error   string                `json:"error,omitempty"`
+}
+
+type workspaceDiffResponse struct {
+	Drift        bool                  `json:"drift"`
+	InSyncCount  int                   `json:"in_sync_count"`
+	ChangedCount int                   `json:"changed_count"`
+	MissingCount int                   `json:"missing_count"`
+	FailedCount  int                   `json:"failed_count"`
+	Results      []workspaceDiffResult `json:"results"`
+}
+
+type sequencePair struct {
+	A int
+	B int
+}
+
+func newDiffCmd() *cobra.Command {
+	var paths []string
+	cmd := &cobra.Command{
+		Use:   "diff -f <file|dir>",
+		Short: "Show drift between local manifests and the live workspace",
+		Long: `Compare local resource manifests with the live workspace.
+
+Accepts the same files and directories as 'runagents apply'. Server-managed
+fields such as status, used_by and timestamps are ignored. Use -o json for a
+structured, path-level diff.
+
+Exit codes:
+  0  no drift
+  1  the comparison could not be completed
+  2  drift detected`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(paths) == 0 {
+				return fmt.Errorf("--file is required")
+			}
+			docs, err := loadWorkspaceDocuments(paths)
+			if err != nil {
+				return err
+			}
+			resources, err := buildWorkspaceResources(docs)
+			if err != nil {
+				return err
+			}
+			c, err := newAPIClient(cmd.Context())
+			if err != nil {
+				return err
+			}
+			resp := diffWorkspaceResources(c, resources)
+			if isJSONOutput() {
+				if err := printJSONValue(resp); err != nil {
+					return err
+				}
+			} else {
+				printWorkspaceDiffResponse(resp)
+			}
+			if resp.FailedCount > 0 {
+				return fmt.Errorf("%d of %d resources could not be compared", resp.FailedCount, len(resp.Results))
+			}
+			if resp.Drift {
+				return &exitCodeError{
+					code: exitCodeDrift,
+					err:  fmt.Errorf("drift detected in %d of %d resources", resp.ChangedCount+resp.MissingCount, len(resp.Results)),
+				}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringArrayVarP(&paths, "file", "f", nil, "Resource file or directory (repeatable)")
+	return cmd
+}
+
+func diffWorkspaceResources(c workspaceAPIClient, resources []workspaceResource) workspaceDiffResponse {
+	resp := workspaceDiffResponse{Results: make([]workspaceDiffResult, 0, len(resources))}
+	for _, resource := range resources {
+		result := diffWorkspaceResource(c, resource)
+		switch result.Status {
+		case "in_sync":
+			resp.InSyncCount++
+		case "changed":
+			resp.ChangedCount++
+		case "missing":
+			resp.MissingCount++
+		default:
+			resp.FailedCount++
+		}
+		resp.Results = append(resp.Results, result)
+	}
+	resp.Drift = resp.ChangedCount+resp.MissingCount > 0
+	return resp
+}
+
+func diffWorkspaceResource(c workspaceAPIClient, resource workspaceResource) workspaceDiffResult {
+	result := workspaceDiffResult{Kind: resource.Kind, Name: resource.Name, Source: resource.Source}
+	live, found, err := fetchLiveWorkspaceResource(c, resource)
+	if err != nil {
+		result.Status = "failed"
+		result.Error = err.Error()
+		return result
+	}
+	local, remote, err := workspaceDiffDocuments(resource, live, found)
+	if err != nil {
+		result.Status = "failed"
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Changes = compareDocuments("", remote, local)
+	switch {
+	case !found:
+		result.Status = "missing"
+	case len(result.Changes) == 0:
+		result.Status = "in_sync"
+		return result
+	default:
+		result.Status = "changed"
+	}
+
+	liveLabel := "/dev/null"
+	if found {
+		liveLabel = "live/" + resource.Kind + "/" + resource.Name
+	}
+	diff, err := unifiedDocumentDiff(liveLabel, "local/"+resource.Kind+"/"+resource.Name, remote, local)
+	if err != nil {
+		result.Status = "failed"
+		result.Error = err.Error()
+		return result
+	}
+	result.Diff = diff
+	return result
+}
+
+// workspaceDiffDocuments returns the local and live documents for a resource
+// with server-managed fields removed, and the live document projected onto
+// the local one as apply does, so they can be compared directly.
+func workspaceDiffDocuments(resource workspaceResource, live map[string]any, found bool) (any, any, error) {
+	local, err := normalizedWorkspacePayload(resource)
+	if err != nil {
+		return nil, nil, err
+	}
+	stripWorkspaceServerFields(resource.Kind, local)
+	if !found {
+		return local, nil, nil
+	}
+
+	var remote any
+	if err := decodeStructuredValue(live, &remote); err != nil {
+		return nil, nil, fmt.Errorf("failed to normalize live %s %q: %w", resource.Kind, resource.Name, err)
+	}
+	stripWorkspaceServerFields(resource.Kind, remote)
+	return local, projectLiveWorkspaceDocument(local, remote), nil
+}
+
+func stripWorkspaceServerFields(kind string, doc any) {
+	asMap, ok := doc.(map[string]any)
+	if !ok {
+		return
+	}
+	for _, field := range workspaceServerFields[""] {
+		delete(asMap, field)
+	}
+	for _, field := range workspaceServerFields[kind] {
+		delete(asMap, field)
+	}
+}
+
+// compareDocuments lists the path-level differences between two generic
+// JSON documents. Paths use dots for object keys and [n] for list items.
+func compareDocuments(path string, live, local any) []workspaceDiffChange {
+	if reflect.DeepEqual(live, local) {
+		return nil
+	}
+	if live == nil {
+		return []workspaceDiffChange{{Path: documentPathOrRoot(path), Change: "added", Local: local}}
+	}
+	if local == nil {
+		return []workspaceDiffChange{{Path: documentPathOrRoot(path), Change: "removed", Live: live}}
+	}
+
+	liveMap, liveIsMap := live.(map[string]any)
+	localMap, localIsMap := local.(map[string]any)
+	if liveIsMap && localIsMap {
+		keys := make(map[string]struct{}, len(liveMap)+len(localMap))
+		for key := range liveMap {
+			keys[key] = struct{}{}
+		}
+		for key := range localMap {
+			keys[key] = struct{}{}
+		}
+		sorted := make([]string, 0, len(keys))
+		for key := range keys {
+			sorted = append(sorted, key)
+		}
+		sort.Strings(sorted)
+
+		var changes []workspaceDiffChange
+		for _, key := range sorted {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			changes = append(changes, compareDocuments(childPath, liveMap[key], localMap[key])...)
+		}
+		return changes
+	}
+
+	liveItems, liveIsList := live.([]any)
+	localItems, localIsList := local.([]any)
+	if liveIsList && localIsList {
+		var changes []workspaceDiffChange
+		for i := 0; i < len(liveItems) || i < len(localItems); i++ {
+			var liveItem, localItem any
+			if i < len(liveItems) {
+				liveItem = liveItems[i]
+			}
+			if i < len(localItems) {
+				localItem = localItems[i]
+			}
+			changes = append(changes, compareDocuments(fmt.Sprintf("%s[%d]", path, i), liveItem, localItem)...)
+		}
+		return changes
+	}
+
+	return []workspaceDiffChange{{Path: documentPathOrRoot(path), Change: "changed", Live: live, Local: local}}
+}
+
+func documentPathOrRoot(path string) string {
+	if path == "" {
+		return "."
+	}
+	return path
+}
+
+// unifiedDocumentDiff renders both documents as YAML and returns a unified
+// diff between them, or an empty string when they render identically.
+func unifiedDocumentDiff(fromLabel, toLabel string, from, to any) (string, error) {
+	fromLines, err := documentLines(from)
+	if err != nil {
+		return "", err
+	}
+	toLines, err := documentLines(to)
+	if err != nil {
+		return "", err
+	}
+	hunks := unifiedDiffHunks(fromLines, toLines, unifiedDiffContext)
+	if len(hunks) == 0 {
+		return "", nil
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n", fromLabel)
+	fmt.Fprintf(&b, "+++ %s\n", toLabel)
+	for _, line := range hunks {
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	return b.String(), nil
+}
+
+func documentLines(doc any) ([]string, error) {
+	if doc == nil {
+		return nil, nil
+	}
+	data, err := encodeStructuredYAML(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render yaml: %w", err)
+	}
+	return strings.Split(strings.TrimRight(string(data), "\n"), "\n"), nil
+}
+
+// unifiedDiffHunks returns the @@ hunks of a line diff between a and b.
+func unifiedDiffHunks(a, b []string, context int) []string {
+	pairs := alignSequences(len(a), len(b), func(i, j int) bool { return a[i] == b[j] })
+
+	var changed []int
+	for idx, pair := range pairs {
+		if pair.A < 0 || pair.B < 0 {
+			changed = append(changed, idx)
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	var out []string
+	for start := 0; start < len(changed); {
+		end := start
+		for end+1 < len(changed) && changed[end+1]-changed[end] <= 2*context {
+			end++
+		}
+		from := changed[start] - context
+		if from < 0 {
+			from = 0
+		}
+		to := changed[end] + context + 1
+		if to > len(pairs) {
+			to = len(pairs)
+		}
+
+		oldStart, newStart := 1, 1
+		for _, pair := range pairs[:from] {
+			if pair.A >= 0 {
+				oldStart++
+			}
+			if pair.B >= 0 {
+				newStart++
+			}
+		}
+		var oldCount, newCount int
+		var lines []string
+		for _, pair := range pairs[from:to] {
+			switch {
+			case pair.A >= 0 && pair.B >= 0:
+				oldCount++
+				newCount++
+				lines = append(lines, " "+a[pair.A])
+			case pair.A >= 0:
+				oldCount++
+				lines = append(lines, "-"+a[pair.A])
+			default:
+				newCount++
+				lines = append(lines, "+"+b[pair.B])
+			}
+		}
+		if oldCount == 0 {
+			oldStart--
+		}
+		if newCount == 0 {
+			newStart--
+		}
+		out = append(out, fmt.Sprintf("@@ -%d,%d +%d,%d @@", oldStart, oldCount, newStart, newCount))
+		out = append(out, lines...)
+		start = end + 1
+	}
+	return out
+}
+
+// alignSequences aligns two sequences by their longest common subsequence.
+// Each pair holds an index into the first (A) and second (B) sequence, with
+// -1 marking an item present on only one side. Removals precede additions.
+func alignSequences(n, m int, equal func(i, j int) bool) []sequencePair {
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if equal(i, j) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	pairs := make([]sequencePair, 0, n+m)
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case equal(i, j):
+			pairs = append(pairs, sequencePair{A: i, B: j})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			pairs = append(pairs, sequencePair{A: i, B: -1})
+			i++
+		default:
+			pairs = append(pairs, sequencePair{A: -1, B: j})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		pairs = append(pairs, sequencePair{A: i, B: -1})
+	}
+	for ; j < m; j++ {
+		pairs = append(pairs, sequencePair{A: -1, B: j})
+	}
+	return pairs
+}
+
+func printWorkspaceDiffResponse(resp workspaceDiffResponse) {
+	for _, result := range resp.Results {
+		switch result.Status {
+		case "changed", "missing":
+			fmt.Print(result.Diff)
+			fmt.Println()
+		case "failed":
+			fmt.Printf("%s/%s: %s\n\n", result.Kind, result.Name, result.Error)
+		}
+	}
+	if !resp.Drift && resp.FailedCount == 0 {
+		fmt.Printf("No drift detected across %d resources.\n", len(resp.Results))
+		return
+	}
+	fmt.Printf("In sync: %d, changed: %d, missing: %d, failed: %d\n", resp.InSyncCount, resp.ChangedCount, resp.MissingCount, resp.FailedCount)
+}
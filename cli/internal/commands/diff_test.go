@@ -0,0 +1,138 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAlignSequencesPairsCommonItems(t *testing.T) {
+	a := []string{"a", "b", "c", "d"}
+	b := []string{"a", "c", "x", "d"}
+	pairs := alignSequences(len(a), len(b), func(i, j int) bool { return a[i] == b[j] })
+
+	var got []string
+	for _, pair := range pairs {
+		switch {
+		case pair.A >= 0 && pair.B >= 0:
+			got = append(got, "="+a[pair.A])
+		case pair.A >= 0:
+			got = append(got, "-"+a[pair.A])
+		default:
+			got = append(got, "+"+b[pair.B])
+		}
+	}
+	if strings.Join(got, " ") != "=a -b =c +x =d" {
+		t.Fatalf("unexpected alignment %q", strings.Join(got, " "))
+	}
+}
+
+func TestUnifiedDiffHunksIncludesContextAndRanges(t *testing.T) {
+	a := []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10"}
+	b := []string{"1", "2", "3", "4", "five", "6", "7", "8", "9", "10"}
+	hunks := unifiedDiffHunks(a, b, 2)
+	want := []string{"@@ -3,5 +3,5 @@", " 3", " 4", "-5", "+five", " 6", " 7"}
+	if strings.Join(hunks, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected hunks:\n%s", strings.Join(hunks, "\n"))
+	}
+	if hunks := unifiedDiffHunks(a, a, 2); len(hunks) != 0 {
+		t.Fatalf("expected no hunks for identical input, got %v", hunks)
+	}
+}
+
+func TestCompareDocumentsReportsPathLevelChanges(t *testing.T) {
+	live := map[string]any{
+		"name": "billing",
+		"spec": map[string]any{
+			"policies": []any{"a", "b"},
+			"host":     "old.example.com",
+		},
+	}
+	local := map[string]any{
+		"name": "billing",
+		"spec": map[string]any{
+			"policies": []any{"a"},
+			"host":     "new.example.com",
+			"claim":    "email",
+		},
+	}
+	changes := compareDocuments("", live, local)
+	var got []string
+	for _, change := range changes {
+		got = append(got, change.Change+" "+change.Path)
+	}
+	want := "added spec.claim,changed spec.host,removed spec.policies[1]"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("expected %q, got %q", want, strings.Join(got, ","))
+	}
+}
+
+func TestDiffWorkspaceResourcesIgnoresServerFields(t *testing.T) {
+	client := &fakeWorkspaceClient{objects: map[string]any{
+		"/policies/billing-read": map[string]any{
+			"name":      "billing-read",
+			"namespace": "default",
+			"status":    map[string]any{"ready": true},
+			"used_by":   []any{map[string]any{"name": "billing-agent"}},
+			"spec": map[string]any{
+				"policies": []any{map[string]any{"permission": "allow", "resource": "https://billing.example.com/*"}},
+			},
+		},
+		"/agents/billing-agent/config": map[string]any{
+			"agent_name":     "billing-agent",
+			"image":          "registry/billing:1",
+			"system_prompt":  "Old prompt.",
+			"required_tools": []any{"billing"},
+		},
+	}}
+	policy := cliPolicyApplyRequest{Name: "billing-read"}
+	policy.Spec.Policies = []cliPolicyRule{{Permission: "allow", Resource: "https://billing.example.com/*"}}
+	resources := []workspaceResource{
+		{Kind: workspaceKindPolicy, Name: "billing-read", Payload: policy},
+		{Kind: workspaceKindAgent, Name: "billing-agent", Payload: map[string]any{"system_prompt": "New prompt."}},
+		{Kind: workspaceKindTool, Name: "crm", Payload: map[string]any{"name": "crm", "spec": map[string]any{}}},
+	}
+
+	resp := diffWorkspaceResources(client, resources)
+	if !resp.Drift || resp.InSyncCount != 1 || resp.ChangedCount != 1 || resp.MissingCount != 1 {
+		t.Fatalf("unexpected counts: %#v", resp)
+	}
+	agent := resp.Results[1]
+	if len(agent.Changes) != 1 || agent.Changes[0].Path != "system_prompt" {
+		t.Fatalf("expected only the system prompt to drift, got %#v", agent.Changes)
+	}
+	if !strings.Contains(agent.Diff, "-system_prompt: Old prompt.") || !strings.Contains(agent.Diff, "+system_prompt: New prompt.") {
+		t.Fatalf("unexpected agent diff:\n%s", agent.Diff)
+	}
+	if !strings.HasPrefix(resp.Results[2].Diff, "--- /dev/null\n+++ local/Tool/crm\n") {
+		t.Fatalf("unexpected missing tool diff:\n%s", resp.Results[2].Diff)
+	}
+}
+
+func TestDiffAgreesWithApplyOnServerDefaultedFields(t *testing.T) {
+	client := &fakeWorkspaceClient{objects: map[string]any{
+		"/tools/billing": map[string]any{
+			"name":   "billing",
+			"status": "Ready",
+			"spec": map[string]any{
+				"connection": map[string]any{"baseUrl": "https://billing.example.com", "port": 443},
+			},
+		},
+	}}
+	resource := workspaceResource{
+		Kind: workspaceKindTool,
+		Name: "billing",
+		Payload: map[string]any{
+			"name": "billing",
+			"spec": map[string]any{
+				"connection": map[string]any{"baseUrl": "https://billing.example.com"},
+			},
+		},
+	}
+
+	if result := applyWorkspaceResource(client, resource); result.Status != "unchanged" {
+		t.Fatalf("expected apply to report unchanged, got %#v", result)
+	}
+	if resp := diffWorkspaceResources(client, []workspaceResource{resource}); resp.Drift || resp.InSyncCount != 1 {
+		t.Fatalf("expected diff to agree with apply, got %#v", resp)
+	}
+}
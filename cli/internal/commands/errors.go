@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/runagents/runagents/cli/internal/client"
+)
+
+// Exit codes returned by Execute. Any other failure exits with 1.
+const (
+	// exitCodeDrift is returned when local manifests differ from the workspace,
+	// or when compared runs diverge.
+	exitCodeDrift        = 2
+	exitCodeUnauthorized = 3
+	exitCodeForbidden    = 4
+	exitCodeNotFound     = 5
+	exitCodeConflict     = 6
+	exitCodeUnavailable  = 7
+	// exitCodeVerificationFailed is returned when run integrity checks fail.
+	exitCodeVerificationFailed = 8
+	// The runs wait outcomes: a run failed, is paused for approval, consent,
+	// or some other reason, or the wait timed out.
+	exitCodeRunFailed           = 9
+	exitCodeRunAwaitingApproval = 10
+	exitCodeRunAwaitingConsent  = 11
+	exitCodeRunPaused           = 12
+	exitCodeWaitTimeout         = 13
+	// exitCodeDeployFailed is returned when a build fails or a deployed agent
+	// fails to start.
+	exitCodeDeployFailed = 14
+	exitCodeInterrupted  = 130
+)
+
+// exitCodeError makes Execute exit with a specific code instead of 1.
+type exitCodeError struct {
+	code int
+	err  error
+}
+
+func (e *exitCodeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitCodeError) Unwrap() error {
+	return e.err
+}
+
+// describeError returns the message and exit code Execute reports for err.
+// API errors get an actionable hint and an exit code per error class.
+func describeError(err error) (string, int) {
+	var coded *exitCodeError
+	if errors.As(err, &coded) {
+		return err.Error(), coded.code
+	}
+
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) {
+		return err.Error(), 1
+	}
+
+	message, code := describeAPIError(apiErr)
+	if context := apiErrorContext(err, apiErr); context != "" {
+		message = context + ": " + message
+	}
+	if apiErr.RequestID != "" {
+		message += fmt.Sprintf(" (request ID: %s)", apiErr.RequestID)
+	}
+	return message, code
+}
+
+func describeAPIError(apiErr *client.APIError) (string, int) {
+	detail := apiErr.Message
+	if detail == "" {
+		detail = http.StatusText(apiErr.StatusCode)
+	}
+	switch status := apiErr.StatusCode; {
+	case status == http.StatusUnauthorized:
+		return "API key rejected (HTTP 401) — run 'runagents config set api-key <key>' or pass --api-key", exitCodeUnauthorized
+	case status == http.StatusForbidden:
+		return fmt.Sprintf("permission denied (HTTP 403): %s — check that the API key has access to this workspace", detail), exitCodeForbidden
+	case status == http.StatusNotFound:
+		return fmt.Sprintf("not found (HTTP 404): %s — check the name or ID, and that the endpoint points at the right workspace", detail), exitCodeNotFound
+	case status == http.StatusConflict:
+		return fmt.Sprintf("conflict (HTTP 409): %s — the resource already exists or changed concurrently; fetch it again and retry", detail), exitCodeConflict
+	case status == http.StatusTooManyRequests:
+		return fmt.Sprintf("rate limited (HTTP 429): %s — wait a moment and retry, or raise --retries", detail), exitCodeUnavailable
+	case status >= 500:
+		return fmt.Sprintf("RunAgents API unavailable (HTTP %d): %s — try again shortly", status, detail), exitCodeUnavailable
+	default:
+		return fmt.Sprintf("request rejected (HTTP %d): %s", status, detail), 1
+	}
+}
+
+// apiErrorContext returns whatever a command prefixed onto the API error
+// with fmt.Errorf("...: %w"), so wrapped context is not lost.
+func apiErrorContext(err error, apiErr *client.APIError) string {
+	full := err.Error()
+	if !strings.HasSuffix(full, apiErr.Error()) {
+		return ""
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(full, apiErr.Error()), ": ")
+}
@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/runagents/runagents/cli/internal/client"
+)
+
+func TestDescribeErrorMapsAPIErrorClasses(t *testing.T) {
+	cases := []struct {
+		status int
+		code   int
+		want   string
+	}{
+		{http.StatusUnauthorized, exitCodeUnauthorized, "runagents config set api-key"},
+		{http.StatusForbidden, exitCodeForbidden, "permission denied"},
+		{http.StatusNotFound, exitCodeNotFound, "not found (HTTP 404): agent missing"},
+		{http.StatusConflict, exitCodeConflict, "conflict (HTTP 409)"},
+		{http.StatusServiceUnavailable, exitCodeUnavailable, "unavailable (HTTP 503)"},
+		{http.StatusBadRequest, 1, "request rejected (HTTP 400): agent missing"},
+	}
+	for _, tc := range cases {
+		err := &client.APIError{StatusCode: tc.status, Message: "agent missing"}
+		message, code := describeError(err)
+		if code != tc.code {
+			t.Fatalf("HTTP %d: expected exit code %d, got %d", tc.status, tc.code, code)
+		}
+		if !strings.Contains(message, tc.want) {
+			t.Fatalf("HTTP %d: expected message containing %q, got %q", tc.status, tc.want, message)
+		}
+	}
+}
+
+func TestDescribeErrorKeepsContextAndRequestID(t *testing.T) {
+	apiErr := &client.APIError{StatusCode: http.StatusNotFound, Message: "no such run", RequestID: "req-42"}
+	message, code := describeError(fmt.Errorf("failed to fetch run events: %w", apiErr))
+	if code != exitCodeNotFound {
+		t.Fatalf("expected exit code %d, got %d", exitCodeNotFound, code)
+	}
+	if !strings.HasPrefix(message, "failed to fetch run events: not found (HTTP 404): no such run") {
+		t.Fatalf("expected wrapped context to be preserved, got %q", message)
+	}
+	if !strings.HasSuffix(message, "(request ID: req-42)") {
+		t.Fatalf("expected request ID, got %q", message)
+	}
+}
+
+func TestDescribeErrorHonorsExplicitExitCodes(t *testing.T) {
+	message, code := describeError(&exitCodeError{code: exitCodeDrift, err: errors.New("drift detected")})
+	if code != exitCodeDrift || message != "drift detected" {
+		t.Fatalf("expected drift exit code, got %d %q", code, message)
+	}
+	if _, code := describeError(errors.New("boom")); code != 1 {
+		t.Fatalf("expected generic exit code 1, got %d", code)
+	}
+}
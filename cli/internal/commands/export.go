@@ -0,0 +1,299 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// workspaceKindDirs maps each kind to its directory inside an export.
+var workspaceKindDirs = map[string]string{
+	workspaceKindModelProvider:     "model-providers",
+	workspaceKindTool:              "tools",
+	workspaceKindApprovalConnector: "approval-connectors",
+	workspaceKindIdentityProvider:  "identity-providers",
+	workspaceKindPolicy:            "policies",
+	workspaceKindAgent:             "agents",
+}
+
+var workspaceKindListPaths = map[string]string{
+	workspaceKindModelProvider:     "/model-providers",
+	workspaceKindTool:              "/tools",
+	workspaceKindApprovalConnector: "/approval-connectors",
+	workspaceKindIdentityProvider:  "/identity-providers",
+	workspaceKindPolicy:            "/policies",
+	workspaceKindAgent:             "/agents",
+}
+
+// agentConfigExportFields are the agent config fields accepted by
+// PUT /agents/{name}/config; everything else is derived by the platform.
+var agentConfigExportFields = []string{"system_prompt", "identity_provider", "llm_configs", "required_tools", "policies"}
+
+// secretFieldSuffixes match normalized field names whose string values are
+// treated as secrets and replaced with environment placeholders on export.
+var secretFieldSuffixes = []string{"secret", "password", "passwd", "apikey", "token", "authorization", "privatekey", "credential", "credentials"}
+
+var secretPlaceholderUnsafe = regexp.MustCompile(`[^A-Z0-9]+`)
+
+type workspaceManifest struct {
+	Kind   string         `yaml:"kind"`
+	Name   string         `yaml:"name"`
+	Fields map[string]any `yaml:",inline"`
+}
+
+type workspaceExportedResource struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+type workspaceExportResponse struct {
+	Dir       string                      `json:"dir"`
+	Resources []workspaceExportedResource `json:"resources"`
+	Secrets   []string                    `json:"secrets,omitempty"`
+}
+
+func newExportCmd() *cobra.Command {
+	var (
+		dir   string
+		force bool
+	)
+	cmd := &cobra.Command{
+		Use:   "export --dir <dir>",
+		Short: "Export workspace resources as reproducible YAML manifests",
+		Long: `Export policies, tools, model providers, identity providers, approval
+connectors and agent configs as one YAML file per resource.
+
+Server-managed fields are stripped and secret values are replaced with
+${RUNAGENTS_SECRET_...} placeholders, so the directory can be committed and
+later converged with 'runagents apply -f <dir>' once those variables are set.
+
+Examples:
+  runagents export --dir ./workspace
+  runagents export --dir ./workspace --force`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(dir) == "" {
+				return fmt.Errorf("--dir is required")
+			}
+			c, err := newAPIClient(cmd.Context())
+			if err != nil {
+				return err
+			}
+			manifests, err := collectWorkspaceManifests(c)
+			if err != nil {
+				return err
+			}
+			secrets := redactWorkspaceManifests(manifests)
+			resp, err := writeWorkspaceManifests(dir, manifests, force)
+			if err != nil {
+				return err
+			}
+			resp.Secrets = secrets
+			if isJSONOutput() {
+				return printJSONValue(resp)
+			}
+			printWorkspaceExportResponse(resp)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&dir, "dir", "", "Directory to write manifests into")
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite existing manifest files")
+	return cmd
+}
+
+// collectWorkspaceManifests fetches every exportable resource and converts
+// it into the document shape accepted by 'runagents apply'.
+func collectWorkspaceManifests(c interface {
+	Get(string) ([]byte, error)
+	listAPIClient
+}) ([]workspaceManifest, error) {
+	var manifests []workspaceManifest
+	for _, kind := range workspaceKindOrder {
+		path := workspaceKindListPaths[kind]
+		items, err := listWorkspaceItems(c, path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", workspaceKindDirs[kind], err)
+		}
+		for _, item := range items {
+			name := stringField(item, "name")
+			if name == "" {
+				continue
+			}
+			if kind == workspaceKindAgent {
+				data, err := c.Get(workspaceResourcePath(kind, name))
+				if err != nil {
+					return nil, fmt.Errorf("failed to get config for agent %q: %w", name, err)
+				}
+				if err := json.Unmarshal(data, &item); err != nil {
+					return nil, fmt.Errorf("failed to parse config for agent %q: %w", name, err)
+				}
+			}
+			manifests = append(manifests, workspaceManifestFromLive(kind, name, item))
+		}
+	}
+	return manifests, nil
+}
+
+// listWorkspaceItems reads every item of a workspace list endpoint,
+// following server pages.
+func listWorkspaceItems(c listAPIClient, path string) ([]map[string]any, error) {
+	raw, _, err := fetchListItems(c, path, nil, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]map[string]any, 0, len(raw))
+	for _, data := range raw {
+		var item map[string]any
+		if err := json.Unmarshal(data, &item); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func workspaceManifestFromLive(kind, name string, live map[string]any) workspaceManifest {
+	fields := make(map[string]any, len(live))
+	if kind == workspaceKindAgent {
+		spec := make(map[string]any, len(agentConfigExportFields))
+		for _, field := range agentConfigExportFields {
+			if value, ok := live[field]; ok {
+				spec[field] = value
+			}
+		}
+		fields["spec"] = spec
+	} else {
+		for key, value := range live {
+			fields[key] = value
+		}
+		stripWorkspaceServerFields(kind, fields)
+		delete(fields, "name")
+	}
+	pruned, _ := pruneEmptyValues(fields).(map[string]any)
+	return workspaceManifest{Kind: kind, Name: name, Fields: pruned}
+}
+
+// redactWorkspaceManifests replaces secret values in place and returns the
+// sorted environment variable names needed to apply the manifests again.
+func redactWorkspaceManifests(manifests []workspaceManifest) []string {
+	seen := map[string]struct{}{}
+	for i := range manifests {
+		prefix := "RUNAGENTS_SECRET_" + manifests[i].Kind + "_" + manifests[i].Name
+		for key, value := range manifests[i].Fields {
+			manifests[i].Fields[key] = redactSecretValues(prefix, key, value, seen)
+		}
+	}
+	secrets := make([]string, 0, len(seen))
+	for name := range seen {
+		secrets = append(secrets, name)
+	}
+	sort.Strings(secrets)
+	return secrets
+}
+
+func redactSecretValues(prefix, key string, value any, seen map[string]struct{}) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		for nestedKey, nested := range typed {
+			typed[nestedKey] = redactSecretValues(prefix, nestedKey, nested, seen)
+		}
+		return typed
+	case []any:
+		for i, item := range typed {
+			typed[i] = redactSecretValues(prefix, key, item, seen)
+		}
+		return typed
+	case string:
+		if typed == "" || !isSecretFieldName(key) || workspaceEnvReference.MatchString(typed) {
+			return typed
+		}
+		name := secretPlaceholderName(prefix, key)
+		seen[name] = struct{}{}
+		return "${" + name + "}"
+	default:
+		return value
+	}
+}
+
+func isSecretFieldName(key string) bool {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", "_", "", " ", "").Replace(key))
+	for _, suffix := range secretFieldSuffixes {
+		if strings.HasSuffix(normalized, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func secretPlaceholderName(prefix, key string) string {
+	name := strings.ToUpper(prefix + "_" + key)
+	return strings.Trim(secretPlaceholderUnsafe.ReplaceAllString(name, "_"), "_")
+}
+
+func writeWorkspaceManifests(dir string, manifests []workspaceManifest, force bool) (workspaceExportResponse, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return workspaceExportResponse{}, fmt.Errorf("resolve export directory: %w", err)
+	}
+	resp := workspaceExportResponse{Dir: absDir, Resources: make([]workspaceExportedResource, 0, len(manifests))}
+	for _, manifest := range manifests {
+		target := filepath.Join(absDir, workspaceKindDirs[manifest.Kind], workspaceManifestFilename(manifest.Name))
+		if !force {
+			if _, err := os.Stat(target); err == nil {
+				return workspaceExportResponse{}, fmt.Errorf("target file already exists: %s (use --force to overwrite)", target)
+			}
+		}
+		data, err := encodeStructuredYAML(manifest)
+		if err != nil {
+			return workspaceExportResponse{}, fmt.Errorf("render %s %q: %w", manifest.Kind, manifest.Name, err)
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return workspaceExportResponse{}, fmt.Errorf("create directory for %s: %w", target, err)
+		}
+		if err := os.WriteFile(target, data, 0o644); err != nil {
+			return workspaceExportResponse{}, fmt.Errorf("write %s: %w", target, err)
+		}
+		resp.Resources = append(resp.Resources, workspaceExportedResource{Kind: manifest.Kind, Name: manifest.Name, Path: target})
+	}
+	return resp, nil
+}
+
+func workspaceManifestFilename(name string) string {
+	safe := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r == ' ' {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	return safe + ".yaml"
+}
+
+func printWorkspaceExportResponse(resp workspaceExportResponse) {
+	if len(resp.Resources) == 0 {
+		fmt.Println("No resources found to export.")
+		return
+	}
+	table := newTable("KIND", "NAME", "FILE")
+	for _, resource := range resp.Resources {
+		rel, err := filepath.Rel(resp.Dir, resource.Path)
+		if err != nil {
+			rel = resource.Path
+		}
+		table.Append([]string{resource.Kind, resource.Name, rel})
+	}
+	table.Render()
+	fmt.Printf("\nExported %d resources to %s\n", len(resp.Resources), resp.Dir)
+	if len(resp.Secrets) > 0 {
+		fmt.Println("\nSecrets were redacted. Set these environment variables before running 'runagents apply':")
+		for _, name := range resp.Secrets {
+			fmt.Printf("  %s\n", name)
+		}
+	}
+}
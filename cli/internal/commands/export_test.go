@@ -0,0 +1,170 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWorkspaceExportRoundTripsThroughApply(t *testing.T) {
+	client := &fakeWorkspaceClient{objects: map[string]any{
+		"/model-providers": []any{map[string]any{
+			"name":      "openai",
+			"namespace": "default",
+			"status":    map[string]any{"phase": "Ready"},
+			"spec": map[string]any{
+				"provider": "openai",
+				"auth":     map[string]any{"apiKey": "sk-live"},
+			},
+		}},
+		"/tools": []any{map[string]any{
+			"name": "billing",
+			"spec": map[string]any{
+				"connection": map[string]any{
+					"baseUrl": "https://billing.example.com",
+					"authentication": map[string]any{
+						"type":         "OAuth2",
+						"oauth2Config": map[string]any{"tokenUrl": "https://billing.example.com/token"},
+					},
+				},
+			},
+		}},
+		"/approval-connectors": []any{map[string]any{
+			"id":         "conn-1",
+			"name":       "slack",
+			"endpoint":   "https://hooks.example.com",
+			"headers":    map[string]any{"Authorization": "Bearer abc"},
+			"enabled":    false,
+			"created_at": "2026-01-01T00:00:00Z",
+		}},
+		"/identity-providers": []any{},
+		"/policies": []any{map[string]any{
+			"name":    "billing-read",
+			"used_by": []any{map[string]any{"name": "billing-agent"}},
+			"spec": map[string]any{
+				"policies": []any{map[string]any{"permission": "allow", "resource": "https://billing.example.com/*"}},
+			},
+		}},
+		"/agents": []any{map[string]any{"name": "billing-agent"}},
+		"/agents/billing-agent/config": map[string]any{
+			"agent_name":     "billing-agent",
+			"image":          "registry/billing:1",
+			"system_prompt":  "Help with billing.",
+			"required_tools": []any{"billing"},
+			"model_usage":    []any{map[string]any{"label": "chat"}},
+		},
+	}}
+
+	manifests, err := collectWorkspaceManifests(client)
+	if err != nil {
+		t.Fatalf("collectWorkspaceManifests returned error: %v", err)
+	}
+	secrets := redactWorkspaceManifests(manifests)
+	want := "RUNAGENTS_SECRET_APPROVALCONNECTOR_SLACK_AUTHORIZATION,RUNAGENTS_SECRET_MODELPROVIDER_OPENAI_APIKEY"
+	if strings.Join(secrets, ",") != want {
+		t.Fatalf("expected secrets %q, got %q", want, strings.Join(secrets, ","))
+	}
+
+	dir := t.TempDir()
+	resp, err := writeWorkspaceManifests(dir, manifests, false)
+	if err != nil {
+		t.Fatalf("writeWorkspaceManifests returned error: %v", err)
+	}
+	if len(resp.Resources) != 5 {
+		t.Fatalf("expected 5 exported resources, got %d", len(resp.Resources))
+	}
+	if _, err := writeWorkspaceManifests(dir, manifests, false); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Fatalf("expected overwrite protection, got %v", err)
+	}
+
+	policy, err := os.ReadFile(filepath.Join(dir, "policies", "billing-read.yaml"))
+	if err != nil {
+		t.Fatalf("read policy manifest: %v", err)
+	}
+	if !strings.HasPrefix(string(policy), "kind: Policy\nname: billing-read\n") || strings.Contains(string(policy), "used_by") {
+		t.Fatalf("unexpected policy manifest:\n%s", policy)
+	}
+	tool, err := os.ReadFile(filepath.Join(dir, "tools", "billing.yaml"))
+	if err != nil {
+		t.Fatalf("read tool manifest: %v", err)
+	}
+	if !strings.Contains(string(tool), "tokenUrl: https://billing.example.com/token") {
+		t.Fatalf("expected token URL to be preserved:\n%s", tool)
+	}
+
+	if _, err := loadWorkspaceDocuments([]string{dir}); err == nil || !strings.Contains(err.Error(), "is not set") {
+		t.Fatalf("expected missing secret error, got %v", err)
+	}
+	for _, name := range secrets {
+		t.Setenv(name, "restored")
+	}
+	docs, err := loadWorkspaceDocuments([]string{dir})
+	if err != nil {
+		t.Fatalf("loadWorkspaceDocuments returned error: %v", err)
+	}
+	resources, err := buildWorkspaceResources(docs)
+	if err != nil {
+		t.Fatalf("buildWorkspaceResources returned error: %v", err)
+	}
+	if len(resources) != 5 {
+		t.Fatalf("expected 5 resources, got %d", len(resources))
+	}
+	connector := resources[2].Payload.(cliApprovalConnectorApplyRequest)
+	if connector.Name != "slack" || connector.ID != "" || connector.Headers["Authorization"] != "restored" {
+		t.Fatalf("unexpected connector payload: %#v", connector)
+	}
+	if connector.Enabled == nil || *connector.Enabled {
+		t.Fatalf("expected disabled connector to round-trip, got %#v", connector.Enabled)
+	}
+	agent := resources[4].Payload.(map[string]any)
+	if _, ok := agent["image"]; ok {
+		t.Fatalf("expected agent image to be stripped, got %#v", agent)
+	}
+}
+
+func TestIsSecretFieldName(t *testing.T) {
+	for _, key := range []string{"api_key", "X-Api-Key", "client_secret", "Authorization", "password", "access_token"} {
+		if !isSecretFieldName(key) {
+			t.Fatalf("expected %q to be treated as a secret", key)
+		}
+	}
+	for _, key := range []string{"tokenUrl", "secretRef", "name", "endpoint"} {
+		if isSecretFieldName(key) {
+			t.Fatalf("expected %q not to be treated as a secret", key)
+		}
+	}
+}
+
+func TestWorkspaceListsFollowServerPages(t *testing.T) {
+	client := &fakeWorkspaceClient{objects: map[string]any{
+		"/model-providers":    map[string]any{"items": []any{}},
+		"/tools":              map[string]any{"items": []any{map[string]any{"name": "billing"}}, "next_cursor": "p2"},
+		"/tools?cursor=p2":    map[string]any{"items": []any{map[string]any{"name": "crm"}}},
+		"/identity-providers": []any{},
+		"/policies":           []any{},
+		"/agents":             []any{},
+		"/approval-connectors": map[string]any{
+			"items":       []any{map[string]any{"id": "conn-1", "name": "slack"}},
+			"next_cursor": "p2",
+		},
+		"/approval-connectors?cursor=p2": map[string]any{"items": []any{map[string]any{"id": "conn-2", "name": "pagerduty"}}},
+	}}
+
+	manifests, err := collectWorkspaceManifests(client)
+	if err != nil {
+		t.Fatalf("collectWorkspaceManifests: %v", err)
+	}
+	var names []string
+	for _, manifest := range manifests {
+		names = append(names, manifest.Kind+"/"+manifest.Name)
+	}
+	if got := strings.Join(names, ","); !strings.Contains(got, "Tool/billing,Tool/crm") || !strings.Contains(got, "ApprovalConnector/pagerduty") {
+		t.Fatalf("expected every page to be exported, got %s", got)
+	}
+
+	connectors, err := fetchApprovalConnectors(client)
+	if err != nil || len(connectors) != 2 || connectors[1].ID != "conn-2" {
+		t.Fatalf("expected both connector pages, got %+v (%v)", connectors, err)
+	}
+}
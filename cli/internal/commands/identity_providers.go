@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/runagents/runagents/cli/internal/client"
 	"github.com/spf13/cobra"
 )
 
@@ -52,7 +53,7 @@ func newIdentityProvidersListCmd() *cobra.Command {
 		Short: "List identity providers in the current workspace",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
@@ -96,7 +97,7 @@ func newIdentityProvidersGetCmd() *cobra.Command {
 		Short: "Get a specific identity provider",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
@@ -136,7 +137,7 @@ func newIdentityProvidersApplyCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
@@ -144,7 +145,7 @@ func newIdentityProvidersApplyCmd() *cobra.Command {
 			action := "created"
 			if _, err := c.Get(fmt.Sprintf("/identity-providers/%s", req.Name)); err == nil {
 				action = "updated"
-			} else if extractHTTPStatus(err) != httpStatusNotFound {
+			} else if !client.IsNotFound(err) {
 				return err
 			}
 
@@ -166,7 +167,7 @@ func newIdentityProvidersDeleteCmd() *cobra.Command {
 		Short: "Delete an identity provider",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
@@ -180,15 +181,23 @@ func newIdentityProvidersDeleteCmd() *cobra.Command {
 }
 
 func loadIdentityProviderApplyRequest(path, nameOverride string) (cliIdentityProviderApplyRequest, error) {
-	var req cliIdentityProviderApplyRequest
-	if err := decodeStructuredFile(path, &req); err != nil {
+	var raw map[string]any
+	if err := decodeStructuredFile(path, &raw); err != nil {
 		return cliIdentityProviderApplyRequest{}, err
 	}
+	return identityProviderApplyRequestFromDocument(raw, nameOverride)
+}
+
+func identityProviderApplyRequestFromDocument(raw map[string]any, nameOverride string) (cliIdentityProviderApplyRequest, error) {
+	var req cliIdentityProviderApplyRequest
+	if err := decodeStructuredValue(raw, &req); err != nil {
+		return cliIdentityProviderApplyRequest{}, fmt.Errorf("decode identity provider request: %w", err)
+	}
 
 	if identityProviderSpecIsZero(req.Spec) {
 		var spec cliIdentityProviderSpec
-		if err := decodeStructuredFile(path, &spec); err != nil {
-			return cliIdentityProviderApplyRequest{}, err
+		if err := decodeStructuredValue(raw, &spec); err != nil {
+			return cliIdentityProviderApplyRequest{}, fmt.Errorf("decode identity provider spec: %w", err)
 		}
 		req.Spec = spec
 	}
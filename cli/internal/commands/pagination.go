@@ -0,0 +1,115 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+// listPageSize is the page size requested from paginated list endpoints.
+const listPageSize = 100
+
+type listAPIClient interface {
+	Paginate(string, url.Values, func([]json.RawMessage) (bool, error)) error
+}
+
+// listOptions holds the --limit and --all flags shared by list commands.
+type listOptions struct {
+	Limit int
+	All   bool
+}
+
+func addListFlags(cmd *cobra.Command, opts *listOptions, noun string, defaultLimit int) {
+	cmd.Flags().IntVar(&opts.Limit, "limit", defaultLimit, fmt.Sprintf("Maximum number of %s to return (0 for all)", noun))
+	cmd.Flags().BoolVar(&opts.All, "all", false, fmt.Sprintf("Return all %s, following every server page (overrides --limit)", noun))
+}
+
+// limitOrAll returns the number of items to collect, or 0 for no limit.
+func (o listOptions) limitOrAll() int {
+	if o.All || o.Limit < 0 {
+		return 0
+	}
+	return o.Limit
+}
+
+// pageSize returns the page size to request from the server.
+func (o listOptions) pageSize() string {
+	if limit := o.limitOrAll(); limit > 0 && limit < listPageSize {
+		return strconv.Itoa(limit)
+	}
+	return strconv.Itoa(listPageSize)
+}
+
+// arrayQuery returns the query for endpoints that answer with one plain JSON
+// array, which Paginate treats as a complete page: the exact limit, or no
+// limit at all so the server returns everything.
+func (o listOptions) arrayQuery() url.Values {
+	if limit := o.limitOrAll(); limit > 0 {
+		return url.Values{"limit": {strconv.Itoa(limit)}}
+	}
+	return nil
+}
+
+// fetchListItems pages through a list endpoint, keeping the items accepted by
+// keep (all items when keep is nil). Paging stops at the first page boundary
+// where at least limit items were kept, so callers that sort should trim the
+// result themselves; limit 0 fetches every page. The returned flag reports
+// whether paging stopped before the server ran out of pages.
+func fetchListItems(c listAPIClient, path string, query url.Values, limit int, keep func(json.RawMessage) (bool, error)) ([]json.RawMessage, bool, error) {
+	kept := []json.RawMessage{}
+	stopped := false
+	err := c.Paginate(path, query, func(items []json.RawMessage) (bool, error) {
+		for _, item := range items {
+			if keep != nil {
+				ok, err := keep(item)
+				if err != nil {
+					return false, err
+				}
+				if !ok {
+					continue
+				}
+			}
+			kept = append(kept, item)
+		}
+		if limit > 0 && len(kept) >= limit {
+			stopped = true
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return kept, stopped || (limit > 0 && len(kept) > limit), nil
+}
+
+// trimListItems keeps the first limit items (all when limit is 0).
+func trimListItems(items []json.RawMessage, limit int) []json.RawMessage {
+	if limit > 0 && len(items) > limit {
+		return items[:limit]
+	}
+	return items
+}
+
+// decodeListItems unmarshals each raw item into a map for table rendering.
+func decodeListItems(items []json.RawMessage) ([]map[string]interface{}, error) {
+	decoded := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		var value map[string]interface{}
+		if err := json.Unmarshal(item, &value); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+		decoded = append(decoded, value)
+	}
+	return decoded, nil
+}
+
+// printListTruncatedHint tells table users that more results are available.
+func printListTruncatedHint(truncated bool, shown int, noun string) {
+	if truncated {
+		fmt.Printf("\nShowing the first %d %s; use --limit or --all to see more.\n", shown, noun)
+	}
+}
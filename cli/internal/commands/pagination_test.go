@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/runagents/runagents/cli/internal/client"
+)
+
+func TestArrayListEndpointsAreNotCutAtOnePage(t *testing.T) {
+	var limits []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limits = append(limits, r.URL.Query().Get("limit"))
+		events := make([]map[string]string, 0, 150)
+		for i := 0; i < 150; i++ {
+			events = append(events, map[string]string{"event": fmt.Sprintf("e%d", i)})
+		}
+		_ = json.NewEncoder(w).Encode(events)
+	}))
+	defer server.Close()
+	c := client.NewClient(server.URL, "ra_ws_test")
+
+	all := listOptions{All: true}
+	items, truncated, err := fetchListItems(c, "/approval-connectors/activity", all.arrayQuery(), all.limitOrAll(), nil)
+	if err != nil {
+		t.Fatalf("fetchListItems: %v", err)
+	}
+	if len(items) != 150 || truncated || limits[0] != "" {
+		t.Fatalf("expected every event without a limit, got %d items (truncated %v, limit %q)", len(items), truncated, limits[0])
+	}
+
+	capped := listOptions{Limit: 120}
+	if query := capped.arrayQuery(); query.Get("limit") != "120" {
+		t.Fatalf("expected the exact limit to be sent, got %v", query)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/runagents/runagents/cli/internal/client"
 	"github.com/spf13/cobra"
 )
 
@@ -91,7 +92,7 @@ func newPoliciesListCmd() *cobra.Command {
 		Short: "List policies in the current workspace",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
@@ -133,7 +134,7 @@ func newPoliciesGetCmd() *cobra.Command {
 		Short: "Get a policy and its usage details",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
@@ -173,7 +174,7 @@ func newPoliciesApplyCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
@@ -188,7 +189,7 @@ func newPoliciesApplyCmd() *cobra.Command {
 					return putErr
 				}
 				return printAppliedPolicy(req.Name, method, data)
-			} else if extractHTTPStatus(err) != httpStatusNotFound {
+			} else if !client.IsNotFound(err) {
 				return err
 			}
 
@@ -210,7 +211,7 @@ func newPoliciesDeleteCmd() *cobra.Command {
 		Short: "Delete a policy",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
@@ -233,7 +234,7 @@ func newPoliciesTranslateCmd() *cobra.Command {
 			if strings.TrimSpace(text) == "" {
 				return fmt.Errorf("--from is required")
 			}
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
@@ -270,8 +271,6 @@ func newPoliciesTranslateCmd() *cobra.Command {
 	return cmd
 }
 
-const httpStatusNotFound = 404
-
 func boolWord(v bool) string {
 	if v {
 		return "yes"
@@ -342,7 +341,10 @@ func loadPolicyApplyRequest(path, overrideName string) (cliPolicyApplyRequest, e
 	if err := decodeStructuredFile(path, &raw); err != nil {
 		return cliPolicyApplyRequest{}, err
 	}
+	return policyApplyRequestFromDocument(raw, overrideName)
+}
 
+func policyApplyRequestFromDocument(raw map[string]any, overrideName string) (cliPolicyApplyRequest, error) {
 	var req cliPolicyApplyRequest
 	if _, ok := raw["spec"]; ok {
 		data, err := json.Marshal(raw)
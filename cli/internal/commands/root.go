@@ -1,8 +1,13 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/runagents/runagents/cli/internal/client"
 	"github.com/runagents/runagents/cli/internal/config"
@@ -19,6 +24,9 @@ var (
 	flagEndpoint string
 	flagAPIKey   string
 	flagOutput   string
+	flagProfile  string
+	flagRetries  int
+	flagTimeout  time.Duration
 )
 
 const (
@@ -42,7 +50,8 @@ var rootCmd = &cobra.Command{
 		"  " + website + "\n\n" +
 		"  Deploy and orchestrate AI agents with identity propagation, policy-driven\n" +
 		"  access control, and just-in-time approval workflows.\n",
-	SilenceUsage: true,
+	SilenceUsage:  true,
+	SilenceErrors: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		mode, err := resolvedAssistantMode()
 		if err != nil {
@@ -56,7 +65,7 @@ var rootCmd = &cobra.Command{
 			fmt.Fprintln(os.Stderr, "Use explicit CLI commands, or set 'runagents config set assistant-mode runagents' to enable the shell.")
 			return cmd.Help()
 		}
-		return runDefaultInteractiveShell()
+		return runDefaultInteractiveShell(cmd.Context())
 	},
 }
 
@@ -75,6 +84,9 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&flagEndpoint, "endpoint", "", "API endpoint URL (overrides config)")
 	rootCmd.PersistentFlags().StringVar(&flagAPIKey, "api-key", "", "API key (overrides config)")
 	rootCmd.PersistentFlags().StringVarP(&flagOutput, "output", "o", "table", "Output format: table or json")
+	rootCmd.PersistentFlags().StringVar(&flagProfile, "profile", "", "Config profile to use (overrides RUNAGENTS_PROFILE and the current profile)")
+	rootCmd.PersistentFlags().DurationVar(&flagTimeout, "request-timeout", client.DefaultTimeout, "Timeout for each API request (0 disables)")
+	rootCmd.PersistentFlags().IntVar(&flagRetries, "retries", client.DefaultRetryPolicy().MaxRetries, "Retries for transient API failures on idempotent requests (0 disables)")
 
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(newConfigCmd())
@@ -84,7 +96,12 @@ func init() {
 	rootCmd.AddCommand(newToolsCmd())
 	rootCmd.AddCommand(newModelsCmd())
 	rootCmd.AddCommand(newRunsCmd())
+	rootCmd.AddCommand(newConversationsCmd())
 	rootCmd.AddCommand(newDeployCmd())
+	rootCmd.AddCommand(newBuildsCmd())
+	rootCmd.AddCommand(newApplyCmd())
+	rootCmd.AddCommand(newDiffCmd())
+	rootCmd.AddCommand(newExportCmd())
 	rootCmd.AddCommand(newCatalogCmd())
 	rootCmd.AddCommand(newPoliciesCmd())
 	rootCmd.AddCommand(newIdentityProvidersCmd())
@@ -95,15 +112,29 @@ func init() {
 	rootCmd.AddCommand(newCopilotCmd())
 }
 
-// Execute runs the root command.
+// Execute runs the root command. Ctrl-C cancels the command's context, which
+// aborts in-flight API requests; a second Ctrl-C exits immediately.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
+		if ctx.Err() != nil {
+			err = &exitCodeError{code: exitCodeInterrupted, err: errors.New("interrupted")}
+		}
+		message, code := describeError(err)
+		fmt.Fprintln(os.Stderr, "Error:", message)
+		os.Exit(code)
 	}
 }
 
 // newAPIClient creates a new API client using config and flag overrides.
-func newAPIClient() (*client.Client, error) {
+// Requests made through it are cancelled when ctx is done.
+func newAPIClient(ctx context.Context) (*client.Client, error) {
 	endpoint, apiKey, err := resolvedAPISettings()
 	if err != nil {
 		return nil, err
@@ -111,11 +142,31 @@ func newAPIClient() (*client.Client, error) {
 	if endpoint == "" {
 		return nil, fmt.Errorf("no endpoint configured; run 'runagents config set endpoint <url>' or use --endpoint")
 	}
-	return client.NewClient(endpoint, apiKey), nil
+	retry := client.DefaultRetryPolicy()
+	retry.MaxRetries = flagRetries
+	if retry.MaxRetries < 0 {
+		retry.MaxRetries = 0
+	}
+	c := client.NewClient(endpoint, apiKey, client.WithRetryPolicy(retry), client.WithTimeout(apiRequestTimeout()))
+	return c.WithContext(ctx), nil
+}
+
+// apiRequestTimeout returns the per-request timeout from --request-timeout.
+func apiRequestTimeout() time.Duration {
+	if flagTimeout < 0 {
+		return 0
+	}
+	return flagTimeout
+}
+
+// loadConfig loads the config for the profile selected by --profile,
+// RUNAGENTS_PROFILE, or the current profile.
+func loadConfig() (*config.Config, error) {
+	return config.LoadProfile(flagProfile)
 }
 
 func resolvedAPISettings() (endpoint, apiKey string, err error) {
-	cfg, err := config.Load()
+	cfg, err := loadConfig()
 	if err != nil {
 		return "", "", fmt.Errorf("failed to load config: %w", err)
 	}
@@ -125,15 +176,18 @@ func resolvedAPISettings() (endpoint, apiKey string, err error) {
 		endpoint = flagEndpoint
 	}
 
-	apiKey = cfg.APIKey
 	if flagAPIKey != "" {
-		apiKey = flagAPIKey
+		return endpoint, flagAPIKey, nil
+	}
+	apiKey, err = cfg.ResolveAPIKey()
+	if err != nil {
+		return "", "", err
 	}
 	return endpoint, apiKey, nil
 }
 
 func resolvedAssistantMode() (string, error) {
-	cfg, err := config.Load()
+	cfg, err := loadConfig()
 	if err != nil {
 		return "", fmt.Errorf("failed to load config: %w", err)
 	}
@@ -0,0 +1,23 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func TestSubcommandFlagsDoNotShadowGlobalFlags(t *testing.T) {
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		cmd.LocalNonPersistentFlags().VisitAll(func(flag *pflag.Flag) {
+			if rootCmd.PersistentFlags().Lookup(flag.Name) != nil {
+				t.Errorf("%s --%s shadows the global flag", cmd.CommandPath(), flag.Name)
+			}
+		})
+		for _, child := range cmd.Commands() {
+			walk(child)
+		}
+	}
+	walk(rootCmd)
+}
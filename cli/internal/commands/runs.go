@@ -6,9 +6,12 @@ import (
 	"fmt"
 	"net/url"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/runagents/runagents/cli/internal/client"
 	"github.com/spf13/cobra"
 )
 
@@ -49,9 +52,10 @@ type cliRunTimelineEntry struct {
 }
 
 type cliRunExport struct {
-	Run      cliRun                `json:"run"`
-	Events   []cliRunEvent         `json:"events"`
-	Timeline []cliRunTimelineEntry `json:"timeline"`
+	Run       cliRun                 `json:"run"`
+	Events    []cliRunEvent          `json:"events"`
+	Timeline  []cliRunTimelineEntry  `json:"timeline"`
+	Integrity *cliRunExportIntegrity `json:"integrity,omitempty"`
 }
 
 type runFilters struct {
@@ -59,6 +63,8 @@ type runFilters struct {
 	Status         string
 	UserID         string
 	ConversationID string
+	Since          time.Time
+	Until          time.Time
 	Limit          int
 }
 
@@ -70,6 +76,7 @@ type runEventFilters struct {
 type runAPIClient interface {
 	Get(string) ([]byte, error)
 	GetWithQuery(string, url.Values) ([]byte, error)
+	Paginate(string, url.Values, func([]json.RawMessage) (bool, error)) error
 }
 
 func newRunsCmd() *cobra.Command {
@@ -83,37 +90,40 @@ func newRunsCmd() *cobra.Command {
 	cmd.AddCommand(newRunsEventsCmd())
 	cmd.AddCommand(newRunsTimelineCmd())
 	cmd.AddCommand(newRunsWaitCmd())
+	cmd.AddCommand(newRunsCancelCmd())
 	cmd.AddCommand(newRunsExportCmd())
+	cmd.AddCommand(newRunsExportTracesCmd())
+	cmd.AddCommand(newRunsReportCmd())
+	cmd.AddCommand(newRunsVerifyCmd())
+	cmd.AddCommand(newRunsDiffCmd())
+	cmd.AddCommand(newRunsSearchCmd())
+	cmd.AddCommand(newRunsActionsCmd())
+	cmd.AddCommand(newRunsStatsCmd())
 
 	return cmd
 }
 
 func newRunsListCmd() *cobra.Command {
-	filters := runFilters{Limit: 50}
+	var (
+		filters runFilters
+		list    listOptions
+	)
 
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List runs with operator-friendly filters",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
 
-			query := url.Values{}
-			if strings.TrimSpace(filters.AgentID) != "" {
-				query.Set("agent_id", strings.TrimSpace(filters.AgentID))
-			}
-			if strings.TrimSpace(filters.Status) != "" {
-				query.Set("status", strings.TrimSpace(filters.Status))
-			}
-
-			runs, err := fetchRuns(c, query)
+			filters.Limit = list.limitOrAll()
+			runs, truncated, err := fetchRuns(c, filters)
 			if err != nil {
 				return err
 			}
-			runs = filterRuns(runs, filters)
 
 			if isJSONOutput() {
 				return printJSONValue(runs)
@@ -134,6 +144,7 @@ func newRunsListCmd() *cobra.Command {
 				})
 			}
 			table.Render()
+			printListTruncatedHint(truncated, len(runs), "runs")
 			return nil
 		},
 	}
@@ -142,7 +153,7 @@ func newRunsListCmd() *cobra.Command {
 	cmd.Flags().StringVar(&filters.Status, "status", "", "Filter runs by status")
 	cmd.Flags().StringVar(&filters.UserID, "user", "", "Filter runs by user ID")
 	cmd.Flags().StringVar(&filters.ConversationID, "conversation", "", "Filter runs by conversation ID")
-	cmd.Flags().IntVar(&filters.Limit, "limit", 50, "Maximum number of runs to display (0 for all)")
+	addListFlags(cmd, &list, "runs", 50)
 
 	return cmd
 }
@@ -153,7 +164,7 @@ func newRunsGetCmd() *cobra.Command {
 		Short: "Get details for a specific run",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
@@ -171,14 +182,18 @@ func newRunsGetCmd() *cobra.Command {
 }
 
 func newRunsEventsCmd() *cobra.Command {
-	filters := runEventFilters{Limit: 100}
+	var (
+		filters  = runEventFilters{Limit: 100}
+		follow   bool
+		interval time.Duration
+	)
 
 	cmd := &cobra.Command{
 		Use:   "events <run-id>",
 		Short: "Show run events with meaningful summaries",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
@@ -186,6 +201,9 @@ func newRunsEventsCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if follow {
+				return followRunCommand(cmd.Context(), c, args[0], events, filters, interval, false)
+			}
 			events = filterRunEvents(events, filters)
 			if isJSONOutput() {
 				return printJSONValue(events)
@@ -211,16 +229,22 @@ func newRunsEventsCmd() *cobra.Command {
 
 	cmd.Flags().StringVar(&filters.Type, "type", "", "Filter events by type")
 	cmd.Flags().IntVar(&filters.Limit, "limit", 100, "Maximum number of events to display (0 for all)")
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Keep printing new events until the run reaches a terminal status")
+	cmd.Flags().DurationVar(&interval, "interval", 2*time.Second, "Polling interval when the API does not stream events")
 	return cmd
 }
 
 func newRunsTimelineCmd() *cobra.Command {
-	return &cobra.Command{
+	var (
+		follow   bool
+		interval time.Duration
+	)
+	cmd := &cobra.Command{
 		Use:   "timeline <run-id>",
 		Short: "Show an operator timeline for a run",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
@@ -232,83 +256,60 @@ func newRunsTimelineCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if follow {
+				return followRunCommand(cmd.Context(), c, args[0], events, runEventFilters{}, interval, true)
+			}
 			timeline := buildRunTimeline(*run, events)
 			if isJSONOutput() {
 				return printJSONValue(map[string]any{"run": run, "timeline": timeline})
 			}
-			if len(timeline) == 0 {
-				fmt.Printf("Run %s has no timeline entries yet. Current status: %s\n", run.ID, run.Status)
-				return nil
-			}
-			table := newTable("SEQ", "TYPE", "DETAIL", "TIMESTAMP")
-			for _, entry := range timeline {
-				seq := ""
-				if entry.Seq > 0 {
-					seq = fmt.Sprintf("%d", entry.Seq)
-				}
-				table.Append([]string{seq, entry.Type, entry.Summary, formatRunTime(entry.Timestamp)})
-			}
-			table.Render()
+			printRunTimeline(*run, timeline)
 			return nil
 		},
 	}
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Keep printing new entries until the run reaches a terminal status")
+	cmd.Flags().DurationVar(&interval, "interval", 2*time.Second, "Polling interval when the API does not stream events")
+	return cmd
 }
 
-func newRunsWaitCmd() *cobra.Command {
+func printRunTimeline(run cliRun, timeline []cliRunTimelineEntry) {
+	if len(timeline) == 0 {
+		fmt.Printf("Run %s has no timeline entries yet. Current status: %s\n", run.ID, run.Status)
+		return
+	}
+	table := newTable("SEQ", "TYPE", "DETAIL", "TIMESTAMP")
+	for _, entry := range timeline {
+		seq := ""
+		if entry.Seq > 0 {
+			seq = fmt.Sprintf("%d", entry.Seq)
+		}
+		table.Append([]string{seq, entry.Type, entry.Summary, formatRunTime(entry.Timestamp)})
+	}
+	table.Render()
+}
+
+func newRunsExportCmd() *cobra.Command {
 	var (
-		timeout  time.Duration
-		interval time.Duration
+		format  string
+		signKey string
+		push    otlpPushOptions
 	)
 	cmd := &cobra.Command{
-		Use:   "wait <run-id>",
-		Short: "Wait for a run to reach a terminal status",
+		Use:   "export <run-id>",
+		Short: "Export a run, its events, and an operator timeline as JSON or an OpenTelemetry trace",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newAPIClient()
-			if err != nil {
-				return err
+			format = strings.ToLower(strings.TrimSpace(format))
+			if format != "json" && format != "otlp-json" {
+				return fmt.Errorf("invalid --format %q: use json or otlp-json", format)
 			}
-			ctx, cancel := context.WithTimeout(context.Background(), timeout)
-			defer cancel()
-
-			var finalRun *cliRun
-			err = waitForCondition(ctx, interval, func(ctx context.Context) (bool, error) {
-				run, runErr := fetchRun(c, args[0])
-				if runErr != nil {
-					return false, runErr
-				}
-				finalRun = run
-				return isTerminalRunStatus(run.Status), nil
-			})
-			if err != nil {
-				if err == context.DeadlineExceeded {
-					return fmt.Errorf("timed out waiting for run %q to finish", args[0])
-				}
-				return err
+			if push.Endpoint != "" && format != "otlp-json" {
+				return fmt.Errorf("--otlp-endpoint requires --format otlp-json")
 			}
-			if finalRun == nil {
-				return fmt.Errorf("run %q did not return a final state", args[0])
+			if signKey != "" && format != "json" {
+				return fmt.Errorf("--sign-key requires --format json")
 			}
-			if isJSONOutput() {
-				return printJSONValue(finalRun)
-			}
-			fmt.Printf("Run %q reached terminal status %s.\n\n", finalRun.ID, finalRun.Status)
-			printRun(*finalRun)
-			return nil
-		},
-	}
-	cmd.Flags().DurationVar(&timeout, "timeout", 5*time.Minute, "Maximum time to wait for the run to finish")
-	cmd.Flags().DurationVar(&interval, "interval", 2*time.Second, "Polling interval while waiting")
-	return cmd
-}
-
-func newRunsExportCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "export <run-id>",
-		Short: "Export a run, its events, and an operator timeline as JSON",
-		Args:  cobra.ExactArgs(1),
-		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
@@ -320,29 +321,157 @@ func newRunsExportCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if format == "otlp-json" {
+				return exportRunOTLP(cmd.Context(), *run, events, push)
+			}
 			export := cliRunExport{
 				Run:      *run,
 				Events:   events,
 				Timeline: buildRunTimeline(*run, events),
 			}
+			if signKey != "" {
+				key, err := loadEd25519PrivateKey(signKey)
+				if err != nil {
+					return err
+				}
+				if err := signRunExport(&export, key, time.Now().UTC()); err != nil {
+					return err
+				}
+			}
 			if isJSONOutput() {
 				return printJSONValue(export)
 			}
 			return printIndentedJSONValue(export)
 		},
 	}
+	cmd.Flags().StringVar(&format, "format", "json", "Export format: json or otlp-json (an OpenTelemetry trace)")
+	cmd.Flags().StringVar(&signKey, "sign-key", "", "Sign the bundle with this PEM Ed25519 private key so 'runs verify --bundle' can detect edits")
+	addOTLPPushFlags(cmd, &push)
+	return cmd
 }
 
-func fetchRuns(c runAPIClient, query url.Values) ([]cliRun, error) {
-	data, err := c.GetWithQuery("/runs", query)
+// fetchRuns lists runs matching filters, newest first. Agent, status, user
+// and conversation filters are sent to the server; user and conversation are
+// checked again locally for servers that ignore them. Pages are followed until
+// filters.Limit runs match (every page when the limit is 0), and the returned
+// flag reports whether more runs may exist.
+func fetchRuns(c runAPIClient, filters runFilters) ([]cliRun, bool, error) {
+	query := runListQuery(filters)
+	var runs []cliRun
+	stopped := false
+	err := c.Paginate("/runs", query, func(items []json.RawMessage) (bool, error) {
+		page := make([]cliRun, 0, len(items))
+		for _, item := range items {
+			var run cliRun
+			if err := json.Unmarshal(item, &run); err != nil {
+				return false, fmt.Errorf("failed to parse runs response: %w", err)
+			}
+			page = append(page, run)
+			if runMatchesFilters(run, filters) {
+				runs = append(runs, run)
+			}
+		}
+		if filters.Limit > 0 && len(runs) >= filters.Limit {
+			stopped = true
+			return false, nil
+		}
+		return !runPageEndsBefore(page, filters.Since), nil
+	})
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
-	var runs []cliRun
-	if err := json.Unmarshal(data, &runs); err != nil {
-		return nil, fmt.Errorf("failed to parse runs response: %w", err)
+	truncated := stopped || (filters.Limit > 0 && len(runs) > filters.Limit)
+	return filterRuns(runs, filters), truncated, nil
+}
+
+// runPageEndsBefore reports whether a page of runs is ordered newest first
+// and ends before since, so that later pages cannot hold newer runs.
+func runPageEndsBefore(page []cliRun, since time.Time) bool {
+	if since.IsZero() || len(page) < 2 {
+		return false
+	}
+	for i := 1; i < len(page); i++ {
+		if runStartTime(page[i]).After(runStartTime(page[i-1])) {
+			return false
+		}
+	}
+	first, last := runStartTime(page[0]), runStartTime(page[len(page)-1])
+	return first.After(last) && last.Before(since)
+}
+
+func runListQuery(filters runFilters) url.Values {
+	query := url.Values{}
+	if value := strings.TrimSpace(filters.AgentID); value != "" {
+		query.Set("agent_id", value)
+	}
+	if value := strings.TrimSpace(filters.Status); value != "" {
+		query.Set("status", value)
+	}
+	if value := strings.TrimSpace(filters.UserID); value != "" {
+		query.Set("user_id", value)
 	}
-	return runs, nil
+	if value := strings.TrimSpace(filters.ConversationID); value != "" {
+		query.Set("conversation_id", value)
+	}
+	if !filters.Since.IsZero() {
+		query.Set("created_after", filters.Since.UTC().Format(time.RFC3339))
+	}
+	if !filters.Until.IsZero() {
+		query.Set("created_before", filters.Until.UTC().Format(time.RFC3339))
+	}
+	query.Set("limit", listOptions{Limit: filters.Limit}.pageSize())
+	return query
+}
+
+func runMatchesFilters(run cliRun, filters runFilters) bool {
+	if filters.UserID != "" && run.UserID != filters.UserID {
+		return false
+	}
+	if filters.ConversationID != "" && run.ConversationID != filters.ConversationID {
+		return false
+	}
+	if started := runStartTime(run); !started.IsZero() {
+		if !filters.Since.IsZero() && started.Before(filters.Since) {
+			return false
+		}
+		if !filters.Until.IsZero() && !started.Before(filters.Until) {
+			return false
+		}
+	}
+	return true
+}
+
+// runStartTime returns when the run was created, falling back to its last
+// update for records without a creation time.
+func runStartTime(run cliRun) time.Time {
+	if !run.CreatedAt.IsZero() {
+		return run.CreatedAt
+	}
+	return run.UpdatedAt
+}
+
+// parseRunTimeBound parses a --since/--until value: an RFC 3339 timestamp, a
+// date (2006-01-02, UTC), or a duration before now such as 90m, 24h or 7d.
+func parseRunTimeBound(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if ts, err := time.Parse(time.RFC3339, value); err == nil {
+		return ts, nil
+	}
+	if ts, err := time.Parse("2006-01-02", value); err == nil {
+		return ts, nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: use an RFC 3339 timestamp, a date (2006-01-02), or a duration such as 24h or 7d", value)
 }
 
 func fetchRun(c interface{ Get(string) ([]byte, error) }, id string) (*cliRun, error) {
@@ -357,11 +486,34 @@ func fetchRun(c interface{ Get(string) ([]byte, error) }, id string) (*cliRun, e
 	return &run, nil
 }
 
+// fetchRunEvents returns the events of a run: the first limit of them, or
+// with limit 0 every event, paging with after_seq until the server returns a
+// short page or stops advancing.
 func fetchRunEvents(c runAPIClient, runID string, limit int) ([]cliRunEvent, error) {
-	query := url.Values{}
 	if limit > 0 {
-		query.Set("limit", fmt.Sprintf("%d", limit))
+		return getRunEvents(c, runID, url.Values{"limit": {strconv.Itoa(limit)}})
+	}
+	var events []cliRunEvent
+	afterSeq := 0
+	for {
+		query := url.Values{"limit": {strconv.Itoa(listPageSize)}}
+		if afterSeq > 0 {
+			query.Set("after_seq", strconv.Itoa(afterSeq))
+		}
+		page, err := getRunEvents(c, runID, query)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, page...)
+		last := lastRunEventSeq(page)
+		if len(page) < listPageSize || last <= afterSeq {
+			return events, nil
+		}
+		afterSeq = last
 	}
+}
+
+func getRunEvents(c runAPIClient, runID string, query url.Values) ([]cliRunEvent, error) {
 	data, err := c.GetWithQuery(fmt.Sprintf("/runs/%s/events", runID), query)
 	if err != nil {
 		return nil, err
@@ -373,16 +525,71 @@ func fetchRunEvents(c runAPIClient, runID string, limit int) ([]cliRunEvent, err
 	return events, nil
 }
 
+// fetchRunEventsForRuns fetches the events of each run with at most workers
+// requests in flight, stopping at the first error. Requests made through a
+// *client.Client are bound to ctx, so cancelling it aborts them.
+func fetchRunEventsForRuns(ctx context.Context, c runAPIClient, runIDs []string, workers int) (map[string][]cliRunEvent, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if bound, ok := c.(interface {
+		WithContext(context.Context) *client.Client
+	}); ok {
+		c = bound.WithContext(ctx)
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		results  = make(map[string][]cliRunEvent, len(runIDs))
+		ids      = make(chan string)
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range ids {
+				events, err := fetchRunEvents(c, id, 0)
+				mu.Lock()
+				if err != nil && firstErr == nil {
+					firstErr = fmt.Errorf("run %s: %w", id, err)
+					cancel()
+				} else if err == nil {
+					results[id] = events
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+send:
+	for _, id := range runIDs {
+		select {
+		case ids <- id:
+		case <-ctx.Done():
+			break send
+		}
+	}
+	close(ids)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
 func filterRuns(runs []cliRun, filters runFilters) []cliRun {
 	filtered := make([]cliRun, 0, len(runs))
 	for _, run := range runs {
-		if filters.UserID != "" && run.UserID != filters.UserID {
-			continue
-		}
-		if filters.ConversationID != "" && run.ConversationID != filters.ConversationID {
-			continue
+		if runMatchesFilters(run, filters) {
+			filtered = append(filtered, run)
 		}
-		filtered = append(filtered, run)
 	}
 	sort.Slice(filtered, func(i, j int) bool {
 		left := filtered[i].UpdatedAt
@@ -432,18 +639,22 @@ func buildRunTimeline(run cliRun, events []cliRunEvent) []cliRunTimelineEntry {
 	}
 	timeline := make([]cliRunTimelineEntry, 0, len(events))
 	for _, event := range events {
-		timeline = append(timeline, cliRunTimelineEntry{
-			Seq:       event.Seq,
-			Type:      event.Type,
-			Actor:     event.Actor,
-			Summary:   summarizeRunEvent(event),
-			Timestamp: event.Timestamp,
-			Data:      event.Data,
-		})
+		timeline = append(timeline, runTimelineEntry(event))
 	}
 	return timeline
 }
 
+func runTimelineEntry(event cliRunEvent) cliRunTimelineEntry {
+	return cliRunTimelineEntry{
+		Seq:       event.Seq,
+		Type:      event.Type,
+		Actor:     event.Actor,
+		Summary:   summarizeRunEvent(event),
+		Timestamp: event.Timestamp,
+		Data:      event.Data,
+	}
+}
+
 func summarizeRunEvent(event cliRunEvent) string {
 	if message := firstNonEmptyRunValue(dataString(event.Data, "message"), dataString(event.Data, "detail"), dataString(event.Data, "summary")); message != "" {
 		return truncateRunMessage(message, 120)
@@ -533,31 +744,47 @@ func summarizeToolRequestEvent(event cliRunEvent) string {
 }
 
 func printRun(run cliRun) {
-	fmt.Printf("ID:             %s\n", run.ID)
-	fmt.Printf("Agent:          %s\n", run.AgentID)
-	fmt.Printf("User:           %s\n", run.UserID)
-	fmt.Printf("Status:         %s\n", run.Status)
-	fmt.Printf("Namespace:      %s\n", run.Namespace)
-	if run.ConversationID != "" {
-		fmt.Printf("Conversation:   %s\n", run.ConversationID)
-	}
-	if run.BlockedActionID != "" {
-		fmt.Printf("Blocked Action: %s\n", run.BlockedActionID)
+	for _, field := range runDetailFields(run) {
+		fmt.Printf("%-16s%s\n", field.Label+":", field.Value)
 	}
-	if run.SurfaceTurnID != "" {
-		fmt.Printf("Surface Turn:   %s\n", run.SurfaceTurnID)
-	}
-	if run.InvokeURL != "" {
-		fmt.Printf("Invoke URL:     %s\n", run.InvokeURL)
-	}
-	fmt.Printf("Created:        %s\n", formatRunTime(run.CreatedAt))
-	fmt.Printf("Updated:        %s\n", formatRunTime(run.UpdatedAt))
 	if run.InitialMessage != "" {
 		fmt.Println()
 		fmt.Printf("Initial Message:\n%s\n", run.InitialMessage)
 	}
 }
 
+type runDetailField struct {
+	Label string
+	Value string
+}
+
+// runDetailFields lists the header fields shown for a run, skipping optional
+// fields that are empty.
+func runDetailFields(run cliRun) []runDetailField {
+	fields := []runDetailField{
+		{Label: "ID", Value: run.ID},
+		{Label: "Agent", Value: run.AgentID},
+		{Label: "User", Value: run.UserID},
+		{Label: "Status", Value: run.Status},
+		{Label: "Namespace", Value: run.Namespace},
+	}
+	optional := []runDetailField{
+		{Label: "Conversation", Value: run.ConversationID},
+		{Label: "Blocked Action", Value: run.BlockedActionID},
+		{Label: "Surface Turn", Value: run.SurfaceTurnID},
+		{Label: "Invoke URL", Value: run.InvokeURL},
+	}
+	for _, field := range optional {
+		if field.Value != "" {
+			fields = append(fields, field)
+		}
+	}
+	return append(fields,
+		runDetailField{Label: "Created", Value: formatRunTime(run.CreatedAt)},
+		runDetailField{Label: "Updated", Value: formatRunTime(run.UpdatedAt)},
+	)
+}
+
 func printJSONValue(v any) error {
 	data, err := json.Marshal(v)
 	if err != nil {
@@ -592,6 +819,17 @@ func isTerminalRunStatus(status string) bool {
 	}
 }
 
+// isBlockedRunStatus reports whether a run is paused until someone acts on
+// it, such as approving a tool call or granting consent.
+func isBlockedRunStatus(status string) bool {
+	switch strings.ToUpper(strings.TrimSpace(status)) {
+	case "PAUSED", "PAUSED_APPROVAL", "PAUSED_CONSENT":
+		return true
+	default:
+		return false
+	}
+}
+
 func dataString(data map[string]any, key string) string {
 	if data == nil {
 		return ""
@@ -0,0 +1,309 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/runagents/runagents/cli/internal/client"
+	"github.com/spf13/cobra"
+)
+
+type cliRunAction struct {
+	ActionID    string    `json:"action_id"`
+	RunID       string    `json:"run_id"`
+	ToolID      string    `json:"tool_id"`
+	Capability  string    `json:"capability,omitempty"`
+	PayloadHash string    `json:"payload_hash,omitempty"`
+	Status      string    `json:"status"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type runActionApprovalBody struct {
+	PayloadHash string `json:"payload_hash,omitempty"`
+	Scope       string `json:"scope,omitempty"`
+	Duration    string `json:"duration,omitempty"`
+}
+
+func newRunsActionsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "actions",
+		Short: "Inspect and approve governed actions on a run",
+	}
+
+	cmd.AddCommand(newRunsActionsListCmd())
+	cmd.AddCommand(newRunsActionsGetCmd())
+	cmd.AddCommand(newRunsActionsApproveCmd())
+
+	return cmd
+}
+
+func newRunsActionsListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list <run-id>",
+		Short: "List governed actions recorded for a run",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newAPIClient(cmd.Context())
+			if err != nil {
+				return err
+			}
+			run, err := fetchRun(c, args[0])
+			if err != nil {
+				return err
+			}
+			actions, err := fetchRunActions(c, *run)
+			if err != nil {
+				return err
+			}
+			if isJSONOutput() {
+				return printJSONValue(actions)
+			}
+			if len(actions) == 0 {
+				fmt.Printf("Run %s has no governed actions. Current status: %s\n", run.ID, run.Status)
+				return nil
+			}
+			table := newTable("ACTION ID", "TOOL", "CAPABILITY", "STATUS", "BLOCKING", "UPDATED")
+			for _, action := range actions {
+				blocking := ""
+				if action.ActionID == run.BlockedActionID {
+					blocking = "yes"
+				}
+				table.Append([]string{
+					action.ActionID,
+					action.ToolID,
+					action.Capability,
+					action.Status,
+					blocking,
+					formatRunTime(action.UpdatedAt),
+				})
+			}
+			table.Render()
+			return nil
+		},
+	}
+}
+
+func newRunsActionsGetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "get <run-id> [action-id]",
+		Short: "Show a governed action (defaults to the action blocking the run)",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newAPIClient(cmd.Context())
+			if err != nil {
+				return err
+			}
+			actionID, err := resolveRunActionID(c, args)
+			if err != nil {
+				return err
+			}
+			action, err := fetchRunAction(c, args[0], actionID)
+			if err != nil {
+				return err
+			}
+			if isJSONOutput() {
+				return printJSONValue(action)
+			}
+			printRunAction(*action)
+			return nil
+		},
+	}
+}
+
+func newRunsActionsApproveCmd() *cobra.Command {
+	var (
+		scope    string
+		duration string
+	)
+	cmd := &cobra.Command{
+		Use:   "approve <run-id> [action-id]",
+		Short: "Approve a blocked action (defaults to the action blocking the run)",
+		Long: "Approve a blocked action so the run can resume. Without an action ID, the action\n" +
+			"the run is currently blocked on is approved. The approval is bound to the action's\n" +
+			"payload hash, so it does not apply if the pending call changes.\n\n" +
+			"--scope and --duration are sent as a request. The documented approve endpoint only\n" +
+			"takes the payload hash, so a wider scope is reported as granted only when the\n" +
+			"server's response confirms it.",
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			runID := args[0]
+			decision, err := buildApprovalDecision(scope, duration)
+			if err != nil {
+				return err
+			}
+
+			c, err := newAPIClient(cmd.Context())
+			if err != nil {
+				return err
+			}
+			actionID, err := resolveRunActionID(c, args)
+			if err != nil {
+				return err
+			}
+			data, err := approveRunAction(c, runID, actionID, decision)
+			if err != nil {
+				return err
+			}
+			if isJSONOutput() {
+				fmt.Println(string(data))
+				return nil
+			}
+			fmt.Println(describeRunActionApproval(fmt.Sprintf("Action %q on run %q", actionID, runID), decision, data))
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&scope, "scope", "", "Requested approval scope: once, run, or window (the server may approve this action only)")
+	cmd.Flags().StringVar(&duration, "duration", "", "Requested approval duration for window scope (for example 1h or 4h)")
+	return cmd
+}
+
+// approveRunAction approves a BLOCKED action, binding the approval to the
+// action's current payload hash.
+func approveRunAction(c interface {
+	Get(string) ([]byte, error)
+	Post(string, interface{}) ([]byte, error)
+}, runID, actionID string, decision *approvalDecisionBody) ([]byte, error) {
+	action, err := fetchRunAction(c, runID, actionID)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(action.Status, "BLOCKED") {
+		return nil, fmt.Errorf("action %q on run %q is %s, not BLOCKED", actionID, runID, action.Status)
+	}
+	return c.Post(fmt.Sprintf("/runs/%s/actions/%s/approve", runID, actionID), runActionApproval(action.PayloadHash, decision))
+}
+
+// describeRunActionApproval describes an approval, claiming the requested
+// scope only when the server's response confirms it.
+func describeRunActionApproval(subject string, decision *approvalDecisionBody, response []byte) string {
+	if decision == nil || decision.Scope == "" || decision.Scope == "once" {
+		return describeApprovalDecision(subject, decision)
+	}
+	var applied struct {
+		Scope string `json:"scope"`
+	}
+	if json.Unmarshal(response, &applied) == nil && strings.EqualFold(applied.Scope, decision.Scope) {
+		return describeApprovalDecision(subject, decision)
+	}
+	requested := "run-wide scope"
+	if decision.Scope == "agent_user_ttl" {
+		requested = "time window"
+		if decision.Duration != "" {
+			requested = decision.Duration + " window"
+		}
+	}
+	return fmt.Sprintf("%s approved. The %s was requested, but the server did not confirm it, so the approval may cover this action only.", subject, requested)
+}
+
+func runActionApproval(payloadHash string, decision *approvalDecisionBody) runActionApprovalBody {
+	body := runActionApprovalBody{PayloadHash: payloadHash}
+	if decision != nil {
+		body.Scope = decision.Scope
+		body.Duration = decision.Duration
+	}
+	return body
+}
+
+// resolveRunActionID returns the action ID from args ([run-id, action-id]),
+// falling back to the action the run is blocked on.
+func resolveRunActionID(c interface{ Get(string) ([]byte, error) }, args []string) (string, error) {
+	if len(args) > 1 && strings.TrimSpace(args[1]) != "" {
+		return strings.TrimSpace(args[1]), nil
+	}
+	run, err := fetchRun(c, args[0])
+	if err != nil {
+		return "", err
+	}
+	if run.BlockedActionID == "" {
+		return "", fmt.Errorf("run %q is not blocked on an action (status %s); pass an action ID", run.ID, run.Status)
+	}
+	return run.BlockedActionID, nil
+}
+
+func fetchRunAction(c interface{ Get(string) ([]byte, error) }, runID, actionID string) (*cliRunAction, error) {
+	data, err := c.Get(fmt.Sprintf("/runs/%s/actions/%s", runID, actionID))
+	if err != nil {
+		return nil, err
+	}
+	var action cliRunAction
+	if err := json.Unmarshal(data, &action); err != nil {
+		return nil, fmt.Errorf("failed to parse run action response: %w", err)
+	}
+	return &action, nil
+}
+
+// fetchRunActions lists the run's governed actions. Deployments that do not
+// serve GET /runs/{id}/actions are handled by fetching every action the run
+// and its events refer to.
+func fetchRunActions(c runAPIClient, run cliRun) ([]cliRunAction, error) {
+	data, err := c.Get(fmt.Sprintf("/runs/%s/actions", run.ID))
+	if err == nil {
+		var actions []cliRunAction
+		if err := json.Unmarshal(data, &actions); err != nil {
+			return nil, fmt.Errorf("failed to parse run actions response: %w", err)
+		}
+		return actions, nil
+	}
+	if !client.IsNotFound(err) && client.StatusCode(err) != http.StatusMethodNotAllowed {
+		return nil, err
+	}
+
+	events, err := fetchRunEvents(c, run.ID, 0)
+	if err != nil {
+		return nil, err
+	}
+	actions := []cliRunAction{}
+	for _, actionID := range runActionIDs(run, events) {
+		action, err := fetchRunAction(c, run.ID, actionID)
+		if err != nil {
+			if client.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		actions = append(actions, *action)
+	}
+	return actions, nil
+}
+
+// runActionIDs returns the action IDs referenced by events, in order, plus
+// the run's blocked action.
+func runActionIDs(run cliRun, events []cliRunEvent) []string {
+	seen := map[string]bool{}
+	var ids []string
+	add := func(id string) {
+		id = strings.TrimSpace(id)
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, event := range events {
+		add(dataString(event.Data, "action_id"))
+		add(dataString(event.Data, "blocked_action_id"))
+	}
+	add(run.BlockedActionID)
+	return ids
+}
+
+func printRunAction(action cliRunAction) {
+	fmt.Printf("Action ID:      %s\n", action.ActionID)
+	fmt.Printf("Run ID:         %s\n", action.RunID)
+	fmt.Printf("Tool:           %s\n", action.ToolID)
+	if action.Capability != "" {
+		fmt.Printf("Capability:     %s\n", action.Capability)
+	}
+	fmt.Printf("Status:         %s\n", action.Status)
+	if action.PayloadHash != "" {
+		fmt.Printf("Payload Hash:   %s\n", action.PayloadHash)
+	}
+	fmt.Printf("Created:        %s\n", formatRunTime(action.CreatedAt))
+	fmt.Printf("Updated:        %s\n", formatRunTime(action.UpdatedAt))
+	if strings.EqualFold(action.Status, "BLOCKED") {
+		fmt.Printf("\nApprove with: runagents runs actions approve %s %s\n", action.RunID, action.ActionID)
+	}
+}
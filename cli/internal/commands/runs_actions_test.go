@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFetchRunActionsFallsBackToActionsReferencedByRun(t *testing.T) {
+	now := time.Date(2026, 4, 9, 10, 0, 0, 0, time.UTC)
+	run := cliRun{ID: "run-1", Status: "PAUSED_APPROVAL", BlockedActionID: "act-2"}
+	fake := &fakeRunsClient{objects: map[string]any{
+		"/runs/run-1/events": []cliRunEvent{
+			{Seq: 1, Type: "TOOL_REQUEST", Timestamp: now},
+			{Seq: 2, Type: "APPROVAL_REQUIRED", Timestamp: now, Data: map[string]any{"action_id": "act-1"}},
+			{Seq: 3, Type: "APPROVAL_REQUIRED", Timestamp: now, Data: map[string]any{"blocked_action_id": "act-2"}},
+		},
+		"/runs/run-1/actions/act-1": cliRunAction{ActionID: "act-1", RunID: "run-1", Status: "EXECUTED"},
+		"/runs/run-1/actions/act-2": cliRunAction{ActionID: "act-2", RunID: "run-1", Status: "BLOCKED"},
+	}}
+
+	actions, err := fetchRunActions(fake, run)
+	if err != nil {
+		t.Fatalf("fetchRunActions returned error: %v", err)
+	}
+	if len(actions) != 2 || actions[0].ActionID != "act-1" || actions[1].ActionID != "act-2" {
+		t.Fatalf("unexpected actions: %+v", actions)
+	}
+}
+
+func TestResolveRunActionIDDefaultsToBlockedAction(t *testing.T) {
+	fake := &fakeRunsClient{objects: map[string]any{
+		"/runs/run-1": cliRun{ID: "run-1", Status: "PAUSED_APPROVAL", BlockedActionID: "act-2"},
+		"/runs/run-2": cliRun{ID: "run-2", Status: "COMPLETED"},
+	}}
+
+	id, err := resolveRunActionID(fake, []string{"run-1"})
+	if err != nil || id != "act-2" {
+		t.Fatalf("expected blocked action act-2, got %q (err %v)", id, err)
+	}
+	id, err = resolveRunActionID(fake, []string{"run-1", "act-9"})
+	if err != nil || id != "act-9" {
+		t.Fatalf("expected explicit action act-9, got %q (err %v)", id, err)
+	}
+	if _, err := resolveRunActionID(fake, []string{"run-2"}); err == nil {
+		t.Fatal("expected error for run without a blocked action")
+	}
+}
+
+func TestRunActionApprovalCarriesPayloadHashAndScope(t *testing.T) {
+	decision, err := buildApprovalDecision("window", "4h")
+	if err != nil {
+		t.Fatalf("buildApprovalDecision returned error: %v", err)
+	}
+	body := runActionApproval("sha256:abc", decision)
+	if body.PayloadHash != "sha256:abc" || body.Scope != "agent_user_ttl" || body.Duration != "4h" {
+		t.Fatalf("unexpected approval body: %+v", body)
+	}
+	confirmed := []byte(`{"action_id":"act-1","status":"APPROVED","scope":"agent_user_ttl"}`)
+	if got := describeRunActionApproval(`Action "act-1"`, decision, confirmed); got != `Action "act-1" approved for 4h.` {
+		t.Fatalf("unexpected description: %q", got)
+	}
+	unconfirmed := []byte(`{"action_id":"act-1","status":"APPROVED"}`)
+	want := `Action "act-1" approved. The 4h window was requested, but the server did not confirm it, so the approval may cover this action only.`
+	if got := describeRunActionApproval(`Action "act-1"`, decision, unconfirmed); got != want {
+		t.Fatalf("unexpected description: %q", got)
+	}
+}
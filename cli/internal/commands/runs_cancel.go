@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// runCancelClient is the subset of the API client runs cancel needs.
+type runCancelClient interface {
+	Get(string) ([]byte, error)
+	Patch(string, interface{}) ([]byte, error)
+}
+
+func newRunsCancelCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "cancel <run-id>",
+		Short: "Stop a stuck run by marking it failed",
+		Long: `Stop a run that is stuck running or paused by marking it FAILED.
+
+Any pending approval or consent request for the run is abandoned. Runs that
+already finished are left untouched.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newAPIClient(cmd.Context())
+			if err != nil {
+				return err
+			}
+			previous, run, err := cancelRun(c, args[0])
+			if err != nil {
+				return err
+			}
+			if isJSONOutput() {
+				return printJSONValue(run)
+			}
+			fmt.Printf("Run %q cancelled (was %s, now %s).\n", run.ID, previous, run.Status)
+			return nil
+		},
+	}
+}
+
+// cancelRun marks a non-terminal run FAILED and returns its previous status
+// along with the updated run.
+func cancelRun(c runCancelClient, id string) (string, *cliRun, error) {
+	run, err := fetchRun(c, id)
+	if err != nil {
+		return "", nil, err
+	}
+	if isTerminalRunStatus(run.Status) {
+		return "", nil, fmt.Errorf("run %q already finished with status %s", id, run.Status)
+	}
+
+	data, err := c.Patch(fmt.Sprintf("/runs/%s", id), map[string]string{"status": "FAILED"})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to cancel run %q: %w", id, err)
+	}
+	updated := *run
+	updated.Status = "FAILED"
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &updated); err != nil {
+			return "", nil, fmt.Errorf("failed to parse run response: %w", err)
+		}
+	}
+	if updated.ID == "" {
+		updated.ID = id
+	}
+	return run.Status, &updated, nil
+}
@@ -0,0 +1,364 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// runDiffSide summarizes one of the compared runs.
+type runDiffSide struct {
+	Run             cliRun   `json:"run"`
+	Events          int      `json:"events"`
+	DurationSeconds float64  `json:"duration_seconds"`
+	Tools           []string `json:"tools"`
+	Capabilities    []string `json:"capabilities"`
+}
+
+// runDiffStep is one aligned pair of timeline entries. Change is "same",
+// "changed", "removed" (only in run A) or "added" (only in run B). Offsets are
+// measured from the start of each run.
+type runDiffStep struct {
+	Change             string               `json:"change"`
+	Key                string               `json:"key"`
+	A                  *cliRunTimelineEntry `json:"a,omitempty"`
+	B                  *cliRunTimelineEntry `json:"b,omitempty"`
+	Differences        []runDiffField       `json:"differences,omitempty"`
+	OffsetASeconds     *float64             `json:"offset_a_seconds,omitempty"`
+	OffsetBSeconds     *float64             `json:"offset_b_seconds,omitempty"`
+	TimingDeltaSeconds *float64             `json:"timing_delta_seconds,omitempty"`
+}
+
+type runDiffField struct {
+	Field string `json:"field"`
+	A     string `json:"a"`
+	B     string `json:"b"`
+}
+
+type runDiffResult struct {
+	A                   runDiffSide   `json:"a"`
+	B                   runDiffSide   `json:"b"`
+	Divergent           bool          `json:"divergent"`
+	ChangedCount        int           `json:"changed_count"`
+	RemovedCount        int           `json:"removed_count"`
+	AddedCount          int           `json:"added_count"`
+	ToolsOnlyInA        []string      `json:"tools_only_in_a"`
+	ToolsOnlyInB        []string      `json:"tools_only_in_b"`
+	CapabilitiesOnlyInA []string      `json:"capabilities_only_in_a"`
+	CapabilitiesOnlyInB []string      `json:"capabilities_only_in_b"`
+	Steps               []runDiffStep `json:"steps"`
+}
+
+func newRunsDiffCmd() *cobra.Command {
+	var (
+		onlyChanges     bool
+		timingThreshold time.Duration
+	)
+	cmd := &cobra.Command{
+		Use:   "diff <run-a> <run-b>",
+		Short: "Compare two runs event by event",
+		Long: `Compare two runs, for example before and after a prompt or model change.
+
+Both timelines are aligned by event type and tool. Each aligned step is
+compared for tool call method, URL and HTTP status, capabilities, approval
+and consent outcomes, message content, errors, and timing. Use -o json for
+regression tooling.
+
+Exit codes:
+  0  the runs took the same steps with the same outcomes
+  1  the comparison could not be completed
+  2  the runs diverge`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newAPIClient(cmd.Context())
+			if err != nil {
+				return err
+			}
+			var runs [2]cliRun
+			var events [2][]cliRunEvent
+			for i, id := range args {
+				run, err := fetchRun(c, id)
+				if err != nil {
+					return err
+				}
+				runEvents, err := fetchRunEvents(c, id, 0)
+				if err != nil {
+					return err
+				}
+				runs[i], events[i] = *run, runEvents
+			}
+
+			result := diffRuns(runs[0], events[0], runs[1], events[1])
+			if isJSONOutput() {
+				if err := printJSONValue(result); err != nil {
+					return err
+				}
+			} else {
+				printRunDiff(result, onlyChanges, timingThreshold)
+			}
+			if result.Divergent {
+				return &exitCodeError{
+					code: exitCodeDrift,
+					err:  fmt.Errorf("runs diverge: %d changed, %d only in %s, %d only in %s", result.ChangedCount, result.RemovedCount, runs[0].ID, result.AddedCount, runs[1].ID),
+				}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&onlyChanges, "only-changes", false, "Hide steps that are the same in both runs")
+	cmd.Flags().DurationVar(&timingThreshold, "timing-threshold", time.Second, "Show timing deltas at or above this duration")
+	return cmd
+}
+
+// diffRuns aligns the timelines of runs a and b and compares each step.
+func diffRuns(a cliRun, aEvents []cliRunEvent, b cliRun, bEvents []cliRunEvent) runDiffResult {
+	aEvents, bEvents = sortedRunEvents(aEvents), sortedRunEvents(bEvents)
+	aTimeline, bTimeline := buildRunTimeline(a, aEvents), buildRunTimeline(b, bEvents)
+	aStart, aEnd := runTraceBounds(a, aEvents)
+	bStart, bEnd := runTraceBounds(b, bEvents)
+
+	result := runDiffResult{
+		A:     newRunDiffSide(a, aEvents, aStart, aEnd),
+		B:     newRunDiffSide(b, bEvents, bStart, bEnd),
+		Steps: []runDiffStep{},
+	}
+	result.ToolsOnlyInA, result.ToolsOnlyInB = stringSetDifference(result.A.Tools, result.B.Tools)
+	result.CapabilitiesOnlyInA, result.CapabilitiesOnlyInB = stringSetDifference(result.A.Capabilities, result.B.Capabilities)
+
+	pairs := alignSequences(len(aTimeline), len(bTimeline), func(i, j int) bool {
+		return runDiffKey(aTimeline[i]) == runDiffKey(bTimeline[j])
+	})
+	for _, pair := range pairs {
+		step := runDiffStep{}
+		if pair.A >= 0 {
+			entry := aTimeline[pair.A]
+			step.A = &entry
+			step.Key = runDiffKey(entry)
+			step.OffsetASeconds = runDiffOffset(entry, aStart)
+		}
+		if pair.B >= 0 {
+			entry := bTimeline[pair.B]
+			step.B = &entry
+			step.Key = runDiffKey(entry)
+			step.OffsetBSeconds = runDiffOffset(entry, bStart)
+		}
+		switch {
+		case step.A == nil:
+			step.Change = "added"
+			result.AddedCount++
+		case step.B == nil:
+			step.Change = "removed"
+			result.RemovedCount++
+		default:
+			step.Differences = compareRunDiffFields(runDiffFields(*step.A), runDiffFields(*step.B))
+			step.Change = "same"
+			if len(step.Differences) > 0 {
+				step.Change = "changed"
+				result.ChangedCount++
+			}
+			if step.OffsetASeconds != nil && step.OffsetBSeconds != nil {
+				delta := *step.OffsetBSeconds - *step.OffsetASeconds
+				step.TimingDeltaSeconds = &delta
+			}
+		}
+		result.Steps = append(result.Steps, step)
+	}
+	result.Divergent = result.ChangedCount+result.AddedCount+result.RemovedCount > 0 ||
+		!strings.EqualFold(a.Status, b.Status)
+	return result
+}
+
+func newRunDiffSide(run cliRun, events []cliRunEvent, start, end time.Time) runDiffSide {
+	side := runDiffSide{Run: run, Events: len(events), Tools: []string{}, Capabilities: []string{}}
+	if !start.IsZero() {
+		side.DurationSeconds = end.Sub(start).Seconds()
+	}
+	tools, capabilities := map[string]bool{}, map[string]bool{}
+	for _, event := range events {
+		if tool := firstNonEmptyRunValue(dataString(event.Data, "tool_id"), dataString(event.Data, "tool")); tool != "" {
+			tools[tool] = true
+		}
+		if capability := dataString(event.Data, "capability"); capability != "" {
+			capabilities[capability] = true
+		}
+	}
+	side.Tools = append(side.Tools, sortedKeysOfSet(tools)...)
+	side.Capabilities = append(side.Capabilities, sortedKeysOfSet(capabilities)...)
+	return side
+}
+
+func sortedKeysOfSet(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// stringSetDifference returns the values only in a and only in b.
+func stringSetDifference(a, b []string) ([]string, []string) {
+	inA, inB := map[string]bool{}, map[string]bool{}
+	for _, value := range a {
+		inA[value] = true
+	}
+	for _, value := range b {
+		inB[value] = true
+	}
+	onlyA, onlyB := []string{}, []string{}
+	for _, value := range a {
+		if !inB[value] {
+			onlyA = append(onlyA, value)
+		}
+	}
+	for _, value := range b {
+		if !inA[value] {
+			onlyB = append(onlyB, value)
+		}
+	}
+	return onlyA, onlyB
+}
+
+// runDiffKey is the identity used to align timeline entries: the event type
+// and tool. Events that resolve the same step with different outcomes share a
+// type here, so an approval that was rejected in one run lines up with the
+// approval granted in the other.
+func runDiffKey(entry cliRunTimelineEntry) string {
+	eventType := strings.ToUpper(entry.Type)
+	switch eventType {
+	case "APPROVED", "REJECTED":
+		eventType = "APPROVAL_DECISION"
+	case "INVOKE_COMPLETED", "INVOKE_FAILED":
+		eventType = "INVOKE_RESULT"
+	case "COMPLETED", "FAILED":
+		eventType = "RUN_RESULT"
+	case "TOOL_CALLED":
+		eventType = "TOOL_REQUEST"
+	}
+	if tool := firstNonEmptyRunValue(dataString(entry.Data, "tool_id"), dataString(entry.Data, "tool")); tool != "" {
+		return eventType + " " + tool
+	}
+	return eventType
+}
+
+// runDiffFields returns the values compared for an aligned step.
+func runDiffFields(entry cliRunTimelineEntry) map[string]string {
+	fields := map[string]string{}
+	set := func(name, value string) {
+		if value = strings.TrimSpace(value); value != "" {
+			fields[name] = value
+		}
+	}
+	eventType := strings.ToUpper(entry.Type)
+	switch eventType {
+	case "TOOL_REQUEST", "TOOL_CALLED", "TOOL_RESPONSE":
+		set("tool_method", strings.ToUpper(dataString(entry.Data, "tool_method")))
+		set("tool_url", dataString(entry.Data, "tool_url"))
+		set("status_code", dataString(entry.Data, "status_code"))
+		set("error", dataString(entry.Data, "error"))
+	case "APPROVED", "REJECTED":
+		set("outcome", eventType)
+	case "USER_MESSAGE", "AGENT_MESSAGE":
+		set("content", firstNonEmptyRunValue(dataString(entry.Data, "content"), dataString(entry.Data, "message")))
+	case "INVOKE_COMPLETED", "INVOKE_FAILED", "COMPLETED", "FAILED":
+		set("outcome", eventType)
+		set("error", dataString(entry.Data, "error"))
+	}
+	set("capability", dataString(entry.Data, "capability"))
+	return fields
+}
+
+func compareRunDiffFields(a, b map[string]string) []runDiffField {
+	names := map[string]bool{}
+	for name := range a {
+		names[name] = true
+	}
+	for name := range b {
+		names[name] = true
+	}
+	var differences []runDiffField
+	for _, name := range sortedKeysOfSet(names) {
+		if a[name] != b[name] {
+			differences = append(differences, runDiffField{Field: name, A: a[name], B: b[name]})
+		}
+	}
+	return differences
+}
+
+func runDiffOffset(entry cliRunTimelineEntry, start time.Time) *float64 {
+	if entry.Timestamp.IsZero() || start.IsZero() {
+		return nil
+	}
+	offset := entry.Timestamp.Sub(start).Seconds()
+	return &offset
+}
+
+func printRunDiff(result runDiffResult, onlyChanges bool, timingThreshold time.Duration) {
+	for _, side := range []struct {
+		label string
+		side  runDiffSide
+	}{{"A", result.A}, {"B", result.B}} {
+		fmt.Printf("Run %s: %s (agent %s, %s, %d events, %s)\n", side.label, side.side.Run.ID, emptyFallback(side.side.Run.AgentID, "-"),
+			side.side.Run.Status, side.side.Events, formatRunDuration(time.Duration(side.side.DurationSeconds*float64(time.Second))))
+	}
+	if len(result.ToolsOnlyInA) > 0 || len(result.ToolsOnlyInB) > 0 {
+		fmt.Printf("Tools only in A: %s; only in B: %s\n", emptyFallback(strings.Join(result.ToolsOnlyInA, ", "), "-"), emptyFallback(strings.Join(result.ToolsOnlyInB, ", "), "-"))
+	}
+	if len(result.CapabilitiesOnlyInA) > 0 || len(result.CapabilitiesOnlyInB) > 0 {
+		fmt.Printf("Capabilities only in A: %s; only in B: %s\n", emptyFallback(strings.Join(result.CapabilitiesOnlyInA, ", "), "-"), emptyFallback(strings.Join(result.CapabilitiesOnlyInB, ", "), "-"))
+	}
+	fmt.Println()
+
+	markers := map[string]string{"same": " ", "changed": "~", "removed": "-", "added": "+"}
+	for _, step := range result.Steps {
+		timing := formatRunDiffTiming(step, timingThreshold)
+		if onlyChanges && step.Change == "same" && timing == "" {
+			continue
+		}
+		summary := ""
+		switch {
+		case step.A != nil:
+			summary = step.A.Summary
+		case step.B != nil:
+			summary = step.B.Summary
+		}
+		line := fmt.Sprintf("%s %-32s %s", markers[step.Change], step.Key, truncateRunMessage(summary, 80))
+		if timing != "" {
+			line += "  " + timing
+		}
+		fmt.Println(strings.TrimRight(line, " "))
+		for _, difference := range step.Differences {
+			fmt.Printf("    %s: %s -> %s\n", difference.Field, emptyFallback(truncateRunMessage(difference.A, 60), "(none)"), emptyFallback(truncateRunMessage(difference.B, 60), "(none)"))
+		}
+	}
+
+	fmt.Println()
+	if result.Divergent {
+		fmt.Printf("Runs diverge: %d changed, %d only in A, %d only in B.\n", result.ChangedCount, result.RemovedCount, result.AddedCount)
+	} else {
+		fmt.Println("Runs took the same steps with the same outcomes.")
+	}
+}
+
+// formatRunDiffTiming describes how much later (or earlier) run B reached a
+// step than run A, when the difference is at least threshold.
+func formatRunDiffTiming(step runDiffStep, threshold time.Duration) string {
+	if step.TimingDeltaSeconds == nil {
+		return ""
+	}
+	delta := time.Duration(*step.TimingDeltaSeconds * float64(time.Second))
+	magnitude := delta
+	if magnitude < 0 {
+		magnitude = -magnitude
+	}
+	if magnitude == 0 || magnitude < threshold {
+		return ""
+	}
+	if delta < 0 {
+		return fmt.Sprintf("(B %s earlier)", formatRunDuration(magnitude))
+	}
+	return fmt.Sprintf("(B %s later)", formatRunDuration(magnitude))
+}
@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDiffRunsAlignsStepsAndReportsDivergence(t *testing.T) {
+	start := time.Date(2026, 4, 9, 10, 0, 0, 0, time.UTC)
+	runA := cliRun{ID: "run-a", AgentID: "billing", Status: "COMPLETED", CreatedAt: start}
+	runB := cliRun{ID: "run-b", AgentID: "billing", Status: "FAILED", CreatedAt: start}
+	eventsA := []cliRunEvent{
+		{Seq: 1, Type: "USER_MESSAGE", Timestamp: start, Data: map[string]any{"content": "refund order 42"}},
+		{Seq: 2, Type: "TOOL_REQUEST", Timestamp: start.Add(time.Second), Data: map[string]any{"tool_id": "orders", "tool_method": "GET", "tool_url": "https://orders/42"}},
+		{Seq: 3, Type: "APPROVAL_REQUIRED", Timestamp: start.Add(2 * time.Second), Data: map[string]any{"tool_id": "stripe", "capability": "refund"}},
+		{Seq: 4, Type: "APPROVED", Timestamp: start.Add(3 * time.Second)},
+		{Seq: 5, Type: "COMPLETED", Timestamp: start.Add(4 * time.Second)},
+	}
+	eventsB := []cliRunEvent{
+		{Seq: 1, Type: "USER_MESSAGE", Timestamp: start, Data: map[string]any{"content": "refund order 42"}},
+		{Seq: 2, Type: "TOOL_REQUEST", Timestamp: start.Add(5 * time.Second), Data: map[string]any{"tool_id": "orders", "tool_method": "POST", "tool_url": "https://orders/42"}},
+		{Seq: 3, Type: "APPROVAL_REQUIRED", Timestamp: start.Add(6 * time.Second), Data: map[string]any{"tool_id": "stripe", "capability": "refund"}},
+		{Seq: 4, Type: "REJECTED", Timestamp: start.Add(7 * time.Second)},
+		{Seq: 5, Type: "TOOL_REQUEST", Timestamp: start.Add(8 * time.Second), Data: map[string]any{"tool_id": "email", "capability": "send"}},
+		{Seq: 6, Type: "FAILED", Timestamp: start.Add(9 * time.Second), Data: map[string]any{"error": "refund rejected"}},
+	}
+
+	result := diffRuns(runA, eventsA, runB, eventsB)
+	if !result.Divergent {
+		t.Fatal("expected runs to diverge")
+	}
+	if len(result.Steps) != 6 {
+		t.Fatalf("expected 6 aligned steps, got %+v", result.Steps)
+	}
+	wantChanges := []string{"same", "changed", "same", "changed", "added", "changed"}
+	for i, want := range wantChanges {
+		if result.Steps[i].Change != want {
+			t.Fatalf("step %d (%s): expected %s, got %s", i, result.Steps[i].Key, want, result.Steps[i].Change)
+		}
+	}
+	toolStep := result.Steps[1]
+	if len(toolStep.Differences) != 1 || toolStep.Differences[0].Field != "tool_method" || toolStep.Differences[0].A != "GET" || toolStep.Differences[0].B != "POST" {
+		t.Fatalf("unexpected tool call differences: %+v", toolStep.Differences)
+	}
+	if toolStep.TimingDeltaSeconds == nil || *toolStep.TimingDeltaSeconds != 4 {
+		t.Fatalf("expected a 4s timing delta, got %v", toolStep.TimingDeltaSeconds)
+	}
+	if result.Steps[3].Key != "APPROVAL_DECISION" || result.Steps[3].Differences[0].B != "REJECTED" {
+		t.Fatalf("expected approval outcomes to be compared, got %+v", result.Steps[3])
+	}
+	if len(result.ToolsOnlyInB) != 1 || result.ToolsOnlyInB[0] != "email" || len(result.CapabilitiesOnlyInB) != 1 || result.CapabilitiesOnlyInB[0] != "send" {
+		t.Fatalf("unexpected tool/capability differences: %+v", result)
+	}
+}
+
+func TestDiffRunsIdenticalRunsDoNotDiverge(t *testing.T) {
+	start := time.Date(2026, 4, 9, 10, 0, 0, 0, time.UTC)
+	events := []cliRunEvent{
+		{Seq: 1, Type: "RUN_CREATED", Timestamp: start},
+		{Seq: 2, Type: "COMPLETED", Timestamp: start.Add(time.Second)},
+	}
+	result := diffRuns(cliRun{ID: "a", Status: "COMPLETED"}, events, cliRun{ID: "b", Status: "COMPLETED"}, events)
+	if result.Divergent || result.ChangedCount+result.AddedCount+result.RemovedCount != 0 {
+		t.Fatalf("expected identical runs, got %+v", result)
+	}
+}
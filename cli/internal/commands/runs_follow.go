@@ -0,0 +1,168 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/runagents/runagents/cli/internal/client"
+)
+
+type runFollowAPIClient interface {
+	runAPIClient
+	StreamCtx(context.Context, string, url.Values, func(client.StreamEvent) error) error
+}
+
+// errRunStreamFinished stops a run event stream once the run has finished,
+// since the server may keep the stream open.
+var errRunStreamFinished = errors.New("run finished")
+
+// followRunEvents calls emit for every event with a sequence number above
+// afterSeq, in order, until the run reaches a terminal status. Events are
+// streamed when the API serves /runs/{id}/events as text/event-stream, until
+// a terminal event or status arrives; otherwise, or when the stream ends
+// early, new events are polled with after_seq every interval. It returns the
+// run in its terminal state.
+func followRunEvents(ctx context.Context, c runFollowAPIClient, runID string, afterSeq int, interval time.Duration, emit func(cliRunEvent) error) (*cliRun, error) {
+	lastSeq := afterSeq
+	deliver := func(event cliRunEvent) error {
+		if event.Seq <= lastSeq {
+			return nil
+		}
+		lastSeq = event.Seq
+		return emit(event)
+	}
+
+	eventsPath := fmt.Sprintf("/runs/%s/events", runID)
+	streamQuery := url.Values{"after_seq": {strconv.Itoa(lastSeq)}}
+	err := c.StreamCtx(ctx, eventsPath, streamQuery, func(message client.StreamEvent) error {
+		var event cliRunEvent
+		if err := json.Unmarshal(message.Data, &event); err != nil {
+			return nil
+		}
+		if event.Seq == 0 {
+			// Heartbeats and status messages carry no sequence number.
+			var status struct {
+				Status string `json:"status"`
+			}
+			if json.Unmarshal(message.Data, &status) == nil && isTerminalRunStatus(status.Status) {
+				return errRunStreamFinished
+			}
+			return nil
+		}
+		if err := deliver(event); err != nil {
+			return err
+		}
+		if isTerminalRunStatus(event.Type) {
+			return errRunStreamFinished
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, client.ErrStreamingUnsupported) && !errors.Is(err, errRunStreamFinished) {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+
+	var finalRun *cliRun
+	err = waitForCondition(ctx, interval, func(ctx context.Context) (bool, error) {
+		// Read the status before the events so that events recorded just
+		// before the run finished are still delivered.
+		run, err := fetchRun(c, runID)
+		if err != nil {
+			return false, err
+		}
+		events, err := fetchRunEventsAfter(c, runID, lastSeq)
+		if err != nil {
+			return false, err
+		}
+		for _, event := range events {
+			if err := deliver(event); err != nil {
+				return false, err
+			}
+		}
+		finalRun = run
+		return isTerminalRunStatus(run.Status), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return finalRun, nil
+}
+
+// followRunCommand prints the recorded events that match filters (the last
+// filters.Limit of them), then follows the run until it is terminal. With
+// timeline set, JSON output uses timeline entries instead of raw events.
+func followRunCommand(ctx context.Context, c runFollowAPIClient, runID string, recorded []cliRunEvent, filters runEventFilters, interval time.Duration, timeline bool) error {
+	for _, event := range filterRunEvents(recorded, filters) {
+		if err := printFollowedRunEvent(event, timeline); err != nil {
+			return err
+		}
+	}
+	run, err := followRunEvents(ctx, c, runID, lastRunEventSeq(recorded), interval, func(event cliRunEvent) error {
+		if filters.Type != "" && !strings.EqualFold(event.Type, filters.Type) {
+			return nil
+		}
+		return printFollowedRunEvent(event, timeline)
+	})
+	if err != nil {
+		return err
+	}
+	printFollowedRunEnd(run)
+	return nil
+}
+
+// fetchRunEventsAfter returns the events recorded after afterSeq, ordered by seq.
+func fetchRunEventsAfter(c runAPIClient, runID string, afterSeq int) ([]cliRunEvent, error) {
+	events, err := getRunEvents(c, runID, url.Values{"after_seq": {strconv.Itoa(afterSeq)}})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Seq < events[j].Seq
+	})
+	return events, nil
+}
+
+// lastRunEventSeq returns the highest sequence number in events.
+func lastRunEventSeq(events []cliRunEvent) int {
+	last := 0
+	for _, event := range events {
+		if event.Seq > last {
+			last = event.Seq
+		}
+	}
+	return last
+}
+
+// printFollowedRunEvent prints one event per line while following a run.
+// JSON output is newline-delimited so it can be piped as events arrive.
+func printFollowedRunEvent(event cliRunEvent, timeline bool) error {
+	if isJSONOutput() && timeline {
+		return printJSONValue(runTimelineEntry(event))
+	}
+	if isJSONOutput() {
+		return printJSONValue(event)
+	}
+	actor := ""
+	if event.Actor != "" {
+		actor = " [" + event.Actor + "]"
+	}
+	fmt.Printf("%s  #%-4d %-18s%s %s\n", formatRunTime(event.Timestamp), event.Seq, event.Type, actor, summarizeRunEvent(event))
+	return nil
+}
+
+// printFollowedRunEnd reports the terminal status after following a run.
+func printFollowedRunEnd(run *cliRun) {
+	if isJSONOutput() || run == nil {
+		return
+	}
+	fmt.Printf("\nRun %s reached terminal status %s.\n", run.ID, run.Status)
+}
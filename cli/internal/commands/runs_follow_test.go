@@ -0,0 +1,135 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/runagents/runagents/cli/internal/client"
+)
+
+// fakeFollowClient reveals one batch of events, and the matching run status,
+// each time the run is fetched.
+type fakeFollowClient struct {
+	fakeRunsClient
+	streamed []string
+	// holdOpen keeps the stream open after the last message, as a server
+	// that does not close it would, until the callback stops it.
+	holdOpen bool
+	batches  [][]cliRunEvent
+	statuses []string
+	visible  []cliRunEvent
+	polls    int
+}
+
+func (f *fakeFollowClient) Get(path string) ([]byte, error) {
+	if f.polls < len(f.batches) {
+		f.visible = append(f.visible, f.batches[f.polls]...)
+	}
+	status := f.statuses[len(f.statuses)-1]
+	if f.polls < len(f.statuses) {
+		status = f.statuses[f.polls]
+	}
+	f.polls++
+	return json.Marshal(cliRun{ID: "run-1", Status: status})
+}
+
+func (f *fakeFollowClient) GetWithQuery(path string, query url.Values) ([]byte, error) {
+	afterSeq, _ := strconv.Atoi(query.Get("after_seq"))
+	events := []cliRunEvent{}
+	for _, event := range f.visible {
+		if event.Seq > afterSeq {
+			events = append(events, event)
+		}
+	}
+	return json.Marshal(events)
+}
+
+func (f *fakeFollowClient) StreamCtx(ctx context.Context, path string, query url.Values, fn func(client.StreamEvent) error) error {
+	if f.streamed == nil {
+		return client.ErrStreamingUnsupported
+	}
+	for _, data := range f.streamed {
+		if err := fn(client.StreamEvent{Data: []byte(data)}); err != nil {
+			return err
+		}
+	}
+	if f.holdOpen {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return nil
+}
+
+func TestFollowRunEventsPollsUntilTerminal(t *testing.T) {
+	fake := &fakeFollowClient{
+		batches: [][]cliRunEvent{
+			{{Seq: 1, Type: "RUN_CREATED"}, {Seq: 2, Type: "TOOL_REQUEST"}},
+			{{Seq: 3, Type: "COMPLETED"}},
+		},
+		statuses: []string{"RUNNING", "COMPLETED"},
+	}
+	var seen []int
+	run, err := followRunEvents(context.Background(), fake, "run-1", 1, time.Millisecond, func(event cliRunEvent) error {
+		seen = append(seen, event.Seq)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("followRunEvents: %v", err)
+	}
+	if fmt.Sprint(seen) != "[2 3]" {
+		t.Fatalf("expected events after seq 1 exactly once, got %v", seen)
+	}
+	if run.Status != "COMPLETED" {
+		t.Fatalf("expected terminal run, got %q", run.Status)
+	}
+}
+
+func TestFollowRunEventsPrefersStreamAndSkipsDuplicates(t *testing.T) {
+	fake := &fakeFollowClient{
+		streamed: []string{`{"seq":2,"type":"TOOL_REQUEST"}`, `{"type":"heartbeat"}`, `{"seq":3,"type":"FAILED"}`},
+		batches:  [][]cliRunEvent{{{Seq: 2, Type: "TOOL_REQUEST"}, {Seq: 3, Type: "FAILED"}}},
+		statuses: []string{"FAILED"},
+	}
+	var seen []int
+	run, err := followRunEvents(context.Background(), fake, "run-1", 1, time.Millisecond, func(event cliRunEvent) error {
+		seen = append(seen, event.Seq)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("followRunEvents: %v", err)
+	}
+	if fmt.Sprint(seen) != "[2 3]" || run.Status != "FAILED" {
+		t.Fatalf("expected streamed events once and a failed run, got %v %q", seen, run.Status)
+	}
+	if fake.polls != 1 {
+		t.Fatalf("expected a single status check after the stream ended, got %d", fake.polls)
+	}
+}
+
+func TestFollowRunEventsStopsStreamingAtTerminalEvent(t *testing.T) {
+	for _, streamed := range [][]string{
+		{`{"seq":2,"type":"TOOL_REQUEST"}`, `{"seq":3,"type":"COMPLETED"}`},
+		{`{"seq":2,"type":"TOOL_REQUEST"}`, `{"status":"COMPLETED"}`},
+	} {
+		fake := &fakeFollowClient{
+			streamed: streamed,
+			holdOpen: true,
+			batches:  [][]cliRunEvent{{{Seq: 2, Type: "TOOL_REQUEST"}, {Seq: 3, Type: "COMPLETED"}}},
+			statuses: []string{"COMPLETED"},
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		run, err := followRunEvents(ctx, fake, "run-1", 1, time.Millisecond, func(cliRunEvent) error { return nil })
+		cancel()
+		if err != nil {
+			t.Fatalf("followRunEvents: %v", err)
+		}
+		if run.Status != "COMPLETED" {
+			t.Fatalf("expected a completed run, got %q", run.Status)
+		}
+	}
+}
@@ -0,0 +1,598 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Span kinds from the OTLP trace protocol.
+const (
+	otlpSpanKindInternal = 1
+	otlpSpanKindServer   = 2
+	otlpSpanKindClient   = 3
+)
+
+// Span status codes from the OTLP trace protocol.
+const (
+	otlpStatusOK    = 1
+	otlpStatusError = 2
+)
+
+// otlpTraceExport is an ExportTraceServiceRequest in the OTLP/JSON encoding,
+// as accepted by collectors on /v1/traces.
+type otlpTraceExport struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue  `json:"attributes,omitempty"`
+	Events            []otlpSpanEvent `json:"events,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpSpanEvent struct {
+	TimeUnixNano string         `json:"timeUnixNano"`
+	Name         string         `json:"name"`
+	Attributes   []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+// otlpAnyValue holds one attribute value. OTLP/JSON encodes 64-bit integers
+// as strings.
+type otlpAnyValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	IntValue    *string `json:"intValue,omitempty"`
+	BoolValue   *bool   `json:"boolValue,omitempty"`
+}
+
+// otlpPushOptions configures pushing traces to an OTLP/HTTP collector.
+type otlpPushOptions struct {
+	Endpoint string
+	Headers  map[string]string
+}
+
+func addOTLPPushFlags(cmd *cobra.Command, opts *otlpPushOptions) {
+	cmd.Flags().StringVar(&opts.Endpoint, "otlp-endpoint", "", "Push traces to this OTLP/HTTP collector (for example http://localhost:4318) instead of printing them")
+	cmd.Flags().StringToStringVar(&opts.Headers, "otlp-header", nil, "Header to send with pushed traces, as key=value (repeatable)")
+}
+
+func newRunsExportTracesCmd() *cobra.Command {
+	var (
+		filters   runFilters
+		since     string
+		until     string
+		workers   int
+		batchSize int
+		push      otlpPushOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export-traces",
+		Short: "Export the runs in a time window as OpenTelemetry traces",
+		Long: `Export the runs created in a time window as OTLP/JSON traces, one trace per run.
+
+Without --otlp-endpoint the ExportTraceServiceRequest is printed, ready for
+any tool that reads OTLP/JSON. With --otlp-endpoint the traces are sent to
+the collector's /v1/traces endpoint in batches of --batch-size runs.
+
+Examples:
+  runagents runs export-traces --since 24h > traces.json
+  runagents runs export-traces --since 24h --otlp-endpoint http://localhost:4318
+  runagents runs export-traces --agent billing-agent --otlp-endpoint https://otlp.example.com --otlp-header authorization="Bearer $TOKEN"`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			now := time.Now().UTC()
+			var err error
+			if filters.Since, err = parseRunTimeBound(since, now); err != nil {
+				return fmt.Errorf("invalid --since: %w", err)
+			}
+			if filters.Until, err = parseRunTimeBound(until, now); err != nil {
+				return fmt.Errorf("invalid --until: %w", err)
+			}
+			if batchSize < 1 {
+				return fmt.Errorf("--batch-size must be at least 1")
+			}
+
+			c, err := newAPIClient(cmd.Context())
+			if err != nil {
+				return err
+			}
+			runs, _, err := fetchRuns(c, filters)
+			if err != nil {
+				return err
+			}
+			ids := make([]string, 0, len(runs))
+			for _, run := range runs {
+				ids = append(ids, run.ID)
+			}
+			events, err := fetchRunEventsForRuns(cmd.Context(), c, ids, workers)
+			if err != nil {
+				return err
+			}
+
+			if push.Endpoint == "" {
+				export := otlpTraceExport{ResourceSpans: []otlpResourceSpans{}}
+				for _, run := range runs {
+					export.ResourceSpans = append(export.ResourceSpans, buildRunResourceSpans(run, events[run.ID]))
+				}
+				return printOTLPExport(export)
+			}
+
+			spans := 0
+			for start := 0; start < len(runs); start += batchSize {
+				end := start + batchSize
+				if end > len(runs) {
+					end = len(runs)
+				}
+				export := otlpTraceExport{}
+				for _, run := range runs[start:end] {
+					resource := buildRunResourceSpans(run, events[run.ID])
+					spans += len(resource.ScopeSpans[0].Spans)
+					export.ResourceSpans = append(export.ResourceSpans, resource)
+				}
+				if err := pushOTLPTraces(cmd.Context(), push, export); err != nil {
+					return err
+				}
+			}
+			fmt.Printf("Pushed %d traces (%d spans) to %s.\n", len(runs), spans, otlpTracesURL(push.Endpoint))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "24h", "Start of the window: RFC 3339 time, date, or duration ago (for example 24h or 7d)")
+	cmd.Flags().StringVar(&until, "until", "", "End of the window, in the same formats as --since (default now)")
+	cmd.Flags().StringVar(&filters.AgentID, "agent", "", "Only export runs of this agent")
+	cmd.Flags().StringVar(&filters.UserID, "user", "", "Only export runs for this user ID")
+	cmd.Flags().StringVar(&filters.Status, "status", "", "Only export runs with this status")
+	cmd.Flags().IntVar(&workers, "concurrency", 8, "Maximum number of run event requests in flight")
+	cmd.Flags().IntVar(&batchSize, "batch-size", 100, "Runs per request when pushing to --otlp-endpoint")
+	addOTLPPushFlags(cmd, &push)
+	return cmd
+}
+
+// exportRunOTLP prints or pushes one run as an OTLP trace.
+func exportRunOTLP(ctx context.Context, run cliRun, events []cliRunEvent, push otlpPushOptions) error {
+	resource := buildRunResourceSpans(run, events)
+	export := otlpTraceExport{ResourceSpans: []otlpResourceSpans{resource}}
+	if push.Endpoint == "" {
+		return printOTLPExport(export)
+	}
+	if err := pushOTLPTraces(ctx, push, export); err != nil {
+		return err
+	}
+	fmt.Printf("Pushed trace %s for run %s (%d spans) to %s.\n", otlpTraceID(run.ID), run.ID, len(resource.ScopeSpans[0].Spans), otlpTracesURL(push.Endpoint))
+	return nil
+}
+
+func printOTLPExport(export otlpTraceExport) error {
+	if isJSONOutput() {
+		return printJSONValue(export)
+	}
+	return printIndentedJSONValue(export)
+}
+
+// buildRunResourceSpans maps a run to one trace. The run is the root server
+// span; tool calls are client spans, and approval waits, consent waits and
+// agent invocations are child spans, each ending at the event that resolves
+// it (or at the end of the run). Every event is recorded as a span event on
+// the span it opened or closed, or on the root span.
+func buildRunResourceSpans(run cliRun, events []cliRunEvent) otlpResourceSpans {
+	events = sortedRunEvents(events)
+	traceID := otlpTraceID(run.ID)
+	start, end := runTraceBounds(run, events)
+
+	root := otlpSpan{
+		TraceID:           traceID,
+		SpanID:            otlpSpanID(run.ID, "run"),
+		Name:              "run " + emptyFallback(run.AgentID, run.ID),
+		Kind:              otlpSpanKindServer,
+		StartTimeUnixNano: otlpTime(start),
+		Attributes: otlpAttributes(
+			"runagents.run.id", run.ID,
+			"runagents.agent.id", run.AgentID,
+			"runagents.conversation.id", run.ConversationID,
+			"runagents.run.status", run.Status,
+			"enduser.id", run.UserID,
+		),
+	}
+	switch strings.ToUpper(run.Status) {
+	case "COMPLETED":
+		root.Status = otlpStatus{Code: otlpStatusOK}
+	case "FAILED":
+		root.Status = otlpStatus{Code: otlpStatusError, Message: "Run failed"}
+	}
+
+	var (
+		children []*otlpSpan
+		open     []openRunSpan
+		index    int
+	)
+	openSpan := func(kind string, event cliRunEvent, span otlpSpan) *otlpSpan {
+		span.TraceID = traceID
+		span.SpanID = otlpSpanID(run.ID, runEventSpanKey(event, index))
+		span.ParentSpanID = root.SpanID
+		span.StartTimeUnixNano = otlpTime(event.Timestamp)
+		span.Attributes = append(otlpAttributes("runagents.span_kind", kind), span.Attributes...)
+		children = append(children, &span)
+		open = append(open, openRunSpan{kind: kind, key: runTraceEventKey(event), span: &span})
+		return &span
+	}
+	closeSpan := func(event cliRunEvent, kinds ...string) *otlpSpan {
+		key := runTraceEventKey(event)
+		match := -1
+		for i, candidate := range open {
+			if !slices.Contains(kinds, candidate.kind) {
+				continue
+			}
+			if key == "" || candidate.key == key {
+				match = i
+				break
+			}
+			if match < 0 {
+				match = i
+			}
+		}
+		if match < 0 {
+			return nil
+		}
+		span := open[match].span
+		open = append(open[:match], open[match+1:]...)
+		span.EndTimeUnixNano = otlpTime(event.Timestamp)
+		return span
+	}
+
+	for i, event := range events {
+		index = i
+		var target *otlpSpan
+		switch strings.ToUpper(event.Type) {
+		case "TOOL_REQUEST":
+			target = openSpan("tool_call", event, runToolSpan(event))
+		case "TOOL_CALLED":
+			// A call that was requested earlier (and possibly approved) is
+			// recorded on the request's span; otherwise it starts its own.
+			target = findOpenRunSpan(open, "tool_call", runTraceEventKey(event))
+			if target == nil {
+				target = openSpan("tool_call", event, runToolSpan(event))
+			}
+		case "TOOL_RESPONSE":
+			if target = closeSpan(event, "tool_call"); target != nil {
+				applyToolResponse(target, event)
+			}
+		case "APPROVAL_REQUIRED":
+			target = openSpan("approval", event, otlpSpan{
+				Name:       "approval " + runTraceToolName(event),
+				Kind:       otlpSpanKindInternal,
+				Attributes: runGovernedSpanAttributes(event),
+			})
+		case "APPROVED", "REJECTED":
+			kinds := []string{"approval"}
+			if strings.EqualFold(event.Type, "REJECTED") {
+				kinds = append(kinds, "consent")
+			}
+			if target = closeSpan(event, kinds...); target != nil {
+				decision := strings.ToUpper(event.Type)
+				target.Attributes = append(target.Attributes, otlpAttributes(
+					"runagents.decision", decision,
+					"runagents.decision.actor", firstNonEmptyRunValue(dataString(event.Data, "approver_id"), event.Actor),
+				)...)
+				if decision == "REJECTED" {
+					target.Status = otlpStatus{Code: otlpStatusError, Message: summarizeRunEvent(event)}
+				}
+			}
+		case "CONSENT_REQUIRED":
+			target = openSpan("consent", event, otlpSpan{
+				Name:       "consent " + runTraceToolName(event),
+				Kind:       otlpSpanKindInternal,
+				Attributes: runGovernedSpanAttributes(event),
+			})
+		case "RESUMED":
+			target = closeSpan(event, "consent")
+		case "INVOKE_REQUESTED":
+			target = openSpan("invoke", event, otlpSpan{
+				Name: "invoke " + emptyFallback(run.AgentID, "agent"),
+				Kind: otlpSpanKindClient,
+				Attributes: otlpAttributes(
+					"url.full", run.InvokeURL,
+				),
+			})
+		case "INVOKE_COMPLETED", "INVOKE_FAILED":
+			if target = closeSpan(event, "invoke"); target != nil {
+				if strings.EqualFold(event.Type, "INVOKE_FAILED") {
+					target.Status = otlpStatus{Code: otlpStatusError, Message: summarizeRunEvent(event)}
+				} else {
+					target.Status = otlpStatus{Code: otlpStatusOK}
+				}
+			}
+		case "FAILED":
+			root.Status = otlpStatus{Code: otlpStatusError, Message: summarizeRunEvent(event)}
+		}
+		if target == nil {
+			target = &root
+		}
+		target.Events = append(target.Events, runTraceSpanEvent(event))
+	}
+
+	root.EndTimeUnixNano = otlpTime(end)
+	spans := []otlpSpan{root}
+	for _, span := range children {
+		if span.EndTimeUnixNano == "" {
+			span.EndTimeUnixNano = root.EndTimeUnixNano
+			span.Attributes = append(span.Attributes, otlpBoolAttribute("runagents.span.unfinished", true))
+		}
+		spans = append(spans, *span)
+	}
+
+	return otlpResourceSpans{
+		Resource: otlpResource{Attributes: otlpAttributes(
+			"service.name", emptyFallback(run.AgentID, "runagents-agent"),
+			"service.namespace", run.Namespace,
+		)},
+		ScopeSpans: []otlpScopeSpans{{
+			Scope: otlpScope{Name: "runagents-cli"},
+			Spans: spans,
+		}},
+	}
+}
+
+type openRunSpan struct {
+	kind string
+	key  string
+	span *otlpSpan
+}
+
+func findOpenRunSpan(open []openRunSpan, kind, key string) *otlpSpan {
+	for _, candidate := range open {
+		if candidate.kind == kind && (key == "" || candidate.key == key) {
+			return candidate.span
+		}
+	}
+	return nil
+}
+
+// runTraceEventKey identifies the action an event belongs to, so responses
+// and decisions close the span they answer.
+func runTraceEventKey(event cliRunEvent) string {
+	return firstNonEmptyRunValue(runEventActionID(event), dataString(event.Data, "tool_id"), dataString(event.Data, "tool"))
+}
+
+func runTraceToolName(event cliRunEvent) string {
+	return firstNonEmptyRunValue(dataString(event.Data, "tool_id"), dataString(event.Data, "tool"), "tool")
+}
+
+func runToolSpan(event cliRunEvent) otlpSpan {
+	method := strings.ToUpper(dataString(event.Data, "tool_method"))
+	toolURL := dataString(event.Data, "tool_url")
+	name := runTraceToolName(event)
+	if method != "" {
+		name = method + " " + name
+	}
+	attributes := otlpAttributes(
+		"runagents.tool.id", firstNonEmptyRunValue(dataString(event.Data, "tool_id"), dataString(event.Data, "tool")),
+		"runagents.capability", dataString(event.Data, "capability"),
+		"runagents.action.id", runEventActionID(event),
+		"tool_method", dataString(event.Data, "tool_method"),
+		"tool_url", toolURL,
+		"http.request.method", method,
+		"url.full", toolURL,
+	)
+	if parsed, err := url.Parse(toolURL); err == nil && parsed.Host != "" {
+		attributes = append(attributes, otlpAttributes("server.address", parsed.Hostname())...)
+	}
+	return otlpSpan{Name: name, Kind: otlpSpanKindClient, Attributes: attributes}
+}
+
+func applyToolResponse(span *otlpSpan, event cliRunEvent) {
+	statusCode, err := strconv.Atoi(dataString(event.Data, "status_code"))
+	if err != nil {
+		span.Status = otlpStatus{Code: otlpStatusOK}
+		return
+	}
+	span.Attributes = append(span.Attributes, otlpIntAttribute("http.response.status_code", int64(statusCode)))
+	if statusCode >= 400 {
+		span.Status = otlpStatus{Code: otlpStatusError, Message: fmt.Sprintf("HTTP %d", statusCode)}
+	} else {
+		span.Status = otlpStatus{Code: otlpStatusOK}
+	}
+}
+
+func runGovernedSpanAttributes(event cliRunEvent) []otlpKeyValue {
+	return otlpAttributes(
+		"runagents.tool.id", firstNonEmptyRunValue(dataString(event.Data, "tool_id"), dataString(event.Data, "tool")),
+		"runagents.capability", dataString(event.Data, "capability"),
+		"runagents.action.id", runEventActionID(event),
+	)
+}
+
+func runTraceSpanEvent(event cliRunEvent) otlpSpanEvent {
+	attributes := []otlpKeyValue{otlpIntAttribute("runagents.event.seq", int64(event.Seq))}
+	attributes = append(attributes, otlpAttributes(
+		"runagents.event.id", event.EventID,
+		"runagents.event.actor", event.Actor,
+		"runagents.event.summary", summarizeRunEvent(event),
+		"runagents.event.payload_hash", event.PayloadHash,
+	)...)
+	keys := make([]string, 0, len(event.Data))
+	for key := range event.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := event.Data[key]
+		text, ok := value.(string)
+		if !ok {
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				continue
+			}
+			text = string(encoded)
+		}
+		attributes = append(attributes, otlpAttributes("runagents.data."+key, text)...)
+	}
+	return otlpSpanEvent{
+		TimeUnixNano: otlpTime(event.Timestamp),
+		Name:         event.Type,
+		Attributes:   attributes,
+	}
+}
+
+// runTraceBounds returns the span of time covered by the run and its events.
+func runTraceBounds(run cliRun, events []cliRunEvent) (time.Time, time.Time) {
+	start, end := runStartTime(run), run.UpdatedAt
+	for _, event := range events {
+		if event.Timestamp.IsZero() {
+			continue
+		}
+		if start.IsZero() || event.Timestamp.Before(start) {
+			start = event.Timestamp
+		}
+		if event.Timestamp.After(end) {
+			end = event.Timestamp
+		}
+	}
+	if end.Before(start) {
+		end = start
+	}
+	return start, end
+}
+
+// otlpTraceID derives a stable 16-byte trace ID from the run ID, so exporting
+// the same run twice yields the same trace.
+func otlpTraceID(runID string) string {
+	sum := sha256.Sum256([]byte("runagents/run/" + runID))
+	return hex.EncodeToString(sum[:16])
+}
+
+func otlpSpanID(runID, key string) string {
+	sum := sha256.Sum256([]byte("runagents/run/" + runID + "/" + key))
+	return hex.EncodeToString(sum[:8])
+}
+
+// runEventSpanKey names the span an event opens. Servers that do not number
+// their events send seq 0, so those fall back to the event ID and then to the
+// event's position in the run.
+func runEventSpanKey(event cliRunEvent, index int) string {
+	if event.Seq > 0 {
+		return strconv.Itoa(event.Seq)
+	}
+	if event.EventID != "" {
+		return "event/" + event.EventID
+	}
+	return "index/" + strconv.Itoa(index)
+}
+
+func otlpTime(ts time.Time) string {
+	if ts.IsZero() {
+		return "0"
+	}
+	return strconv.FormatInt(ts.UnixNano(), 10)
+}
+
+// otlpAttributes builds string attributes from key, value pairs, skipping
+// empty values.
+func otlpAttributes(pairs ...string) []otlpKeyValue {
+	attributes := make([]otlpKeyValue, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i+1] == "" {
+			continue
+		}
+		value := pairs[i+1]
+		attributes = append(attributes, otlpKeyValue{Key: pairs[i], Value: otlpAnyValue{StringValue: &value}})
+	}
+	return attributes
+}
+
+func otlpIntAttribute(key string, value int64) otlpKeyValue {
+	text := strconv.FormatInt(value, 10)
+	return otlpKeyValue{Key: key, Value: otlpAnyValue{IntValue: &text}}
+}
+
+func otlpBoolAttribute(key string, value bool) otlpKeyValue {
+	return otlpKeyValue{Key: key, Value: otlpAnyValue{BoolValue: &value}}
+}
+
+// otlpTracesURL appends the standard /v1/traces path to a collector base URL
+// that does not already name a path.
+func otlpTracesURL(endpoint string) string {
+	endpoint = strings.TrimRight(strings.TrimSpace(endpoint), "/")
+	if parsed, err := url.Parse(endpoint); err == nil && (parsed.Path == "" || parsed.Path == "/") {
+		return endpoint + "/v1/traces"
+	}
+	return endpoint
+}
+
+// pushOTLPTraces posts an export to an OTLP/HTTP collector using the JSON
+// encoding.
+func pushOTLPTraces(ctx context.Context, opts otlpPushOptions, export otlpTraceExport) error {
+	body, err := json.Marshal(export)
+	if err != nil {
+		return fmt.Errorf("failed to encode traces: %w", err)
+	}
+	target := otlpTracesURL(opts.Endpoint)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid --otlp-endpoint: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range opts.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := (&http.Client{Timeout: apiRequestTimeout()}).Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to push traces to %s: %w", target, err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("OTLP endpoint %s returned HTTP %d: %s", target, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
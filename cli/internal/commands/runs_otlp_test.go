@@ -0,0 +1,153 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBuildRunResourceSpansMapsToolCallsApprovalsAndConsent(t *testing.T) {
+	start := time.Date(2026, 4, 9, 10, 0, 0, 0, time.UTC)
+	run := cliRun{ID: "run-1", AgentID: "billing", UserID: "alice", Status: "COMPLETED", CreatedAt: start, UpdatedAt: start.Add(time.Minute)}
+	events := []cliRunEvent{
+		{Seq: 1, Type: "RUN_CREATED", Timestamp: start},
+		{Seq: 2, Type: "APPROVAL_REQUIRED", Timestamp: start.Add(time.Second), Data: map[string]any{"tool_id": "stripe", "action_id": "act-1"}},
+		{Seq: 3, Type: "APPROVED", Actor: "bob", Timestamp: start.Add(10 * time.Second), Data: map[string]any{"action_id": "act-1"}},
+		{Seq: 4, Type: "TOOL_REQUEST", Timestamp: start.Add(11 * time.Second), Data: map[string]any{"tool_id": "stripe", "tool_method": "post", "tool_url": "https://api.stripe.com/v1/refunds"}},
+		{Seq: 5, Type: "TOOL_RESPONSE", Timestamp: start.Add(12 * time.Second), Data: map[string]any{"tool_id": "stripe", "status_code": 502}},
+		{Seq: 6, Type: "CONSENT_REQUIRED", Timestamp: start.Add(13 * time.Second), Data: map[string]any{"tool_id": "drive"}},
+		{Seq: 7, Type: "RESUMED", Timestamp: start.Add(20 * time.Second)},
+		{Seq: 8, Type: "COMPLETED", Timestamp: start.Add(30 * time.Second)},
+	}
+
+	resource := buildRunResourceSpans(run, events)
+	spans := resource.ScopeSpans[0].Spans
+	if len(spans) != 4 {
+		t.Fatalf("expected root, approval, tool and consent spans, got %d", len(spans))
+	}
+	root := spans[0]
+	if root.TraceID != otlpTraceID("run-1") || root.Kind != otlpSpanKindServer || root.Status.Code != otlpStatusOK {
+		t.Fatalf("unexpected root span: %+v", root)
+	}
+	if root.EndTimeUnixNano != otlpTime(start.Add(time.Minute)) {
+		t.Fatalf("expected root span to end at the last update, got %s", root.EndTimeUnixNano)
+	}
+
+	approval, tool, consent := spans[1], spans[2], spans[3]
+	if otlpAttributeValue(approval.Attributes, "runagents.span_kind") != "approval" || otlpAttributeValue(approval.Attributes, "runagents.decision.actor") != "bob" {
+		t.Fatalf("unexpected approval span: %+v", approval)
+	}
+	if approval.EndTimeUnixNano != otlpTime(start.Add(10*time.Second)) {
+		t.Fatalf("expected approval span to end at the decision, got %s", approval.EndTimeUnixNano)
+	}
+	if tool.Kind != otlpSpanKindClient || tool.Name != "POST stripe" || tool.ParentSpanID != root.SpanID {
+		t.Fatalf("unexpected tool span: %+v", tool)
+	}
+	if otlpAttributeValue(tool.Attributes, "tool_url") != "https://api.stripe.com/v1/refunds" || otlpAttributeValue(tool.Attributes, "http.response.status_code") != "502" {
+		t.Fatalf("unexpected tool span attributes: %+v", tool.Attributes)
+	}
+	if tool.Status.Code != otlpStatusError {
+		t.Fatalf("expected failed tool call to have error status, got %+v", tool.Status)
+	}
+	if otlpAttributeValue(consent.Attributes, "runagents.span_kind") != "consent" || consent.EndTimeUnixNano != otlpTime(start.Add(20*time.Second)) {
+		t.Fatalf("unexpected consent span: %+v", consent)
+	}
+	if len(tool.Events) != 2 || tool.Events[0].Name != "TOOL_REQUEST" || tool.Events[1].Name != "TOOL_RESPONSE" {
+		t.Fatalf("expected tool events on the tool span, got %+v", tool.Events)
+	}
+}
+
+func TestPushOTLPTracesPostsJSONToCollector(t *testing.T) {
+	var (
+		gotPath   string
+		gotHeader string
+		got       otlpTraceExport
+	)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotHeader = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("failed to decode pushed traces: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	run := cliRun{ID: "run-1", AgentID: "billing", Status: "FAILED", CreatedAt: time.Date(2026, 4, 9, 10, 0, 0, 0, time.UTC)}
+	export := otlpTraceExport{ResourceSpans: []otlpResourceSpans{buildRunResourceSpans(run, nil)}}
+	opts := otlpPushOptions{Endpoint: collector.URL, Headers: map[string]string{"Authorization": "Bearer token"}}
+	if err := pushOTLPTraces(context.Background(), opts, export); err != nil {
+		t.Fatalf("pushOTLPTraces returned error: %v", err)
+	}
+	if gotPath != "/v1/traces" || gotHeader != "Bearer token" {
+		t.Fatalf("unexpected request: path %q authorization %q", gotPath, gotHeader)
+	}
+	if len(got.ResourceSpans) != 1 || got.ResourceSpans[0].ScopeSpans[0].Spans[0].Status.Code != otlpStatusError {
+		t.Fatalf("unexpected pushed traces: %+v", got)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad payload", http.StatusBadRequest)
+	}))
+	defer failing.Close()
+	if err := pushOTLPTraces(context.Background(), otlpPushOptions{Endpoint: failing.URL}, export); err == nil {
+		t.Fatal("expected error when the collector rejects the traces")
+	}
+}
+
+func TestOTLPTracesURL(t *testing.T) {
+	tests := map[string]string{
+		"http://localhost:4318":               "http://localhost:4318/v1/traces",
+		"http://localhost:4318/":              "http://localhost:4318/v1/traces",
+		"https://otlp.example.com/v1/traces":  "https://otlp.example.com/v1/traces",
+		"https://otlp.example.com/custom/api": "https://otlp.example.com/custom/api",
+	}
+	for endpoint, want := range tests {
+		if got := otlpTracesURL(endpoint); got != want {
+			t.Fatalf("otlpTracesURL(%q) = %q, want %q", endpoint, got, want)
+		}
+	}
+}
+
+func otlpAttributeValue(attributes []otlpKeyValue, key string) string {
+	for _, attribute := range attributes {
+		if attribute.Key != key {
+			continue
+		}
+		switch {
+		case attribute.Value.StringValue != nil:
+			return *attribute.Value.StringValue
+		case attribute.Value.IntValue != nil:
+			return *attribute.Value.IntValue
+		}
+	}
+	return ""
+}
+
+func TestBuildRunResourceSpansGivesUnnumberedEventsDistinctSpanIDs(t *testing.T) {
+	start := time.Date(2026, 4, 9, 10, 0, 0, 0, time.UTC)
+	run := cliRun{ID: "run-1", Status: "COMPLETED", CreatedAt: start}
+	events := []cliRunEvent{
+		{Type: "TOOL_REQUEST", Timestamp: start, Data: map[string]any{"tool_id": "stripe"}},
+		{Type: "TOOL_REQUEST", Timestamp: start.Add(time.Second), Data: map[string]any{"tool_id": "crm"}},
+		{EventID: "evt-3", Type: "TOOL_REQUEST", Timestamp: start.Add(2 * time.Second), Data: map[string]any{"tool_id": "drive"}},
+	}
+
+	spans := buildRunResourceSpans(run, events).ScopeSpans[0].Spans
+	if len(spans) != 4 {
+		t.Fatalf("expected root and three tool spans, got %d", len(spans))
+	}
+	seen := map[string]bool{}
+	for _, span := range spans {
+		if seen[span.SpanID] {
+			t.Fatalf("duplicate span ID %s in %+v", span.SpanID, spans)
+		}
+		seen[span.SpanID] = true
+	}
+	if spans[3].SpanID != otlpSpanID("run-1", "event/evt-3") {
+		t.Fatalf("expected the event ID to key the span, got %s", spans[3].SpanID)
+	}
+}
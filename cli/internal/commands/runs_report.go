@@ -0,0 +1,453 @@
+package commands
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// runReport is the content of an incident report for one run.
+type runReport struct {
+	Run         cliRun              `json:"run"`
+	Header      []runDetailField    `json:"-"`
+	Duration    time.Duration       `json:"duration_ns,omitempty"`
+	Timeline    []runReportEntry    `json:"timeline"`
+	ToolCalls   []runReportToolCall `json:"tool_calls"`
+	Decisions   []runReportDecision `json:"decisions"`
+	FinalError  string              `json:"final_error,omitempty"`
+	GeneratedAt time.Time           `json:"generated_at"`
+}
+
+// runReportEntry is a timeline entry with the time elapsed since the previous
+// entry and since the start of the run.
+type runReportEntry struct {
+	cliRunTimelineEntry
+	SincePrevious time.Duration `json:"since_previous_ns"`
+	SinceStart    time.Duration `json:"since_start_ns"`
+}
+
+type runReportToolCall struct {
+	Seq        int           `json:"seq"`
+	Tool       string        `json:"tool"`
+	Capability string        `json:"capability,omitempty"`
+	Method     string        `json:"method,omitempty"`
+	URL        string        `json:"url,omitempty"`
+	StatusCode int           `json:"status_code,omitempty"`
+	Outcome    string        `json:"outcome"`
+	Failed     bool          `json:"failed"`
+	Duration   time.Duration `json:"duration_ns,omitempty"`
+	startedAt  time.Time
+	key        string
+}
+
+// runReportDecision is an approval or consent request and how it was resolved.
+type runReportDecision struct {
+	Seq         int           `json:"seq"`
+	Kind        string        `json:"kind"`
+	Tool        string        `json:"tool"`
+	Capability  string        `json:"capability,omitempty"`
+	RequestedAt time.Time     `json:"requested_at"`
+	Decision    string        `json:"decision"`
+	Actor       string        `json:"actor,omitempty"`
+	DecidedAt   time.Time     `json:"decided_at"`
+	Wait        time.Duration `json:"wait_ns,omitempty"`
+	key         string
+}
+
+func newRunsReportCmd() *cobra.Command {
+	var (
+		format string
+		file   string
+	)
+	cmd := &cobra.Command{
+		Use:   "report <run-id>",
+		Short: "Render an incident report for a run as Markdown or HTML",
+		Long: `Render a self-contained report for a run: the run details, a timeline with the
+time between events, tool calls and their HTTP outcomes, approval and consent
+decisions with who made them, and the final error.
+
+Examples:
+  runagents runs report <run-id> > incident.md
+  runagents runs report <run-id> --format html --file incident.html`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format = strings.ToLower(strings.TrimSpace(format))
+			if format == "md" {
+				format = "markdown"
+			}
+			if format != "markdown" && format != "html" {
+				return fmt.Errorf("invalid --format %q: use markdown or html", format)
+			}
+			c, err := newAPIClient(cmd.Context())
+			if err != nil {
+				return err
+			}
+			run, err := fetchRun(c, args[0])
+			if err != nil {
+				return err
+			}
+			events, err := fetchRunEvents(c, args[0], 0)
+			if err != nil {
+				return err
+			}
+			report := buildRunReport(*run, events, time.Now().UTC())
+			if isJSONOutput() {
+				return printJSONValue(report)
+			}
+
+			var out io.Writer = os.Stdout
+			if file != "" {
+				f, err := os.Create(file)
+				if err != nil {
+					return fmt.Errorf("failed to create report file: %w", err)
+				}
+				defer f.Close()
+				out = f
+			}
+			if format == "html" {
+				err = renderRunReportHTML(out, report)
+			} else {
+				err = renderRunReportMarkdown(out, report)
+			}
+			if err != nil {
+				return err
+			}
+			if file != "" {
+				fmt.Printf("Report for run %s written to %s.\n", run.ID, file)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "markdown", "Report format: markdown or html")
+	cmd.Flags().StringVar(&file, "file", "", "Write the report to this file instead of stdout")
+	return cmd
+}
+
+func buildRunReport(run cliRun, events []cliRunEvent, now time.Time) runReport {
+	events = sortedRunEvents(events)
+	report := runReport{
+		Run:         run,
+		Header:      runDetailFields(run),
+		Timeline:    []runReportEntry{},
+		ToolCalls:   []runReportToolCall{},
+		Decisions:   []runReportDecision{},
+		GeneratedAt: now,
+	}
+	start, end := runTraceBounds(run, events)
+	if isTerminalRunStatus(run.Status) && !start.IsZero() {
+		report.Duration = end.Sub(start)
+	}
+
+	previous := start
+	for _, entry := range buildRunTimeline(run, events) {
+		reportEntry := runReportEntry{cliRunTimelineEntry: entry}
+		if !entry.Timestamp.IsZero() && !start.IsZero() {
+			reportEntry.SincePrevious = entry.Timestamp.Sub(previous)
+			reportEntry.SinceStart = entry.Timestamp.Sub(start)
+			previous = entry.Timestamp
+		}
+		report.Timeline = append(report.Timeline, reportEntry)
+	}
+
+	var openCalls, openDecisions []int
+	for _, event := range events {
+		key := runTraceEventKey(event)
+		switch strings.ToUpper(event.Type) {
+		case "TOOL_REQUEST", "TOOL_CALLED":
+			if strings.EqualFold(event.Type, "TOOL_CALLED") && hasOpenRunReportCall(report.ToolCalls, openCalls, key) {
+				// The call was already recorded by its TOOL_REQUEST.
+				continue
+			}
+			report.ToolCalls = append(report.ToolCalls, runReportToolCall{
+				Seq:        event.Seq,
+				Tool:       runTraceToolName(event),
+				Capability: dataString(event.Data, "capability"),
+				Method:     strings.ToUpper(dataString(event.Data, "tool_method")),
+				URL:        dataString(event.Data, "tool_url"),
+				Outcome:    "No response recorded",
+				startedAt:  event.Timestamp,
+				key:        key,
+			})
+			openCalls = append(openCalls, len(report.ToolCalls)-1)
+		case "TOOL_RESPONSE":
+			match := matchRunReportItem(openCalls, key, func(i int) string { return report.ToolCalls[i].key })
+			if match < 0 {
+				continue
+			}
+			call := &report.ToolCalls[openCalls[match]]
+			openCalls = append(openCalls[:match], openCalls[match+1:]...)
+			if !call.startedAt.IsZero() && !event.Timestamp.IsZero() {
+				call.Duration = event.Timestamp.Sub(call.startedAt)
+			}
+			call.Outcome = "Returned successfully"
+			if statusCode, err := strconv.Atoi(dataString(event.Data, "status_code")); err == nil {
+				call.StatusCode = statusCode
+				call.Outcome = fmt.Sprintf("HTTP %d", statusCode)
+				call.Failed = statusCode >= 400
+			}
+			if message := dataString(event.Data, "error"); message != "" {
+				if call.StatusCode > 0 {
+					call.Outcome = fmt.Sprintf("HTTP %d: %s", call.StatusCode, message)
+				} else {
+					call.Outcome = message
+				}
+				call.Failed = true
+			}
+		case "APPROVAL_REQUIRED", "CONSENT_REQUIRED":
+			kind := "approval"
+			if strings.EqualFold(event.Type, "CONSENT_REQUIRED") {
+				kind = "consent"
+			}
+			report.Decisions = append(report.Decisions, runReportDecision{
+				Seq:         event.Seq,
+				Kind:        kind,
+				Tool:        runTraceToolName(event),
+				Capability:  dataString(event.Data, "capability"),
+				RequestedAt: event.Timestamp,
+				Decision:    "PENDING",
+				key:         key,
+			})
+			openDecisions = append(openDecisions, len(report.Decisions)-1)
+		case "APPROVED", "REJECTED", "RESUMED":
+			kind := ""
+			if strings.EqualFold(event.Type, "APPROVED") {
+				kind = "approval"
+			} else if strings.EqualFold(event.Type, "RESUMED") {
+				kind = "consent"
+			}
+			candidates := make([]int, 0, len(openDecisions))
+			for _, index := range openDecisions {
+				if kind == "" || report.Decisions[index].Kind == kind {
+					candidates = append(candidates, index)
+				}
+			}
+			match := matchRunReportItem(candidates, key, func(i int) string { return report.Decisions[i].key })
+			if match < 0 {
+				continue
+			}
+			index := candidates[match]
+			for i, open := range openDecisions {
+				if open == index {
+					openDecisions = append(openDecisions[:i], openDecisions[i+1:]...)
+					break
+				}
+			}
+			decision := &report.Decisions[index]
+			decision.Decision = strings.ToUpper(event.Type)
+			if decision.Decision == "RESUMED" {
+				decision.Decision = "GRANTED"
+			}
+			decision.Actor = firstNonEmptyRunValue(dataString(event.Data, "approver_id"), event.Actor)
+			decision.DecidedAt = event.Timestamp
+			if !event.Timestamp.IsZero() && !decision.RequestedAt.IsZero() {
+				decision.Wait = event.Timestamp.Sub(decision.RequestedAt)
+			}
+		case "FAILED", "INVOKE_FAILED":
+			report.FinalError = summarizeRunEvent(event)
+		}
+	}
+	if report.FinalError == "" && strings.EqualFold(run.Status, "FAILED") {
+		report.FinalError = "Run failed without a recorded error"
+	}
+	return report
+}
+
+func hasOpenRunReportCall(calls []runReportToolCall, open []int, key string) bool {
+	for _, index := range open {
+		if calls[index].key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// matchRunReportItem returns the position in open of the item whose key
+// matches key, falling back to the oldest open item, or -1 if none is open.
+func matchRunReportItem(open []int, key string, keyOf func(int) string) int {
+	if len(open) == 0 {
+		return -1
+	}
+	if key != "" {
+		for i, index := range open {
+			if keyOf(index) == key {
+				return i
+			}
+		}
+	}
+	return 0
+}
+
+// formatRunDuration renders a duration with millisecond precision under a
+// minute and second precision above.
+func formatRunDuration(d time.Duration) string {
+	if d <= 0 {
+		return "0s"
+	}
+	if d < time.Minute {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(time.Second).String()
+}
+
+func renderRunReportMarkdown(w io.Writer, report runReport) error {
+	var b strings.Builder
+	run := report.Run
+	fmt.Fprintf(&b, "# Run report: %s\n\n", run.ID)
+	b.WriteString("| Field | Value |\n|-------|-------|\n")
+	for _, field := range report.Header {
+		fmt.Fprintf(&b, "| %s | %s |\n", field.Label, markdownCell(field.Value))
+	}
+	if report.Duration > 0 {
+		fmt.Fprintf(&b, "| Duration | %s |\n", formatRunDuration(report.Duration))
+	}
+	b.WriteString("\n")
+
+	if report.FinalError != "" {
+		fmt.Fprintf(&b, "> **Final error:** %s\n\n", strings.ReplaceAll(strings.TrimSpace(report.FinalError), "\n", "\n> "))
+	}
+	if run.InitialMessage != "" {
+		fmt.Fprintf(&b, "## Initial message\n\n> %s\n\n", strings.ReplaceAll(strings.TrimSpace(run.InitialMessage), "\n", "\n> "))
+	}
+
+	b.WriteString("## Timeline\n\n")
+	b.WriteString("| Seq | Time | +Since previous | Elapsed | Type | Actor | Detail |\n")
+	b.WriteString("|----:|------|----------------:|--------:|------|-------|--------|\n")
+	for _, entry := range report.Timeline {
+		seq := ""
+		if entry.Seq > 0 {
+			seq = strconv.Itoa(entry.Seq)
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s | %s |\n",
+			seq, formatRunTime(entry.Timestamp), formatRunDuration(entry.SincePrevious), formatRunDuration(entry.SinceStart),
+			entry.Type, markdownCell(entry.Actor), markdownCell(entry.Summary))
+	}
+	b.WriteString("\n")
+
+	b.WriteString("## Tool calls\n\n")
+	if len(report.ToolCalls) == 0 {
+		b.WriteString("No tool calls recorded.\n\n")
+	} else {
+		b.WriteString("| Seq | Tool | Capability | Request | Outcome | Duration |\n")
+		b.WriteString("|----:|------|------------|---------|---------|---------:|\n")
+		for _, call := range report.ToolCalls {
+			outcome := markdownCell(call.Outcome)
+			if call.Failed {
+				outcome = "**" + outcome + "**"
+			}
+			fmt.Fprintf(&b, "| %d | %s | %s | %s | %s | %s |\n",
+				call.Seq, markdownCell(call.Tool), markdownCell(call.Capability), markdownCell(strings.TrimSpace(call.Method+" "+call.URL)), outcome, formatRunDuration(call.Duration))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("## Approvals and consent\n\n")
+	if len(report.Decisions) == 0 {
+		b.WriteString("No approval or consent requests recorded.\n\n")
+	} else {
+		b.WriteString("| Seq | Kind | Tool | Capability | Decision | By | Requested | Waited |\n")
+		b.WriteString("|----:|------|------|------------|----------|----|-----------|-------:|\n")
+		for _, decision := range report.Decisions {
+			fmt.Fprintf(&b, "| %d | %s | %s | %s | %s | %s | %s | %s |\n",
+				decision.Seq, decision.Kind, markdownCell(decision.Tool), markdownCell(decision.Capability), decision.Decision,
+				markdownCell(decision.Actor), formatRunTime(decision.RequestedAt), formatRunDuration(decision.Wait))
+		}
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "_Generated by the runagents CLI at %s._\n", formatRunTime(report.GeneratedAt))
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownCell makes a value safe to place in a Markdown table cell.
+func markdownCell(value string) string {
+	value = strings.ReplaceAll(value, "|", "\\|")
+	value = strings.ReplaceAll(value, "\r\n", " ")
+	return strings.ReplaceAll(value, "\n", " ")
+}
+
+var runReportHTMLTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"time":     formatRunTime,
+	"duration": formatRunDuration,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Run report: {{.Run.ID}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2rem; color: #1f2328; }
+h1 { font-size: 1.5rem; } h2 { font-size: 1.2rem; margin-top: 2rem; }
+table { border-collapse: collapse; width: 100%; font-size: 0.9rem; }
+th, td { border: 1px solid #d0d7de; padding: 0.3rem 0.6rem; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+td.num { text-align: right; white-space: nowrap; }
+table.header { width: auto; } table.header th { width: 10rem; }
+.error { border-left: 4px solid #cf222e; background: #ffebe9; padding: 0.6rem 1rem; }
+.failed { color: #cf222e; font-weight: 600; }
+blockquote { border-left: 4px solid #d0d7de; margin: 0; padding: 0.2rem 1rem; color: #57606a; white-space: pre-wrap; }
+footer { margin-top: 2rem; color: #57606a; font-size: 0.8rem; }
+</style>
+</head>
+<body>
+<h1>Run report: {{.Run.ID}}</h1>
+<table class="header">
+{{- range .Header}}
+<tr><th>{{.Label}}</th><td>{{.Value}}</td></tr>
+{{- end}}
+{{- if gt .Duration 0}}
+<tr><th>Duration</th><td>{{duration .Duration}}</td></tr>
+{{- end}}
+</table>
+{{- if .FinalError}}
+<p class="error"><strong>Final error:</strong> {{.FinalError}}</p>
+{{- end}}
+{{- if .Run.InitialMessage}}
+<h2>Initial message</h2>
+<blockquote>{{.Run.InitialMessage}}</blockquote>
+{{- end}}
+<h2>Timeline</h2>
+<table>
+<tr><th>Seq</th><th>Time</th><th>+Since previous</th><th>Elapsed</th><th>Type</th><th>Actor</th><th>Detail</th></tr>
+{{- range .Timeline}}
+<tr><td class="num">{{if .Seq}}{{.Seq}}{{end}}</td><td>{{time .Timestamp}}</td><td class="num">{{duration .SincePrevious}}</td><td class="num">{{duration .SinceStart}}</td><td>{{.Type}}</td><td>{{.Actor}}</td><td>{{.Summary}}</td></tr>
+{{- end}}
+</table>
+<h2>Tool calls</h2>
+{{- if .ToolCalls}}
+<table>
+<tr><th>Seq</th><th>Tool</th><th>Capability</th><th>Request</th><th>Outcome</th><th>Duration</th></tr>
+{{- range .ToolCalls}}
+<tr><td class="num">{{.Seq}}</td><td>{{.Tool}}</td><td>{{.Capability}}</td><td>{{.Method}} {{.URL}}</td><td{{if .Failed}} class="failed"{{end}}>{{.Outcome}}</td><td class="num">{{duration .Duration}}</td></tr>
+{{- end}}
+</table>
+{{- else}}
+<p>No tool calls recorded.</p>
+{{- end}}
+<h2>Approvals and consent</h2>
+{{- if .Decisions}}
+<table>
+<tr><th>Seq</th><th>Kind</th><th>Tool</th><th>Capability</th><th>Decision</th><th>By</th><th>Requested</th><th>Waited</th></tr>
+{{- range .Decisions}}
+<tr><td class="num">{{.Seq}}</td><td>{{.Kind}}</td><td>{{.Tool}}</td><td>{{.Capability}}</td><td{{if eq .Decision "REJECTED"}} class="failed"{{end}}>{{.Decision}}</td><td>{{.Actor}}</td><td>{{time .RequestedAt}}</td><td class="num">{{duration .Wait}}</td></tr>
+{{- end}}
+</table>
+{{- else}}
+<p>No approval or consent requests recorded.</p>
+{{- end}}
+<footer>Generated by the runagents CLI at {{time .GeneratedAt}}.</footer>
+</body>
+</html>
+`))
+
+func renderRunReportHTML(w io.Writer, report runReport) error {
+	if err := runReportHTMLTemplate.Execute(w, report); err != nil {
+		return fmt.Errorf("failed to render report: %w", err)
+	}
+	return nil
+}
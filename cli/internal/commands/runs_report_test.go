@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func sampleFailedRunForReport() (cliRun, []cliRunEvent) {
+	start := time.Date(2026, 4, 9, 10, 0, 0, 0, time.UTC)
+	run := cliRun{ID: "run-1", AgentID: "billing", UserID: "alice", Status: "FAILED", Namespace: "default", CreatedAt: start, UpdatedAt: start.Add(20 * time.Second)}
+	events := []cliRunEvent{
+		{Seq: 1, Type: "RUN_CREATED", Timestamp: start},
+		{Seq: 2, Type: "APPROVAL_REQUIRED", Timestamp: start.Add(2 * time.Second), Data: map[string]any{"tool_id": "stripe", "capability": "refund", "action_id": "act-1"}},
+		{Seq: 3, Type: "APPROVED", Actor: "bob", Timestamp: start.Add(7 * time.Second), Data: map[string]any{"action_id": "act-1"}},
+		{Seq: 4, Type: "TOOL_REQUEST", Timestamp: start.Add(8 * time.Second), Data: map[string]any{"tool_id": "stripe", "tool_method": "POST", "tool_url": "https://api.stripe.com/v1/refunds|x"}},
+		{Seq: 5, Type: "TOOL_RESPONSE", Timestamp: start.Add(8500 * time.Millisecond), Data: map[string]any{"tool_id": "stripe", "status_code": 502}},
+		{Seq: 6, Type: "FAILED", Timestamp: start.Add(10 * time.Second), Data: map[string]any{"error": "upstream <unavailable>"}},
+	}
+	return run, events
+}
+
+func TestBuildRunReportCollectsToolCallsDecisionsAndError(t *testing.T) {
+	run, events := sampleFailedRunForReport()
+	report := buildRunReport(run, events, time.Date(2026, 4, 9, 12, 0, 0, 0, time.UTC))
+
+	if report.Duration != 20*time.Second {
+		t.Fatalf("expected 20s run duration, got %s", report.Duration)
+	}
+	if len(report.Timeline) != 6 || report.Timeline[2].SincePrevious != 5*time.Second || report.Timeline[2].SinceStart != 7*time.Second {
+		t.Fatalf("unexpected timeline durations: %+v", report.Timeline)
+	}
+	if len(report.ToolCalls) != 1 {
+		t.Fatalf("expected one tool call, got %+v", report.ToolCalls)
+	}
+	call := report.ToolCalls[0]
+	if call.Outcome != "HTTP 502" || !call.Failed || call.Duration != 500*time.Millisecond {
+		t.Fatalf("unexpected tool call: %+v", call)
+	}
+	if len(report.Decisions) != 1 || report.Decisions[0].Decision != "APPROVED" || report.Decisions[0].Actor != "bob" || report.Decisions[0].Wait != 5*time.Second {
+		t.Fatalf("unexpected decisions: %+v", report.Decisions)
+	}
+	if report.FinalError != "upstream <unavailable>" {
+		t.Fatalf("unexpected final error %q", report.FinalError)
+	}
+}
+
+func TestRenderRunReportMarkdownAndHTML(t *testing.T) {
+	run, events := sampleFailedRunForReport()
+	report := buildRunReport(run, events, time.Date(2026, 4, 9, 12, 0, 0, 0, time.UTC))
+
+	var markdown strings.Builder
+	if err := renderRunReportMarkdown(&markdown, report); err != nil {
+		t.Fatalf("renderRunReportMarkdown returned error: %v", err)
+	}
+	for _, want := range []string{
+		"# Run report: run-1",
+		"| Agent | billing |",
+		"> **Final error:** upstream <unavailable>",
+		"| 4 | stripe |  | POST https://api.stripe.com/v1/refunds\\|x | **HTTP 502** | 500ms |",
+		"| 2 | approval | stripe | refund | APPROVED | bob |",
+	} {
+		if !strings.Contains(markdown.String(), want) {
+			t.Fatalf("expected markdown to contain %q, got:\n%s", want, markdown.String())
+		}
+	}
+
+	var html strings.Builder
+	if err := renderRunReportHTML(&html, report); err != nil {
+		t.Fatalf("renderRunReportHTML returned error: %v", err)
+	}
+	if !strings.Contains(html.String(), "upstream &lt;unavailable&gt;") {
+		t.Fatalf("expected escaped final error in html, got:\n%s", html.String())
+	}
+	if !strings.Contains(html.String(), "<style>") || strings.Contains(html.String(), "<link") {
+		t.Fatal("expected a self-contained html document")
+	}
+}
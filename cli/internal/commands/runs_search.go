@@ -0,0 +1,280 @@
+package commands
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// runEventQuery selects run events. Every set field must match the same event,
+// except that a TOOL_RESPONSE also carries the HTTP fields of its request.
+type runEventQuery struct {
+	Types      []string
+	Tool       string
+	Capability string
+	Method     string
+	URL        string
+	StatusCode string
+	Actor      string
+
+	url *regexp.Regexp
+}
+
+// runSearchResult is a run with the events that matched the query.
+type runSearchResult struct {
+	RunID   string                `json:"run_id"`
+	AgentID string                `json:"agent_id,omitempty"`
+	UserID  string                `json:"user_id,omitempty"`
+	Status  string                `json:"status,omitempty"`
+	Matches []cliRunTimelineEntry `json:"matches"`
+}
+
+func newRunsSearchCmd() *cobra.Command {
+	var (
+		filters runFilters
+		query   runEventQuery
+		since   string
+		until   string
+		workers int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "search",
+		Short: "Find runs whose events match tool, capability, HTTP, or actor filters",
+		Long: `Scan the events of every run created in a time window and print the runs
+with events that match all of the given filters, along with those events.
+
+--url matches a substring of the tool URL, or the whole URL when it contains
+'*' wildcards. --status-code accepts a code such as 403 or a class such as 5xx.
+A TOOL_RESPONSE also matches on the tool, URL, and method of its request.
+
+Examples:
+  runagents runs search --since 3d --tool erp --method POST
+  runagents runs search --type CONSENT_REQUIRED --tool google-calendar
+  runagents runs search --url 'https://api.stripe.com/v1/refunds*' --status-code 4xx
+  runagents runs search --type APPROVED --actor alice@example.com -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := query.compile(); err != nil {
+				return err
+			}
+			if query.empty() {
+				return fmt.Errorf("pass at least one event filter: --type, --tool, --capability, --method, --url, --status-code, or --actor")
+			}
+			now := time.Now().UTC()
+			var err error
+			if filters.Since, err = parseRunTimeBound(since, now); err != nil {
+				return fmt.Errorf("invalid --since: %w", err)
+			}
+			if filters.Until, err = parseRunTimeBound(until, now); err != nil {
+				return fmt.Errorf("invalid --until: %w", err)
+			}
+			if !filters.Since.IsZero() && !filters.Until.IsZero() && !filters.Since.Before(filters.Until) {
+				return fmt.Errorf("--since must be before --until")
+			}
+
+			c, err := newAPIClient(cmd.Context())
+			if err != nil {
+				return err
+			}
+			runs, _, err := fetchRuns(c, filters)
+			if err != nil {
+				return err
+			}
+			ids := make([]string, 0, len(runs))
+			for _, run := range runs {
+				ids = append(ids, run.ID)
+			}
+			events, err := fetchRunEventsForRuns(cmd.Context(), c, ids, workers)
+			if err != nil {
+				return err
+			}
+
+			results := searchRunEvents(runs, events, query)
+			if isJSONOutput() {
+				return printJSONValue(results)
+			}
+			if len(results) == 0 {
+				fmt.Printf("No matching events in %d runs.\n", len(runs))
+				return nil
+			}
+			table := newTable("RUN", "AGENT", "SEQ", "TYPE", "TIME", "SUMMARY")
+			matches := 0
+			for _, result := range results {
+				for _, entry := range result.Matches {
+					table.Append([]string{result.RunID, result.AgentID, strconv.Itoa(entry.Seq), entry.Type, formatRunTime(entry.Timestamp), entry.Summary})
+					matches++
+				}
+			}
+			table.Render()
+			fmt.Printf("\n%d matching events in %d of %d runs.\n", matches, len(results), len(runs))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "7d", "Start of the window: RFC 3339 time, date, or duration ago (for example 24h or 3d)")
+	cmd.Flags().StringVar(&until, "until", "", "End of the window, in the same formats as --since (default now)")
+	cmd.Flags().StringVar(&filters.AgentID, "agent", "", "Only search runs of this agent")
+	cmd.Flags().StringVar(&filters.UserID, "user", "", "Only search runs for this user ID")
+	cmd.Flags().StringVar(&filters.Status, "status", "", "Only search runs with this status")
+	cmd.Flags().StringSliceVar(&query.Types, "type", nil, "Event type to match (repeatable, for example TOOL_REQUEST or CONSENT_REQUIRED)")
+	cmd.Flags().StringVar(&query.Tool, "tool", "", "Tool ID to match")
+	cmd.Flags().StringVar(&query.Capability, "capability", "", "Capability to match")
+	cmd.Flags().StringVar(&query.Method, "method", "", "HTTP method to match")
+	cmd.Flags().StringVar(&query.URL, "url", "", "Tool URL substring, or pattern with * wildcards")
+	cmd.Flags().StringVar(&query.StatusCode, "status-code", "", "HTTP status code (403) or class (5xx) to match")
+	cmd.Flags().StringVar(&query.Actor, "actor", "", "Event actor to match (for example an approver)")
+	cmd.Flags().IntVar(&workers, "concurrency", 8, "Maximum number of run event requests in flight")
+	return cmd
+}
+
+// searchRunEvents returns, in run order, the runs with at least one event
+// matching the query.
+func searchRunEvents(runs []cliRun, events map[string][]cliRunEvent, query runEventQuery) []runSearchResult {
+	results := []runSearchResult{}
+	for _, run := range runs {
+		var matches []cliRunTimelineEntry
+		for _, event := range withToolRequestFields(sortedRunEvents(events[run.ID])) {
+			if query.matches(event) {
+				matches = append(matches, runTimelineEntry(event))
+			}
+		}
+		if len(matches) == 0 {
+			continue
+		}
+		results = append(results, runSearchResult{
+			RunID:   run.ID,
+			AgentID: run.AgentID,
+			UserID:  run.UserID,
+			Status:  run.Status,
+			Matches: matches,
+		})
+	}
+	return results
+}
+
+// runToolRequestFields are copied from a tool request onto its response so
+// that filters such as --url and --status-code can match the same call.
+var runToolRequestFields = []string{"tool_id", "tool", "capability", "tool_method", "method", "tool_url", "url"}
+
+// withToolRequestFields pairs each TOOL_RESPONSE with the earliest unanswered
+// TOOL_REQUEST of the same action or tool and fills in the request fields the
+// response does not carry. Events are expected in sequence order.
+func withToolRequestFields(events []cliRunEvent) []cliRunEvent {
+	var open []cliRunEvent
+	paired := make([]cliRunEvent, 0, len(events))
+	for _, event := range events {
+		switch strings.ToUpper(event.Type) {
+		case "TOOL_REQUEST":
+			open = append(open, event)
+		case "TOOL_RESPONSE":
+			key := runTraceEventKey(event)
+			match := -1
+			for i, request := range open {
+				if key == "" || runTraceEventKey(request) == key {
+					match = i
+					break
+				}
+			}
+			if match >= 0 {
+				request := open[match]
+				open = append(open[:match], open[match+1:]...)
+				data := make(map[string]any, len(event.Data)+len(runToolRequestFields))
+				for _, field := range runToolRequestFields {
+					if value, ok := request.Data[field]; ok {
+						data[field] = value
+					}
+				}
+				for field, value := range event.Data {
+					data[field] = value
+				}
+				event.Data = data
+			}
+		}
+		paired = append(paired, event)
+	}
+	return paired
+}
+
+func (q *runEventQuery) compile() error {
+	if code := strings.ToLower(strings.TrimSpace(q.StatusCode)); code != "" {
+		if !regexp.MustCompile(`^[1-5]([0-9]{2}|xx)$`).MatchString(code) {
+			return fmt.Errorf("invalid --status-code %q: use a code such as 404 or a class such as 4xx", q.StatusCode)
+		}
+		q.StatusCode = code
+	}
+	q.URL = strings.TrimSpace(q.URL)
+	q.url = nil
+	if strings.Contains(q.URL, "*") {
+		parts := strings.Split(q.URL, "*")
+		for i, part := range parts {
+			parts[i] = regexp.QuoteMeta(part)
+		}
+		q.url = regexp.MustCompile("^" + strings.Join(parts, ".*") + "$")
+	}
+	return nil
+}
+
+func (q runEventQuery) empty() bool {
+	return len(q.Types) == 0 && q.Tool == "" && q.Capability == "" && q.Method == "" &&
+		q.URL == "" && q.StatusCode == "" && q.Actor == ""
+}
+
+func (q runEventQuery) matches(event cliRunEvent) bool {
+	if len(q.Types) > 0 {
+		found := false
+		for _, eventType := range q.Types {
+			if strings.EqualFold(strings.TrimSpace(eventType), event.Type) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if q.Tool != "" && !strings.EqualFold(q.Tool, firstNonEmptyRunValue(dataString(event.Data, "tool_id"), dataString(event.Data, "tool"))) {
+		return false
+	}
+	if q.Capability != "" && !strings.EqualFold(q.Capability, dataString(event.Data, "capability")) {
+		return false
+	}
+	if q.Method != "" && !strings.EqualFold(q.Method, firstNonEmptyRunValue(dataString(event.Data, "tool_method"), dataString(event.Data, "method"))) {
+		return false
+	}
+	if q.URL != "" {
+		eventURL := firstNonEmptyRunValue(dataString(event.Data, "tool_url"), dataString(event.Data, "url"))
+		if eventURL == "" {
+			return false
+		}
+		if q.url != nil && !q.url.MatchString(eventURL) {
+			return false
+		}
+		if q.url == nil && !strings.Contains(eventURL, q.URL) {
+			return false
+		}
+	}
+	if q.StatusCode != "" && !matchesStatusCode(q.StatusCode, dataString(event.Data, "status_code")) {
+		return false
+	}
+	if q.Actor != "" && !strings.EqualFold(q.Actor, firstNonEmptyRunValue(event.Actor, dataString(event.Data, "approver_id"))) {
+		return false
+	}
+	return true
+}
+
+// matchesStatusCode compares a status code against 404 or a class like 4xx.
+func matchesStatusCode(want, got string) bool {
+	got = strings.TrimSpace(got)
+	if len(got) != 3 {
+		return false
+	}
+	if strings.HasSuffix(want, "xx") {
+		return got[0] == want[0]
+	}
+	return got == want
+}
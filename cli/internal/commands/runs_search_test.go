@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRunEventQueryMatchesAllFiltersOnOneEvent(t *testing.T) {
+	post := cliRunEvent{Seq: 2, Type: "TOOL_REQUEST", Data: map[string]any{"tool_id": "erp", "tool_method": "POST", "tool_url": "https://erp.internal/api/v2/orders", "capability": "orders.create"}}
+	get := cliRunEvent{Seq: 3, Type: "TOOL_REQUEST", Data: map[string]any{"tool_id": "erp", "tool_method": "GET", "tool_url": "https://erp.internal/api/v2/orders/7"}}
+	failed := cliRunEvent{Seq: 4, Type: "TOOL_RESPONSE", Data: map[string]any{"tool_id": "erp", "status_code": float64(503)}}
+
+	cases := []struct {
+		name  string
+		query runEventQuery
+		event cliRunEvent
+		want  bool
+	}{
+		{"tool and method", runEventQuery{Tool: "ERP", Method: "post"}, post, true},
+		{"method mismatch", runEventQuery{Tool: "erp", Method: "POST"}, get, false},
+		{"url substring", runEventQuery{URL: "/orders"}, get, true},
+		{"url wildcard", runEventQuery{URL: "https://erp.internal/*/orders"}, post, true},
+		{"url wildcard is anchored", runEventQuery{URL: "https://erp.internal/*/orders"}, get, false},
+		{"status class", runEventQuery{StatusCode: "5xx"}, failed, true},
+		{"status code", runEventQuery{StatusCode: "500"}, failed, false},
+		{"type list", runEventQuery{Types: []string{"tool_response", "FAILED"}}, failed, true},
+		{"capability", runEventQuery{Capability: "orders.create", Types: []string{"TOOL_REQUEST"}}, post, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			query := tc.query
+			if err := query.compile(); err != nil {
+				t.Fatalf("compile: %v", err)
+			}
+			if got := query.matches(tc.event); got != tc.want {
+				t.Fatalf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestRunEventQueryRejectsInvalidStatusCode(t *testing.T) {
+	query := runEventQuery{StatusCode: "50"}
+	if err := query.compile(); err == nil {
+		t.Fatalf("expected an error for a two-digit status code")
+	}
+}
+
+func TestSearchRunEventsReturnsMatchingRunsWithSummaries(t *testing.T) {
+	now := time.Date(2026, 4, 9, 10, 0, 0, 0, time.UTC)
+	runs := []cliRun{
+		{ID: "run-1", AgentID: "scheduler"},
+		{ID: "run-2", AgentID: "scheduler"},
+	}
+	events := map[string][]cliRunEvent{
+		"run-1": {
+			{Seq: 2, Type: "CONSENT_REQUIRED", Timestamp: now, Data: map[string]any{"tool_id": "google-calendar"}},
+			{Seq: 1, Type: "USER_MESSAGE", Timestamp: now, Data: map[string]any{"content": "book a meeting"}},
+		},
+		"run-2": {
+			{Seq: 1, Type: "CONSENT_REQUIRED", Timestamp: now, Data: map[string]any{"tool_id": "slack"}},
+		},
+	}
+
+	query := runEventQuery{Types: []string{"CONSENT_REQUIRED"}, Tool: "google-calendar"}
+	results := searchRunEvents(runs, events, query)
+	if len(results) != 1 || results[0].RunID != "run-1" {
+		t.Fatalf("expected only run-1, got %+v", results)
+	}
+	if len(results[0].Matches) != 1 || results[0].Matches[0].Summary != "Consent required for google-calendar" {
+		t.Fatalf("unexpected matches: %+v", results[0].Matches)
+	}
+}
+
+func TestSearchRunEventsMatchesResponsesAgainstTheirRequest(t *testing.T) {
+	now := time.Date(2026, 4, 9, 10, 0, 0, 0, time.UTC)
+	runs := []cliRun{{ID: "run-1", AgentID: "billing"}}
+	events := map[string][]cliRunEvent{
+		"run-1": {
+			{Seq: 1, Type: "TOOL_REQUEST", Timestamp: now, Data: map[string]any{"action_id": "act-1", "tool_id": "stripe", "tool_method": "POST", "tool_url": "https://api.stripe.com/v1/refunds"}},
+			{Seq: 2, Type: "TOOL_REQUEST", Timestamp: now, Data: map[string]any{"action_id": "act-2", "tool_id": "stripe", "tool_method": "GET", "tool_url": "https://api.stripe.com/v1/charges/ch_1"}},
+			{Seq: 3, Type: "TOOL_RESPONSE", Timestamp: now, Data: map[string]any{"action_id": "act-2", "tool_id": "stripe", "status_code": float64(404)}},
+			{Seq: 4, Type: "TOOL_RESPONSE", Timestamp: now, Data: map[string]any{"action_id": "act-1", "tool_id": "stripe", "status_code": float64(402)}},
+		},
+	}
+
+	query := runEventQuery{URL: "https://api.stripe.com/v1/refunds*", StatusCode: "4xx"}
+	if err := query.compile(); err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	results := searchRunEvents(runs, events, query)
+	if len(results) != 1 || len(results[0].Matches) != 1 || results[0].Matches[0].Seq != 4 {
+		t.Fatalf("expected only the refund response, got %+v", results)
+	}
+
+	query = runEventQuery{Method: "GET", StatusCode: "404"}
+	if err := query.compile(); err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	results = searchRunEvents(runs, events, query)
+	if len(results) != 1 || len(results[0].Matches) != 1 || results[0].Matches[0].Seq != 3 {
+		t.Fatalf("expected only the charge response, got %+v", results)
+	}
+}
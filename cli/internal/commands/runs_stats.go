@@ -0,0 +1,481 @@
+package commands
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// runStats is the aggregate reported by runs stats.
+type runStats struct {
+	Since    time.Time           `json:"since"`
+	Until    time.Time           `json:"until"`
+	Overall  runStatsGroup       `json:"overall"`
+	ByAgent  []runStatsGroup     `json:"by_agent"`
+	ByUser   []runStatsGroup     `json:"by_user"`
+	ByStatus []runStatusCount    `json:"by_status"`
+	ByDay    []runStatsGroup     `json:"by_day"`
+	Errors   []runErrorStatGroup `json:"errors"`
+}
+
+// runStatsGroup summarizes the runs sharing a key. Success rate is the share
+// of terminal runs that completed; durations cover terminal runs only.
+type runStatsGroup struct {
+	Key                       string  `json:"key,omitempty"`
+	Runs                      int     `json:"runs"`
+	Completed                 int     `json:"completed"`
+	Failed                    int     `json:"failed"`
+	SuccessRate               float64 `json:"success_rate"`
+	MedianDurationSeconds     float64 `json:"median_duration_seconds"`
+	P95DurationSeconds        float64 `json:"p95_duration_seconds"`
+	Approvals                 int     `json:"approvals"`
+	MedianApprovalWaitSeconds float64 `json:"median_approval_wait_seconds"`
+	P95ApprovalWaitSeconds    float64 `json:"p95_approval_wait_seconds"`
+	durations, approvalWaits  []time.Duration
+}
+
+type runStatusCount struct {
+	Status string `json:"status"`
+	Runs   int    `json:"runs"`
+}
+
+// runErrorStatGroup counts failure events sharing a normalized message.
+type runErrorStatGroup struct {
+	Error     string    `json:"error"`
+	Count     int       `json:"count"`
+	Agents    []string  `json:"agents"`
+	Example   string    `json:"example"`
+	ExampleID string    `json:"example_run_id"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+func newRunsStatsCmd() *cobra.Command {
+	var (
+		filters    runFilters
+		since      string
+		until      string
+		skipEvents bool
+		workers    int
+		topErrors  int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Aggregate run outcomes, durations, approval waits, and failures",
+		Long: `Aggregate the runs created in a time window by agent, user, status, and day.
+
+Reports success rate (completed out of terminal runs), median and p95 run
+duration, approval wait time (APPROVAL_REQUIRED to APPROVED), and groups
+FAILED and INVOKE_FAILED events by normalized error message.
+
+Examples:
+  runagents runs stats
+  runagents runs stats --since 24h --agent billing-agent
+  runagents runs stats --since 2026-04-01 --until 2026-04-08 -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			now := time.Now().UTC()
+			var err error
+			if filters.Since, err = parseRunTimeBound(since, now); err != nil {
+				return fmt.Errorf("invalid --since: %w", err)
+			}
+			if filters.Until, err = parseRunTimeBound(until, now); err != nil {
+				return fmt.Errorf("invalid --until: %w", err)
+			}
+			if filters.Until.IsZero() {
+				filters.Until = now
+			}
+			if !filters.Since.IsZero() && !filters.Since.Before(filters.Until) {
+				return fmt.Errorf("--since must be before --until")
+			}
+
+			c, err := newAPIClient(cmd.Context())
+			if err != nil {
+				return err
+			}
+			runs, _, err := fetchRuns(c, filters)
+			if err != nil {
+				return err
+			}
+
+			events := map[string][]cliRunEvent{}
+			if !skipEvents && len(runs) > 0 {
+				ids := make([]string, 0, len(runs))
+				for _, run := range runs {
+					ids = append(ids, run.ID)
+				}
+				if events, err = fetchRunEventsForRuns(cmd.Context(), c, ids, workers); err != nil {
+					return err
+				}
+			}
+
+			stats := buildRunStats(runs, events)
+			stats.Since = filters.Since
+			stats.Until = filters.Until
+			if topErrors > 0 && len(stats.Errors) > topErrors {
+				stats.Errors = stats.Errors[:topErrors]
+			}
+			if isJSONOutput() {
+				return printJSONValue(stats)
+			}
+			printRunStats(stats)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "7d", "Start of the window: RFC 3339 time, date, or duration ago (for example 24h or 7d)")
+	cmd.Flags().StringVar(&until, "until", "", "End of the window, in the same formats as --since (default now)")
+	cmd.Flags().StringVar(&filters.AgentID, "agent", "", "Only include runs of this agent")
+	cmd.Flags().StringVar(&filters.UserID, "user", "", "Only include runs for this user ID")
+	cmd.Flags().StringVar(&filters.Status, "status", "", "Only include runs with this status")
+	cmd.Flags().BoolVar(&skipEvents, "skip-events", false, "Do not fetch run events (no approval waits or error groups)")
+	cmd.Flags().IntVar(&workers, "concurrency", 8, "Maximum number of run event requests in flight")
+	cmd.Flags().IntVar(&topErrors, "top-errors", 10, "Maximum number of error groups to report (0 for all)")
+	return cmd
+}
+
+// buildRunStats aggregates runs and, when available, their events keyed by run ID.
+func buildRunStats(runs []cliRun, events map[string][]cliRunEvent) runStats {
+	overall := &runStatsGroup{}
+	byAgent := map[string]*runStatsGroup{}
+	byUser := map[string]*runStatsGroup{}
+	byDay := map[string]*runStatsGroup{}
+	byStatus := map[string]int{}
+	errorGroups := map[string]*runErrorStatGroup{}
+
+	for _, run := range runs {
+		recorded, fetched := events[run.ID]
+		runEvents := sortedRunEvents(recorded)
+		groups := []*runStatsGroup{
+			overall,
+			runStatsGroupFor(byAgent, emptyFallback(run.AgentID, "(none)")),
+			runStatsGroupFor(byUser, emptyFallback(run.UserID, "(none)")),
+			runStatsGroupFor(byDay, runStartTime(run).UTC().Format("2006-01-02")),
+		}
+		duration, hasDuration := runDuration(run, runEvents)
+		waits := runApprovalWaits(runEvents)
+		status := strings.ToUpper(strings.TrimSpace(run.Status))
+		for _, group := range groups {
+			group.Runs++
+			switch status {
+			case "COMPLETED":
+				group.Completed++
+			case "FAILED":
+				group.Failed++
+			}
+			if hasDuration {
+				group.durations = append(group.durations, duration)
+			}
+			group.approvalWaits = append(group.approvalWaits, waits...)
+		}
+		byStatus[emptyFallback(status, "UNKNOWN")]++
+
+		for _, failure := range runFailureMessages(run, runEvents, fetched) {
+			key := normalizeRunError(failure.message)
+			group, ok := errorGroups[key]
+			if !ok {
+				group = &runErrorStatGroup{Error: key, Example: failure.message, ExampleID: run.ID}
+				errorGroups[key] = group
+			}
+			group.Count++
+			if run.AgentID != "" && !slices.Contains(group.Agents, run.AgentID) {
+				group.Agents = append(group.Agents, run.AgentID)
+			}
+			if failure.at.After(group.LastSeen) {
+				group.LastSeen = failure.at
+				group.Example = failure.message
+				group.ExampleID = run.ID
+			}
+		}
+	}
+
+	stats := runStats{
+		Overall:  finishRunStatsGroup(*overall),
+		ByAgent:  finishRunStatsGroups(byAgent, false),
+		ByUser:   finishRunStatsGroups(byUser, false),
+		ByDay:    finishRunStatsGroups(byDay, true),
+		ByStatus: []runStatusCount{},
+		Errors:   []runErrorStatGroup{},
+	}
+	for status, count := range byStatus {
+		stats.ByStatus = append(stats.ByStatus, runStatusCount{Status: status, Runs: count})
+	}
+	sort.Slice(stats.ByStatus, func(i, j int) bool {
+		if stats.ByStatus[i].Runs != stats.ByStatus[j].Runs {
+			return stats.ByStatus[i].Runs > stats.ByStatus[j].Runs
+		}
+		return stats.ByStatus[i].Status < stats.ByStatus[j].Status
+	})
+	for _, group := range errorGroups {
+		sort.Strings(group.Agents)
+		if group.Agents == nil {
+			group.Agents = []string{}
+		}
+		stats.Errors = append(stats.Errors, *group)
+	}
+	sort.Slice(stats.Errors, func(i, j int) bool {
+		if stats.Errors[i].Count != stats.Errors[j].Count {
+			return stats.Errors[i].Count > stats.Errors[j].Count
+		}
+		return stats.Errors[i].Error < stats.Errors[j].Error
+	})
+	return stats
+}
+
+func runStatsGroupFor(groups map[string]*runStatsGroup, key string) *runStatsGroup {
+	group, ok := groups[key]
+	if !ok {
+		group = &runStatsGroup{Key: key}
+		groups[key] = group
+	}
+	return group
+}
+
+// finishRunStatsGroups computes each group's rates and percentiles, ordered by
+// key when byKey is set and by run count otherwise.
+func finishRunStatsGroups(groups map[string]*runStatsGroup, byKey bool) []runStatsGroup {
+	finished := make([]runStatsGroup, 0, len(groups))
+	for _, group := range groups {
+		finished = append(finished, finishRunStatsGroup(*group))
+	}
+	sort.Slice(finished, func(i, j int) bool {
+		if !byKey && finished[i].Runs != finished[j].Runs {
+			return finished[i].Runs > finished[j].Runs
+		}
+		return finished[i].Key < finished[j].Key
+	})
+	return finished
+}
+
+func finishRunStatsGroup(group runStatsGroup) runStatsGroup {
+	if terminal := group.Completed + group.Failed; terminal > 0 {
+		group.SuccessRate = float64(group.Completed) / float64(terminal)
+	}
+	group.MedianDurationSeconds = durationPercentile(group.durations, 50).Seconds()
+	group.P95DurationSeconds = durationPercentile(group.durations, 95).Seconds()
+	group.Approvals = len(group.approvalWaits)
+	group.MedianApprovalWaitSeconds = durationPercentile(group.approvalWaits, 50).Seconds()
+	group.P95ApprovalWaitSeconds = durationPercentile(group.approvalWaits, 95).Seconds()
+	return group
+}
+
+// durationPercentile returns the nearest-rank percentile p of values.
+func durationPercentile(values []time.Duration, p float64) time.Duration {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func sortedRunEvents(events []cliRunEvent) []cliRunEvent {
+	sorted := append([]cliRunEvent(nil), events...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Seq < sorted[j].Seq })
+	return sorted
+}
+
+// runDuration measures a terminal run from its creation (or first event) to
+// its last event (or last update). Runs still in progress have no duration.
+func runDuration(run cliRun, events []cliRunEvent) (time.Duration, bool) {
+	if !isTerminalRunStatus(run.Status) {
+		return 0, false
+	}
+	start, end := run.CreatedAt, run.UpdatedAt
+	for _, event := range events {
+		if event.Timestamp.IsZero() {
+			continue
+		}
+		if start.IsZero() || event.Timestamp.Before(start) {
+			start = event.Timestamp
+		}
+		if event.Timestamp.After(end) {
+			end = event.Timestamp
+		}
+	}
+	if start.IsZero() || end.IsZero() || end.Before(start) {
+		return 0, false
+	}
+	return end.Sub(start), true
+}
+
+// runApprovalWaits pairs each APPROVED event with the APPROVAL_REQUIRED event
+// for the same action (or the oldest open request when no action ID is
+// recorded) and returns the time between them.
+func runApprovalWaits(events []cliRunEvent) []time.Duration {
+	var (
+		waits   []time.Duration
+		pending []cliRunEvent
+	)
+	for _, event := range events {
+		switch strings.ToUpper(event.Type) {
+		case "APPROVAL_REQUIRED":
+			pending = append(pending, event)
+		case "APPROVED":
+			if len(pending) == 0 {
+				continue
+			}
+			match := 0
+			if actionID := runEventActionID(event); actionID != "" {
+				for i, request := range pending {
+					if runEventActionID(request) == actionID {
+						match = i
+						break
+					}
+				}
+			}
+			request := pending[match]
+			pending = append(pending[:match], pending[match+1:]...)
+			if !request.Timestamp.IsZero() && !event.Timestamp.Before(request.Timestamp) {
+				waits = append(waits, event.Timestamp.Sub(request.Timestamp))
+			}
+		}
+	}
+	return waits
+}
+
+func runEventActionID(event cliRunEvent) string {
+	return firstNonEmptyRunValue(dataString(event.Data, "action_id"), dataString(event.Data, "blocked_action_id"))
+}
+
+type runFailure struct {
+	message string
+	at      time.Time
+}
+
+// runFailureMessages returns the error messages of the run's FAILED and
+// INVOKE_FAILED events. A failed run whose events were fetched but record no
+// failure still counts once, so it is not silently left out of the groups.
+func runFailureMessages(run cliRun, events []cliRunEvent, fetched bool) []runFailure {
+	var failures []runFailure
+	for _, event := range events {
+		switch strings.ToUpper(event.Type) {
+		case "FAILED", "INVOKE_FAILED":
+			failures = append(failures, runFailure{message: summarizeRunEvent(event), at: event.Timestamp})
+		}
+	}
+	if len(failures) == 0 && fetched && strings.EqualFold(run.Status, "FAILED") {
+		failures = append(failures, runFailure{message: "(no error recorded)", at: run.UpdatedAt})
+	}
+	return failures
+}
+
+var (
+	runErrorTimestampPattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`)
+	runErrorUUIDPattern      = regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`)
+	runErrorULIDPattern      = regexp.MustCompile(`\b[0-9A-HJKMNP-TV-Z]{26}\b`)
+	runErrorHexPattern       = regexp.MustCompile(`(?i)\b(0x)?[0-9a-f]{16,}\b`)
+	runErrorNumberPattern    = regexp.MustCompile(`\b\d+\.\d+\w*|\b\d{4,}\b`)
+	runErrorSpacePattern     = regexp.MustCompile(`\s+`)
+)
+
+// normalizeRunError masks the parts of an error message that vary between
+// occurrences (timestamps, IDs, hashes, long numbers, durations) so that
+// repeats of the same failure group together. Short numbers such as HTTP
+// status codes are kept.
+func normalizeRunError(message string) string {
+	message = runErrorTimestampPattern.ReplaceAllString(message, "<time>")
+	message = runErrorUUIDPattern.ReplaceAllString(message, "<id>")
+	message = runErrorULIDPattern.ReplaceAllString(message, "<id>")
+	message = runErrorHexPattern.ReplaceAllString(message, "<hex>")
+	message = runErrorNumberPattern.ReplaceAllString(message, "<n>")
+	message = strings.TrimSpace(runErrorSpacePattern.ReplaceAllString(message, " "))
+	return emptyFallback(message, "(empty error)")
+}
+
+func printRunStats(stats runStats) {
+	overall := stats.Overall
+	window := formatRunTime(stats.Until)
+	if !stats.Since.IsZero() {
+		window = formatRunTime(stats.Since) + " to " + window
+	} else {
+		window = "until " + window
+	}
+	fmt.Printf("Runs created %s: %d (%d completed, %d failed, %d other)\n",
+		window, overall.Runs, overall.Completed, overall.Failed, overall.Runs-overall.Completed-overall.Failed)
+	if overall.Runs == 0 {
+		return
+	}
+	fmt.Printf("Success rate: %s   Duration median/p95: %s / %s   Approval wait median/p95: %s / %s (%d approvals)\n",
+		formatSuccessRate(overall),
+		formatStatSeconds(overall.MedianDurationSeconds, len(overall.durations)), formatStatSeconds(overall.P95DurationSeconds, len(overall.durations)),
+		formatStatSeconds(overall.MedianApprovalWaitSeconds, overall.Approvals), formatStatSeconds(overall.P95ApprovalWaitSeconds, overall.Approvals),
+		overall.Approvals)
+
+	printRunStatsGroups("By agent:", "AGENT", stats.ByAgent)
+	printRunStatsGroups("By user:", "USER", stats.ByUser)
+
+	fmt.Println()
+	fmt.Println("By status:")
+	statusTable := newTable("STATUS", "RUNS")
+	for _, status := range stats.ByStatus {
+		statusTable.Append([]string{status.Status, fmt.Sprintf("%d", status.Runs)})
+	}
+	statusTable.Render()
+
+	printRunStatsGroups("By day (UTC):", "DAY", stats.ByDay)
+
+	if len(stats.Errors) == 0 {
+		return
+	}
+	fmt.Println()
+	fmt.Println("Top errors:")
+	errorTable := newTable("COUNT", "ERROR", "AGENTS", "LAST SEEN", "EXAMPLE RUN")
+	for _, group := range stats.Errors {
+		errorTable.Append([]string{
+			fmt.Sprintf("%d", group.Count),
+			truncateRunMessage(group.Error, 80),
+			strings.Join(group.Agents, ", "),
+			formatRunTime(group.LastSeen),
+			group.ExampleID,
+		})
+	}
+	errorTable.Render()
+}
+
+func printRunStatsGroups(title, keyHeader string, groups []runStatsGroup) {
+	fmt.Println()
+	fmt.Println(title)
+	table := newTable(keyHeader, "RUNS", "COMPLETED", "FAILED", "SUCCESS", "P50 DURATION", "P95 DURATION", "P50 APPROVAL WAIT")
+	for _, group := range groups {
+		table.Append([]string{
+			group.Key,
+			fmt.Sprintf("%d", group.Runs),
+			fmt.Sprintf("%d", group.Completed),
+			fmt.Sprintf("%d", group.Failed),
+			formatSuccessRate(group),
+			formatStatSeconds(group.MedianDurationSeconds, len(group.durations)),
+			formatStatSeconds(group.P95DurationSeconds, len(group.durations)),
+			formatStatSeconds(group.MedianApprovalWaitSeconds, group.Approvals),
+		})
+	}
+	table.Render()
+}
+
+func formatSuccessRate(group runStatsGroup) string {
+	if group.Completed+group.Failed == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", group.SuccessRate*100)
+}
+
+func formatStatSeconds(seconds float64, samples int) string {
+	if samples == 0 {
+		return "-"
+	}
+	d := time.Duration(seconds * float64(time.Second))
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(time.Second).String()
+}
@@ -0,0 +1,171 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/runagents/runagents/cli/internal/client"
+)
+
+func TestBuildRunStatsAggregatesOutcomesDurationsAndApprovalWaits(t *testing.T) {
+	day := time.Date(2026, 4, 9, 10, 0, 0, 0, time.UTC)
+	runs := []cliRun{
+		{ID: "run-1", AgentID: "billing", UserID: "alice", Status: "COMPLETED", CreatedAt: day, UpdatedAt: day.Add(10 * time.Second)},
+		{ID: "run-2", AgentID: "billing", UserID: "bob", Status: "FAILED", CreatedAt: day.Add(time.Hour), UpdatedAt: day.Add(time.Hour + 30*time.Second)},
+		{ID: "run-3", AgentID: "support", UserID: "alice", Status: "PAUSED_APPROVAL", CreatedAt: day.AddDate(0, 0, 1)},
+	}
+	events := map[string][]cliRunEvent{
+		"run-1": {
+			{Seq: 2, Type: "APPROVED", Timestamp: day.Add(5 * time.Second), Data: map[string]any{"action_id": "act-1"}},
+			{Seq: 1, Type: "APPROVAL_REQUIRED", Timestamp: day.Add(2 * time.Second), Data: map[string]any{"action_id": "act-1"}},
+		},
+		"run-2": {
+			{Seq: 1, Type: "INVOKE_FAILED", Timestamp: day.Add(time.Hour + 40*time.Second), Data: map[string]any{"error": "upstream returned HTTP 502 after 1.5s"}},
+		},
+		"run-3": {},
+	}
+
+	stats := buildRunStats(runs, events)
+
+	overall := stats.Overall
+	if overall.Runs != 3 || overall.Completed != 1 || overall.Failed != 1 {
+		t.Fatalf("unexpected overall counts: %+v", overall)
+	}
+	if overall.SuccessRate != 0.5 {
+		t.Fatalf("expected 50%% success rate, got %v", overall.SuccessRate)
+	}
+	if overall.MedianDurationSeconds != 10 || overall.P95DurationSeconds != 40 {
+		t.Fatalf("unexpected durations: median %v p95 %v", overall.MedianDurationSeconds, overall.P95DurationSeconds)
+	}
+	if overall.Approvals != 1 || overall.MedianApprovalWaitSeconds != 3 {
+		t.Fatalf("unexpected approval waits: %+v", overall)
+	}
+	if len(stats.ByAgent) != 2 || stats.ByAgent[0].Key != "billing" || stats.ByAgent[0].Runs != 2 {
+		t.Fatalf("unexpected agent groups: %+v", stats.ByAgent)
+	}
+	if len(stats.ByDay) != 2 || stats.ByDay[0].Key != "2026-04-09" || stats.ByDay[1].Key != "2026-04-10" {
+		t.Fatalf("unexpected day groups: %+v", stats.ByDay)
+	}
+	if len(stats.Errors) != 1 || stats.Errors[0].Error != "upstream returned HTTP 502 after <n>" || stats.Errors[0].ExampleID != "run-2" {
+		t.Fatalf("unexpected error groups: %+v", stats.Errors)
+	}
+}
+
+func TestNormalizeRunErrorGroupsVaryingDetails(t *testing.T) {
+	first := normalizeRunError("tool call 01HQXYZ1234567890ABCDEFGHJ failed at 2026-04-09T10:00:00Z: HTTP 503")
+	second := normalizeRunError("tool call  01HQXYZ1234567890ABCDEFGHK failed at 2026-04-10T11:30:00Z: HTTP 503")
+	if first != second {
+		t.Fatalf("expected messages to normalize alike, got %q and %q", first, second)
+	}
+	if first != "tool call <id> failed at <time>: HTTP 503" {
+		t.Fatalf("unexpected normalized message %q", first)
+	}
+}
+
+func TestParseRunTimeBound(t *testing.T) {
+	now := time.Date(2026, 4, 9, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{value: "", want: time.Time{}},
+		{value: "24h", want: now.Add(-24 * time.Hour)},
+		{value: "7d", want: now.AddDate(0, 0, -7)},
+		{value: "2026-04-01", want: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{value: "2026-04-01T08:00:00Z", want: time.Date(2026, 4, 1, 8, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := parseRunTimeBound(tt.value, now)
+		if err != nil {
+			t.Fatalf("parseRunTimeBound(%q) returned error: %v", tt.value, err)
+		}
+		if !got.Equal(tt.want) {
+			t.Fatalf("parseRunTimeBound(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+	if _, err := parseRunTimeBound("last week", now); err == nil {
+		t.Fatal("expected error for unparseable time")
+	}
+}
+
+func TestFetchRunEventsForRunsReturnsEventsPerRun(t *testing.T) {
+	objects := map[string]any{}
+	ids := []string{}
+	for i := 1; i <= 5; i++ {
+		id := fmt.Sprintf("run-%d", i)
+		ids = append(ids, id)
+		objects["/runs/"+id+"/events"] = []cliRunEvent{{RunID: id, Seq: 1, Type: "RUN_CREATED"}}
+	}
+	fake := &fakeRunsClient{objects: objects}
+
+	events, err := fetchRunEventsForRuns(context.Background(), fake, ids, 2)
+	if err != nil {
+		t.Fatalf("fetchRunEventsForRuns returned error: %v", err)
+	}
+	for _, id := range ids {
+		if len(events[id]) != 1 || events[id][0].RunID != id {
+			t.Fatalf("unexpected events for %s: %+v", id, events[id])
+		}
+	}
+
+	if _, err := fetchRunEventsForRuns(context.Background(), fake, append(ids, "run-missing"), 2); err == nil {
+		t.Fatal("expected error when a run's events cannot be fetched")
+	}
+}
+
+func TestFetchRunEventsPagesWithAfterSeq(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		afterSeq, _ := strconv.Atoi(r.URL.Query().Get("after_seq"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		events := []cliRunEvent{}
+		for seq := afterSeq + 1; seq <= 250 && len(events) < limit; seq++ {
+			events = append(events, cliRunEvent{Seq: seq, Type: "TOOL_REQUEST"})
+		}
+		_ = json.NewEncoder(w).Encode(events)
+	}))
+	defer server.Close()
+
+	events, err := fetchRunEvents(client.NewClient(server.URL, "ra_ws_test"), "run-1", 0)
+	if err != nil {
+		t.Fatalf("fetchRunEvents: %v", err)
+	}
+	if len(events) != 250 || events[249].Seq != 250 {
+		t.Fatalf("expected all 250 events, got %d", len(events))
+	}
+	if got := strings.Join(queries, " "); got != "limit=100 after_seq=100&limit=100 after_seq=200&limit=100" {
+		t.Fatalf("unexpected event page queries %q", got)
+	}
+}
+
+func TestFetchRunEventsForRunsCancelsInFlightRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "run-missing") {
+			http.Error(w, `{"error":"not found"}`, http.StatusNotFound)
+			return
+		}
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+		_, _ = w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	started := time.Now()
+	_, err := fetchRunEventsForRuns(context.Background(), client.NewClient(server.URL, "ra_ws_test"), []string{"run-slow", "run-missing"}, 2)
+	if err == nil || !strings.Contains(err.Error(), "run-missing") {
+		t.Fatalf("expected the missing run's error, got %v", err)
+	}
+	if elapsed := time.Since(started); elapsed > 2*time.Second {
+		t.Fatalf("expected the slow request to be cancelled, took %s", elapsed)
+	}
+}
@@ -1,10 +1,58 @@
 package commands
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/runagents/runagents/cli/internal/client"
 )
 
+// fakeRunsClient serves canned objects by path and pages of list items.
+type fakeRunsClient struct {
+	objects map[string]any
+	pages   map[string][][]any
+	mu      sync.Mutex
+	queries []url.Values
+}
+
+func (f *fakeRunsClient) Get(path string) ([]byte, error) {
+	return f.GetWithQuery(path, nil)
+}
+
+func (f *fakeRunsClient) GetWithQuery(path string, query url.Values) ([]byte, error) {
+	f.mu.Lock()
+	f.queries = append(f.queries, query)
+	f.mu.Unlock()
+	obj, ok := f.objects[path]
+	if !ok {
+		return nil, &client.APIError{StatusCode: http.StatusNotFound, Message: "not found"}
+	}
+	return json.Marshal(obj)
+}
+
+func (f *fakeRunsClient) Paginate(path string, query url.Values, fn func([]json.RawMessage) (bool, error)) error {
+	f.queries = append(f.queries, query)
+	for _, page := range f.pages[path] {
+		items := make([]json.RawMessage, 0, len(page))
+		for _, item := range page {
+			data, err := json.Marshal(item)
+			if err != nil {
+				return err
+			}
+			items = append(items, data)
+		}
+		more, err := fn(items)
+		if err != nil || !more {
+			return err
+		}
+	}
+	return nil
+}
+
 func TestFilterRunsAppliesClientSideFiltersSortAndLimit(t *testing.T) {
 	now := time.Date(2026, 4, 9, 10, 0, 0, 0, time.UTC)
 	runs := []cliRun{
@@ -22,6 +70,47 @@ func TestFilterRunsAppliesClientSideFiltersSortAndLimit(t *testing.T) {
 	}
 }
 
+func TestFetchRunsPushesFiltersAndStopsPagingAtLimit(t *testing.T) {
+	now := time.Date(2026, 4, 9, 10, 0, 0, 0, time.UTC)
+	fake := &fakeRunsClient{pages: map[string][][]any{
+		"/runs": {
+			{
+				cliRun{ID: "run-1", UserID: "alice@example.com", UpdatedAt: now.Add(-1 * time.Minute)},
+				cliRun{ID: "run-2", UserID: "bob@example.com", UpdatedAt: now.Add(-2 * time.Minute)},
+			},
+			{
+				cliRun{ID: "run-3", UserID: "alice@example.com", UpdatedAt: now.Add(-3 * time.Minute)},
+			},
+			{
+				cliRun{ID: "run-4", UserID: "alice@example.com", UpdatedAt: now.Add(-4 * time.Minute)},
+			},
+		},
+	}}
+
+	runs, truncated, err := fetchRuns(fake, runFilters{UserID: "alice@example.com", Status: "FAILED", Limit: 2})
+	if err != nil {
+		t.Fatalf("fetchRuns: %v", err)
+	}
+	if len(runs) != 2 || runs[0].ID != "run-1" || runs[1].ID != "run-3" {
+		t.Fatalf("expected alice's two newest runs, got %#v", runs)
+	}
+	if !truncated {
+		t.Fatalf("expected truncated result when paging stops early")
+	}
+	query := fake.queries[0]
+	if query.Get("user_id") != "alice@example.com" || query.Get("status") != "FAILED" || query.Get("limit") != "2" {
+		t.Fatalf("expected filters to be pushed to the server, got %v", query)
+	}
+
+	runs, truncated, err = fetchRuns(fake, runFilters{UserID: "alice@example.com"})
+	if err != nil {
+		t.Fatalf("fetchRuns: %v", err)
+	}
+	if len(runs) != 3 || truncated {
+		t.Fatalf("expected every page without a limit, got %d runs (truncated %v)", len(runs), truncated)
+	}
+}
+
 func TestFilterRunEventsAppliesTypeAndTailLimit(t *testing.T) {
 	events := []cliRunEvent{
 		{Seq: 1, Type: "TOOL_REQUEST"},
@@ -92,3 +181,37 @@ func TestIsTerminalRunStatus(t *testing.T) {
 		t.Fatalf("expected PAUSED_APPROVAL to be non-terminal")
 	}
 }
+
+func TestFetchRunsStopsPagingBeforeSince(t *testing.T) {
+	now := time.Date(2026, 4, 9, 10, 0, 0, 0, time.UTC)
+	fake := &fakeRunsClient{pages: map[string][][]any{"/runs": {
+		{cliRun{ID: "run-4", CreatedAt: now.Add(-time.Hour)}, cliRun{ID: "run-3", CreatedAt: now.Add(-2 * time.Hour)}},
+		{cliRun{ID: "run-2", CreatedAt: now.Add(-30 * time.Hour)}, cliRun{ID: "run-1", CreatedAt: now.Add(-40 * time.Hour)}},
+		{cliRun{ID: "run-0", CreatedAt: now.Add(-50 * time.Hour)}, cliRun{ID: "run-never", CreatedAt: now}},
+	}}}
+	pages := 0
+	counting := &pageCountingRunsClient{fakeRunsClient: fake, pages: &pages}
+
+	runs, _, err := fetchRuns(counting, runFilters{Since: now.Add(-24 * time.Hour)})
+	if err != nil {
+		t.Fatalf("fetchRuns: %v", err)
+	}
+	if len(runs) != 2 || pages != 2 {
+		t.Fatalf("expected two runs from two pages, got %d runs from %d pages", len(runs), pages)
+	}
+	if got := fake.queries[0].Get("created_after"); got != "2026-04-08T10:00:00Z" {
+		t.Fatalf("expected --since to be sent as created_after, got %q", got)
+	}
+}
+
+type pageCountingRunsClient struct {
+	*fakeRunsClient
+	pages *int
+}
+
+func (c *pageCountingRunsClient) Paginate(path string, query url.Values, fn func([]json.RawMessage) (bool, error)) error {
+	return c.fakeRunsClient.Paginate(path, query, func(items []json.RawMessage) (bool, error) {
+		*c.pages++
+		return fn(items)
+	})
+}
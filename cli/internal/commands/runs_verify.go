@@ -0,0 +1,458 @@
+package commands
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// cliRunExportIntegrity seals a run export. Digest is the SHA-256 of the
+// canonical JSON of the export without this field, and Signature is an
+// Ed25519 signature over the same bytes.
+type cliRunExportIntegrity struct {
+	Algorithm string    `json:"algorithm"`
+	Digest    string    `json:"digest"`
+	Signature string    `json:"signature"`
+	PublicKey string    `json:"public_key"`
+	SignedAt  time.Time `json:"signed_at"`
+}
+
+// runVerification is the result of checking a run's event stream and, for
+// export bundles, the bundle seal.
+type runVerification struct {
+	RunID              string                   `json:"run_id"`
+	Source             string                   `json:"source"`
+	Events             int                      `json:"events"`
+	HashesVerified     int                      `json:"hashes_verified"`
+	HashesUnverifiable int                      `json:"hashes_unverifiable"`
+	EventsWithoutHash  int                      `json:"events_without_hash"`
+	Signature          string                   `json:"signature,omitempty"`
+	Findings           []runVerificationFinding `json:"findings"`
+	OK                 bool                     `json:"ok"`
+}
+
+type runVerificationFinding struct {
+	Seq      int    `json:"seq,omitempty"`
+	EventID  string `json:"event_id,omitempty"`
+	Check    string `json:"check"`
+	Severity string `json:"severity"`
+	Detail   string `json:"detail"`
+}
+
+func newRunsVerifyCmd() *cobra.Command {
+	var (
+		bundle    string
+		publicKey string
+	)
+	cmd := &cobra.Command{
+		Use:   "verify [run-id]",
+		Short: "Check a run's event stream, or an export bundle, for tampering",
+		Long: `Check the integrity of a run's events: recompute each event's payload hash,
+and detect gaps, duplicates and other inconsistencies in the event sequence.
+
+With --bundle, the same checks run offline against a file written by
+'runs export'. If the bundle was signed with 'runs export --sign-key', its
+digest and signature are verified too; pass --public-key to check the
+signature against a key you trust rather than the one embedded in the bundle.
+
+Exits with code 8 when any check fails.
+
+Examples:
+  runagents runs verify <run-id>
+  runagents runs verify --bundle run-export.json --public-key signer.pub.pem`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if (len(args) == 1) == (bundle != "") {
+				return fmt.Errorf("pass either a run ID or --bundle")
+			}
+
+			var result runVerification
+			if bundle != "" {
+				data, err := os.ReadFile(bundle)
+				if err != nil {
+					return fmt.Errorf("failed to read bundle: %w", err)
+				}
+				var trusted ed25519.PublicKey
+				if publicKey != "" {
+					if trusted, err = loadEd25519PublicKey(publicKey); err != nil {
+						return err
+					}
+				}
+				if result, err = verifyRunExportBundle(data, trusted); err != nil {
+					return err
+				}
+				result.Source = bundle
+			} else {
+				if publicKey != "" {
+					return fmt.Errorf("--public-key only applies to --bundle")
+				}
+				c, err := newAPIClient(cmd.Context())
+				if err != nil {
+					return err
+				}
+				events, err := fetchRunEvents(c, args[0], 0)
+				if err != nil {
+					return err
+				}
+				result = verifyRunEvents(args[0], events)
+				result.Source = "api"
+			}
+
+			if isJSONOutput() {
+				if err := printJSONValue(result); err != nil {
+					return err
+				}
+			} else {
+				printRunVerification(result)
+			}
+			if !result.OK {
+				return &exitCodeError{
+					code: exitCodeVerificationFailed,
+					err:  fmt.Errorf("verification of run %s failed with %d error(s)", result.RunID, countRunVerificationErrors(result)),
+				}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&bundle, "bundle", "", "Verify a file written by 'runs export' instead of fetching the run")
+	cmd.Flags().StringVar(&publicKey, "public-key", "", "PEM Ed25519 public key to check the bundle signature against")
+	return cmd
+}
+
+// verifyRunEvents checks payload hashes and the sequence of runID's events.
+func verifyRunEvents(runID string, events []cliRunEvent) runVerification {
+	result := runVerification{RunID: runID, Events: len(events), Findings: []runVerificationFinding{}}
+	addFinding := func(event cliRunEvent, check, severity, detail string) {
+		result.Findings = append(result.Findings, runVerificationFinding{
+			Seq:      event.Seq,
+			EventID:  event.EventID,
+			Check:    check,
+			Severity: severity,
+			Detail:   detail,
+		})
+	}
+
+	sorted := sortedRunEvents(events)
+	seenIDs := map[string]int{}
+	for i, event := range sorted {
+		if event.RunID != "" && event.RunID != runID {
+			addFinding(event, "run_id", "error", fmt.Sprintf("event belongs to run %s", event.RunID))
+		}
+		if event.EventID != "" {
+			if seq, ok := seenIDs[event.EventID]; ok {
+				addFinding(event, "duplicate_event_id", "error", fmt.Sprintf("event ID already recorded at seq %d", seq))
+			}
+			seenIDs[event.EventID] = event.Seq
+		}
+
+		if i == 0 {
+			if event.Seq > 1 {
+				addFinding(event, "seq_gap", "error", describeSeqGap(1, event.Seq-1))
+			}
+		} else {
+			previous := sorted[i-1]
+			switch {
+			case event.Seq == previous.Seq:
+				addFinding(event, "duplicate_seq", "error", fmt.Sprintf("seq %d recorded more than once", event.Seq))
+			case event.Seq > previous.Seq+1:
+				addFinding(event, "seq_gap", "error", describeSeqGap(previous.Seq+1, event.Seq-1))
+			}
+			if !event.Timestamp.IsZero() && event.Timestamp.Before(previous.Timestamp) {
+				addFinding(event, "timestamp_order", "warning", fmt.Sprintf("timestamp %s is earlier than seq %d", formatRunTime(event.Timestamp), previous.Seq))
+			}
+		}
+
+		if event.PayloadHash == "" {
+			result.EventsWithoutHash++
+			continue
+		}
+		verified, checkable, err := verifyRunEventHash(event)
+		switch {
+		case err != nil:
+			addFinding(event, "payload_hash", "warning", err.Error())
+			result.HashesUnverifiable++
+		case !checkable:
+			result.HashesUnverifiable++
+		case verified:
+			result.HashesVerified++
+		default:
+			// The hash usually covers the tool-request payload, which is not
+			// part of the event, so a mismatch is not evidence of tampering.
+			addFinding(event, "payload_hash", "warning", fmt.Sprintf("%s does not match the event data; the hashed payload is not recorded, so it cannot be verified", event.PayloadHash))
+			result.HashesUnverifiable++
+		}
+	}
+
+	result.OK = countRunVerificationErrors(result) == 0
+	return result
+}
+
+func describeSeqGap(from, to int) string {
+	if from == to {
+		return fmt.Sprintf("seq %d is missing", from)
+	}
+	return fmt.Sprintf("seq %d-%d are missing", from, to)
+}
+
+// verifyRunEventHash tries to recompute an event's payload hash. The API
+// does not specify the hash input; for tool requests it is the request
+// payload, which events do not carry. The hash is accepted when it is the
+// SHA-256 of the canonical JSON of the event data (keys sorted, no
+// insignificant whitespace) or of a "payload" or "body" value in it.
+// checkable is false when the event has no data to hash; a false verified
+// with checkable true means none of those inputs matched.
+func verifyRunEventHash(event cliRunEvent) (verified, checkable bool, err error) {
+	algorithm, expected, ok := strings.Cut(event.PayloadHash, ":")
+	if !ok {
+		algorithm, expected = "sha256", event.PayloadHash
+	}
+	if !strings.EqualFold(algorithm, "sha256") {
+		return false, false, fmt.Errorf("unsupported hash algorithm %q", algorithm)
+	}
+	expected = strings.ToLower(strings.TrimSpace(expected))
+	if len(event.Data) == 0 {
+		return false, false, nil
+	}
+
+	candidates := []any{event.Data}
+	for _, key := range []string{"payload", "body"} {
+		if value, ok := event.Data[key]; ok && value != nil {
+			candidates = append(candidates, value)
+		}
+	}
+	for _, candidate := range candidates {
+		var payloads [][]byte
+		if text, ok := candidate.(string); ok {
+			payloads = append(payloads, []byte(text))
+		}
+		if canonical, err := canonicalJSON(candidate); err == nil {
+			payloads = append(payloads, canonical)
+		}
+		for _, payload := range payloads {
+			sum := sha256.Sum256(payload)
+			if hex.EncodeToString(sum[:]) == expected {
+				return true, true, nil
+			}
+		}
+	}
+	return false, true, nil
+}
+
+// canonicalJSON encodes v as compact JSON with object keys sorted and numbers
+// written as they appear, so the same document always yields the same bytes.
+func canonicalJSON(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return canonicalizeJSON(data)
+}
+
+func canonicalizeJSON(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+// runExportDigest returns the canonical bytes of an export bundle without its
+// integrity field, and their SHA-256 digest.
+func runExportDigest(data []byte) ([]byte, string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var document map[string]any
+	if err := decoder.Decode(&document); err != nil {
+		return nil, "", fmt.Errorf("failed to parse bundle: %w", err)
+	}
+	delete(document, "integrity")
+	canonical, err := json.Marshal(document)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode bundle: %w", err)
+	}
+	sum := sha256.Sum256(canonical)
+	return canonical, "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// signRunExport seals export with key.
+func signRunExport(export *cliRunExport, key ed25519.PrivateKey, now time.Time) error {
+	export.Integrity = nil
+	data, err := json.Marshal(export)
+	if err != nil {
+		return fmt.Errorf("failed to encode export: %w", err)
+	}
+	canonical, digest, err := runExportDigest(data)
+	if err != nil {
+		return err
+	}
+	export.Integrity = &cliRunExportIntegrity{
+		Algorithm: "ed25519",
+		Digest:    digest,
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(key, canonical)),
+		PublicKey: base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)),
+		SignedAt:  now,
+	}
+	return nil
+}
+
+// verifyRunExportBundle checks the events in an export bundle and, when the
+// bundle is sealed, its digest and signature. The signature is checked with
+// trusted when given, otherwise with the key embedded in the bundle.
+func verifyRunExportBundle(data []byte, trusted ed25519.PublicKey) (runVerification, error) {
+	var export cliRunExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return runVerification{}, fmt.Errorf("failed to parse bundle: %w", err)
+	}
+	if export.Run.ID == "" {
+		return runVerification{}, fmt.Errorf("bundle has no run; expected a file written by 'runs export'")
+	}
+	result := verifyRunEvents(export.Run.ID, export.Events)
+	addFinding := func(check, severity, detail string) {
+		result.Findings = append(result.Findings, runVerificationFinding{Check: check, Severity: severity, Detail: detail})
+	}
+
+	integrity := export.Integrity
+	switch {
+	case integrity == nil:
+		result.Signature = "unsigned"
+		if trusted != nil {
+			addFinding("signature", "error", "bundle is not signed")
+		}
+	case !strings.EqualFold(integrity.Algorithm, "ed25519"):
+		result.Signature = "unsupported"
+		addFinding("signature", "error", fmt.Sprintf("unsupported signature algorithm %q", integrity.Algorithm))
+	default:
+		canonical, digest, err := runExportDigest(data)
+		if err != nil {
+			return runVerification{}, err
+		}
+		if digest != integrity.Digest {
+			addFinding("bundle_digest", "error", fmt.Sprintf("bundle content does not match digest %s (computed %s)", integrity.Digest, digest))
+		}
+		key := trusted
+		if key == nil {
+			embedded, err := base64.StdEncoding.DecodeString(integrity.PublicKey)
+			if err != nil || len(embedded) != ed25519.PublicKeySize {
+				result.Signature = "invalid"
+				addFinding("signature", "error", "bundle has no valid public key; pass --public-key")
+				break
+			}
+			key = ed25519.PublicKey(embedded)
+		}
+		signature, err := base64.StdEncoding.DecodeString(integrity.Signature)
+		if err != nil || !ed25519.Verify(key, canonical, signature) {
+			result.Signature = "invalid"
+			addFinding("signature", "error", "signature does not match the bundle content")
+			break
+		}
+		if trusted != nil {
+			result.Signature = "valid"
+		} else {
+			result.Signature = "valid (embedded key)"
+			addFinding("signature", "warning", "checked against the key embedded in the bundle; pass --public-key to prove who signed it")
+		}
+	}
+
+	result.OK = countRunVerificationErrors(result) == 0
+	return result, nil
+}
+
+func countRunVerificationErrors(result runVerification) int {
+	count := 0
+	for _, finding := range result.Findings {
+		if finding.Severity == "error" {
+			count++
+		}
+	}
+	return count
+}
+
+func printRunVerification(result runVerification) {
+	if len(result.Findings) > 0 {
+		findings := append([]runVerificationFinding(nil), result.Findings...)
+		sort.SliceStable(findings, func(i, j int) bool { return findings[i].Seq < findings[j].Seq })
+		table := newTable("SEQ", "CHECK", "SEVERITY", "DETAIL")
+		for _, finding := range findings {
+			seq := ""
+			if finding.Seq > 0 {
+				seq = fmt.Sprintf("%d", finding.Seq)
+			}
+			table.Append([]string{seq, finding.Check, finding.Severity, finding.Detail})
+		}
+		table.Render()
+		fmt.Println()
+	}
+
+	fmt.Printf("Run %s: %d events, %d payload hashes verified, %d that could not be checked, %d events without a hash.\n",
+		result.RunID, result.Events, result.HashesVerified, result.HashesUnverifiable, result.EventsWithoutHash)
+	if result.Signature != "" {
+		fmt.Printf("Bundle signature: %s\n", result.Signature)
+	}
+	if result.OK {
+		fmt.Println("Verification passed.")
+	} else {
+		fmt.Printf("Verification failed: %d error(s).\n", countRunVerificationErrors(result))
+	}
+}
+
+func readPEMBlock(path, kind string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", kind, err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s %s is not PEM encoded", kind, path)
+	}
+	return block, nil
+}
+
+// loadEd25519PrivateKey reads a PKCS #8 PEM key, as written by
+// "openssl genpkey -algorithm ed25519".
+func loadEd25519PrivateKey(path string) (ed25519.PrivateKey, error) {
+	block, err := readPEMBlock(path, "signing key")
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing key %s: %w", path, err)
+	}
+	key, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("signing key %s is not an Ed25519 key", path)
+	}
+	return key, nil
+}
+
+// loadEd25519PublicKey reads a PKIX PEM public key, as written by
+// "openssl pkey -pubout".
+func loadEd25519PublicKey(path string) (ed25519.PublicKey, error) {
+	block, err := readPEMBlock(path, "public key")
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key %s: %w", path, err)
+	}
+	key, ok := parsed.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key %s is not an Ed25519 key", path)
+	}
+	return key, nil
+}
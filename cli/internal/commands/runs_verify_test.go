@@ -0,0 +1,137 @@
+package commands
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func hashRunEventData(t *testing.T, data map[string]any) string {
+	t.Helper()
+	canonical, err := canonicalJSON(data)
+	if err != nil {
+		t.Fatalf("canonicalJSON returned error: %v", err)
+	}
+	sum := sha256.Sum256(canonical)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func TestVerifyRunEventsDetectsGapsDuplicatesAndHashMismatches(t *testing.T) {
+	data := map[string]any{"tool_id": "stripe", "status_code": 200}
+	events := []cliRunEvent{
+		{EventID: "e1", RunID: "run-1", Seq: 1, Type: "RUN_CREATED"},
+		{EventID: "e2", RunID: "run-1", Seq: 2, Type: "TOOL_RESPONSE", Data: data, PayloadHash: hashRunEventData(t, data)},
+		{EventID: "e4", RunID: "run-1", Seq: 4, Type: "TOOL_RESPONSE", Data: map[string]any{"tool_id": "stripe", "status_code": 500}, PayloadHash: hashRunEventData(t, data)},
+		{EventID: "e4b", RunID: "run-1", Seq: 4, Type: "COMPLETED"},
+		{EventID: "e5", RunID: "run-1", Seq: 5, Type: "APPROVAL_REQUIRED", PayloadHash: "sha256:abc"},
+	}
+
+	result := verifyRunEvents("run-1", events)
+	if result.OK {
+		t.Fatal("expected verification to fail")
+	}
+	if result.HashesVerified != 1 || result.HashesUnverifiable != 2 || result.EventsWithoutHash != 2 {
+		t.Fatalf("unexpected hash counts: %+v", result)
+	}
+	checks := map[string]int{}
+	for _, finding := range result.Findings {
+		checks[finding.Check]++
+		if finding.Check == "payload_hash" && finding.Severity != "warning" {
+			t.Fatalf("expected an unrecognised hash to be a warning, got %+v", finding)
+		}
+	}
+	if checks["seq_gap"] != 1 || checks["duplicate_seq"] != 1 || checks["payload_hash"] != 1 {
+		t.Fatalf("unexpected findings: %+v", result.Findings)
+	}
+
+	clean := verifyRunEvents("run-1", events[:2])
+	if !clean.OK || len(clean.Findings) != 0 {
+		t.Fatalf("expected clean verification, got %+v", clean)
+	}
+
+	unrecognised := verifyRunEvents("run-1", []cliRunEvent{
+		{EventID: "e1", RunID: "run-1", Seq: 1, Type: "TOOL_REQUEST", Data: map[string]any{"tool_url": "https://api.stripe.com/v1/refunds"}, PayloadHash: "sha256:" + hex.EncodeToString(make([]byte, 32))},
+	})
+	if !unrecognised.OK || unrecognised.HashesUnverifiable != 1 {
+		t.Fatalf("expected an unrecognised hash not to fail verification, got %+v", unrecognised)
+	}
+}
+
+func TestSignedRunExportBundleDetectsEdits(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey returned error: %v", err)
+	}
+	run := cliRun{ID: "run-1", Status: "COMPLETED"}
+	events := []cliRunEvent{
+		{EventID: "e1", RunID: "run-1", Seq: 1, Type: "RUN_CREATED", Timestamp: time.Date(2026, 4, 9, 10, 0, 0, 0, time.UTC)},
+		{EventID: "e2", RunID: "run-1", Seq: 2, Type: "COMPLETED", Data: map[string]any{"tokens": 1234}},
+	}
+	export := cliRunExport{Run: run, Events: events, Timeline: buildRunTimeline(run, events)}
+	if err := signRunExport(&export, privateKey, time.Now().UTC()); err != nil {
+		t.Fatalf("signRunExport returned error: %v", err)
+	}
+	bundle, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to encode bundle: %v", err)
+	}
+
+	result, err := verifyRunExportBundle(bundle, publicKey)
+	if err != nil || !result.OK || result.Signature != "valid" {
+		t.Fatalf("expected valid signed bundle, got %+v (err %v)", result, err)
+	}
+
+	tampered := bytes.Replace(bundle, []byte(`"COMPLETED"`), []byte(`"FAILED"`), 1)
+	result, err = verifyRunExportBundle(tampered, publicKey)
+	if err != nil {
+		t.Fatalf("verifyRunExportBundle returned error: %v", err)
+	}
+	if result.OK || result.Signature != "invalid" {
+		t.Fatalf("expected tampered bundle to fail, got %+v", result)
+	}
+
+	otherKey, _, _ := ed25519.GenerateKey(rand.Reader)
+	result, _ = verifyRunExportBundle(bundle, otherKey)
+	if result.OK {
+		t.Fatal("expected bundle to fail against an untrusted key")
+	}
+}
+
+func TestLoadEd25519KeysFromPEM(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey returned error: %v", err)
+	}
+	privateDER, _ := x509.MarshalPKCS8PrivateKey(privateKey)
+	publicDER, _ := x509.MarshalPKIXPublicKey(publicKey)
+	dir := t.TempDir()
+	privatePath := filepath.Join(dir, "signer.pem")
+	publicPath := filepath.Join(dir, "signer.pub.pem")
+	if err := os.WriteFile(privatePath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	loadedPrivate, err := loadEd25519PrivateKey(privatePath)
+	if err != nil || !loadedPrivate.Equal(privateKey) {
+		t.Fatalf("unexpected private key (err %v)", err)
+	}
+	loadedPublic, err := loadEd25519PublicKey(publicPath)
+	if err != nil || !loadedPublic.Equal(publicKey) {
+		t.Fatalf("unexpected public key (err %v)", err)
+	}
+	if _, err := loadEd25519PrivateKey(publicPath); err == nil {
+		t.Fatal("expected error loading a public key as a signing key")
+	}
+}
@@ -0,0 +1,253 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Conditions accepted by runs wait --until.
+const (
+	runWaitUntilCompleted   = "completed"
+	runWaitUntilBlocked     = "blocked"
+	runWaitUntilAnyTerminal = "any-terminal"
+)
+
+func newRunsWaitCmd() *cobra.Command {
+	var (
+		timeout        time.Duration
+		interval       time.Duration
+		until          string
+		conversationID string
+	)
+	cmd := &cobra.Command{
+		Use:   "wait [run-id...]",
+		Short: "Wait for runs to finish or pause for approval",
+		Long: `Wait for one or more runs to reach a condition.
+
+--until completed     wait until every run is COMPLETED or FAILED (default)
+--until blocked       return as soon as any run pauses for approval or consent
+--until any-terminal  wait until every run has finished or paused
+
+The exit code reports where the runs ended up: 0 completed, 9 failed,
+10 waiting for approval, 11 waiting for consent, 12 paused, 13 timed out.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			until = strings.ToLower(strings.TrimSpace(until))
+			switch until {
+			case runWaitUntilCompleted, runWaitUntilBlocked, runWaitUntilAnyTerminal:
+			default:
+				return fmt.Errorf("--until must be one of completed, blocked, or any-terminal")
+			}
+			if len(args) == 0 && conversationID == "" {
+				return fmt.Errorf("pass at least one run ID or --conversation")
+			}
+
+			c, err := newAPIClient(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			ids := append([]string(nil), args...)
+			if conversationID != "" {
+				runs, _, err := fetchRuns(c, runFilters{ConversationID: conversationID})
+				if err != nil {
+					return err
+				}
+				if len(runs) == 0 {
+					return fmt.Errorf("no runs found for conversation %q", conversationID)
+				}
+				sort.SliceStable(runs, func(i, j int) bool {
+					return runStartTime(runs[i]).Before(runStartTime(runs[j]))
+				})
+				for _, run := range runs {
+					if !slices.Contains(ids, run.ID) {
+						ids = append(ids, run.ID)
+					}
+				}
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+
+			runs, err := waitForRuns(ctx, interval, ids, until, func(ctx context.Context, id string) (*cliRun, error) {
+				return fetchRun(c.WithContext(ctx), id)
+			})
+			if err != nil {
+				if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					return &exitCodeError{code: exitCodeWaitTimeout, err: runWaitTimeoutError(ids, runs, timeout)}
+				}
+				return err
+			}
+
+			single := len(args) == 1 && conversationID == ""
+			if isJSONOutput() {
+				if single {
+					if err := printJSONValue(runs[0]); err != nil {
+						return err
+					}
+				} else if err := printJSONValue(runs); err != nil {
+					return err
+				}
+			} else if single {
+				fmt.Printf("Run %q %s.\n\n", runs[0].ID, describeRunWaitStatus(runs[0]))
+				printRun(runs[0])
+			} else {
+				printRunWaitTable(runs)
+			}
+			return runWaitOutcome(runs)
+		},
+	}
+	cmd.Flags().DurationVar(&timeout, "timeout", 5*time.Minute, "Maximum time to wait for the runs")
+	cmd.Flags().DurationVar(&interval, "interval", 2*time.Second, "Polling interval while waiting")
+	cmd.Flags().StringVar(&until, "until", runWaitUntilCompleted, "Condition to wait for: completed, blocked, or any-terminal")
+	cmd.Flags().StringVar(&conversationID, "conversation", "", "Wait on every run in this conversation")
+	return cmd
+}
+
+// waitForRuns polls the runs until the until condition holds and returns
+// their last known state in the order of ids. On error the returned slice
+// holds whatever was fetched so far.
+func waitForRuns(ctx context.Context, interval time.Duration, ids []string, until string, fetch func(context.Context, string) (*cliRun, error)) ([]cliRun, error) {
+	latest := make(map[string]cliRun, len(ids))
+	snapshot := func() []cliRun {
+		runs := make([]cliRun, 0, len(ids))
+		for _, id := range ids {
+			if run, ok := latest[id]; ok {
+				runs = append(runs, run)
+			}
+		}
+		return runs
+	}
+
+	err := waitForCondition(ctx, interval, func(ctx context.Context) (bool, error) {
+		for _, id := range ids {
+			if run, ok := latest[id]; ok && isTerminalRunStatus(run.Status) {
+				continue
+			}
+			run, err := fetch(ctx, id)
+			if err != nil {
+				return false, err
+			}
+			latest[id] = *run
+		}
+		return runWaitSatisfied(snapshot(), until), nil
+	})
+	return snapshot(), err
+}
+
+// runWaitSatisfied reports whether waiting can stop for the given condition.
+func runWaitSatisfied(runs []cliRun, until string) bool {
+	stopped := 0
+	for _, run := range runs {
+		switch {
+		case isTerminalRunStatus(run.Status):
+			stopped++
+		case isBlockedRunStatus(run.Status):
+			if until == runWaitUntilBlocked {
+				return true
+			}
+			if until == runWaitUntilAnyTerminal {
+				stopped++
+			}
+		}
+	}
+	return stopped == len(runs)
+}
+
+// runWaitOutcomeCodes ranks outcomes; the first one present decides the
+// exit code when waiting on several runs.
+var runWaitOutcomeCodes = []struct {
+	status string
+	code   int
+}{
+	{"FAILED", exitCodeRunFailed},
+	{"PAUSED_APPROVAL", exitCodeRunAwaitingApproval},
+	{"PAUSED_CONSENT", exitCodeRunAwaitingConsent},
+	{"PAUSED", exitCodeRunPaused},
+}
+
+// runWaitOutcome returns nil when every run completed, otherwise an error
+// carrying the exit code for the most severe outcome.
+func runWaitOutcome(runs []cliRun) error {
+	var pending []string
+	code := 0
+	for _, outcome := range runWaitOutcomeCodes {
+		for _, run := range runs {
+			if !strings.EqualFold(strings.TrimSpace(run.Status), outcome.status) {
+				continue
+			}
+			if code == 0 {
+				code = outcome.code
+			}
+			pending = append(pending, run.ID)
+		}
+	}
+	if code == 0 {
+		for _, run := range runs {
+			if !isTerminalRunStatus(run.Status) {
+				pending = append(pending, run.ID)
+			}
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+		code = 1
+	}
+	if len(runs) == 1 {
+		return &exitCodeError{code: code, err: fmt.Errorf("run %q %s", runs[0].ID, describeRunWaitStatus(runs[0]))}
+	}
+	return &exitCodeError{code: code, err: fmt.Errorf("%d of %d runs did not complete: %s", len(pending), len(runs), strings.Join(pending, ", "))}
+}
+
+func runWaitTimeoutError(ids []string, runs []cliRun, timeout time.Duration) error {
+	if len(ids) == 1 {
+		status := "unknown"
+		if len(runs) == 1 {
+			status = emptyFallback(runs[0].Status, "unknown")
+		}
+		return fmt.Errorf("timed out after %s waiting for run %q (last status %s)", timeout, ids[0], status)
+	}
+	return fmt.Errorf("timed out after %s waiting for %d runs", timeout, len(ids))
+}
+
+func describeRunWaitStatus(run cliRun) string {
+	status := strings.ToUpper(strings.TrimSpace(run.Status))
+	action := ""
+	if run.BlockedActionID != "" {
+		action = fmt.Sprintf(" on action %s", run.BlockedActionID)
+	}
+	switch status {
+	case "COMPLETED":
+		return "completed"
+	case "FAILED":
+		return "failed"
+	case "PAUSED_APPROVAL":
+		return "is waiting for approval" + action
+	case "PAUSED_CONSENT":
+		return "is waiting for consent" + action
+	case "PAUSED":
+		return "is paused"
+	default:
+		return fmt.Sprintf("is %s", emptyFallback(run.Status, "unknown"))
+	}
+}
+
+func printRunWaitTable(runs []cliRun) {
+	table := newTable("ID", "AGENT", "STATUS", "BLOCKED ACTION", "UPDATED")
+	for _, run := range runs {
+		table.Append([]string{
+			run.ID,
+			run.AgentID,
+			run.Status,
+			run.BlockedActionID,
+			formatRunTime(run.UpdatedAt),
+		})
+	}
+	table.Render()
+}
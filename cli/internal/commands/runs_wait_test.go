@@ -0,0 +1,148 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+// scriptedRunFetcher returns each run's statuses in order, repeating the last.
+func scriptedRunFetcher(statuses map[string][]string) func(context.Context, string) (*cliRun, error) {
+	calls := map[string]int{}
+	return func(_ context.Context, id string) (*cliRun, error) {
+		script := statuses[id]
+		i := calls[id]
+		if i >= len(script) {
+			i = len(script) - 1
+		}
+		calls[id]++
+		return &cliRun{ID: id, Status: script[i]}, nil
+	}
+}
+
+func TestWaitForRunsCompletedWaitsThroughApprovalPauses(t *testing.T) {
+	fetch := scriptedRunFetcher(map[string][]string{
+		"run-1": {"RUNNING", "PAUSED_APPROVAL", "RUNNING", "COMPLETED"},
+	})
+	runs, err := waitForRuns(context.Background(), time.Millisecond, []string{"run-1"}, runWaitUntilCompleted, fetch)
+	if err != nil {
+		t.Fatalf("waitForRuns: %v", err)
+	}
+	if runs[0].Status != "COMPLETED" {
+		t.Fatalf("expected COMPLETED, got %q", runs[0].Status)
+	}
+	if err := runWaitOutcome(runs); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+}
+
+func TestWaitForRunsBlockedReturnsWhenAnyRunPauses(t *testing.T) {
+	fetch := scriptedRunFetcher(map[string][]string{
+		"run-1": {"RUNNING"},
+		"run-2": {"RUNNING", "PAUSED_CONSENT"},
+	})
+	runs, err := waitForRuns(context.Background(), time.Millisecond, []string{"run-1", "run-2"}, runWaitUntilBlocked, fetch)
+	if err != nil {
+		t.Fatalf("waitForRuns: %v", err)
+	}
+	if runs[0].Status != "RUNNING" || runs[1].Status != "PAUSED_CONSENT" {
+		t.Fatalf("unexpected statuses: %+v", runs)
+	}
+	assertExitCode(t, runWaitOutcome(runs), exitCodeRunAwaitingConsent)
+}
+
+func TestWaitForRunsAnyTerminalWaitsForEveryRunToStop(t *testing.T) {
+	fetch := scriptedRunFetcher(map[string][]string{
+		"run-1": {"PAUSED_APPROVAL"},
+		"run-2": {"RUNNING", "RUNNING", "FAILED"},
+	})
+	runs, err := waitForRuns(context.Background(), time.Millisecond, []string{"run-1", "run-2"}, runWaitUntilAnyTerminal, fetch)
+	if err != nil {
+		t.Fatalf("waitForRuns: %v", err)
+	}
+	if runs[1].Status != "FAILED" {
+		t.Fatalf("expected run-2 to fail, got %q", runs[1].Status)
+	}
+	// A failure outranks a pause when picking the exit code.
+	assertExitCode(t, runWaitOutcome(runs), exitCodeRunFailed)
+}
+
+func TestWaitForRunsTimesOutWithLastKnownState(t *testing.T) {
+	fetch := scriptedRunFetcher(map[string][]string{"run-1": {"PAUSED_APPROVAL"}})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	runs, err := waitForRuns(ctx, time.Millisecond, []string{"run-1"}, runWaitUntilCompleted, fetch)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if len(runs) != 1 || runs[0].Status != "PAUSED_APPROVAL" {
+		t.Fatalf("expected last known state, got %+v", runs)
+	}
+	got := runWaitTimeoutError([]string{"run-1"}, runs, time.Second).Error()
+	if got != `timed out after 1s waiting for run "run-1" (last status PAUSED_APPROVAL)` {
+		t.Fatalf("unexpected timeout error: %s", got)
+	}
+}
+
+func TestRunWaitOutcomeDescribesBlockedRun(t *testing.T) {
+	err := runWaitOutcome([]cliRun{{ID: "run-1", Status: "PAUSED_APPROVAL", BlockedActionID: "act-1"}})
+	assertExitCode(t, err, exitCodeRunAwaitingApproval)
+	if err.Error() != `run "run-1" is waiting for approval on action act-1` {
+		t.Fatalf("unexpected message: %s", err)
+	}
+}
+
+func TestCancelRunMarksRunFailed(t *testing.T) {
+	fake := &fakeCancelClient{fakeRunsClient: fakeRunsClient{objects: map[string]any{
+		"/runs/run-1": cliRun{ID: "run-1", AgentID: "billing", Status: "PAUSED_APPROVAL"},
+	}}}
+
+	previous, run, err := cancelRun(fake, "run-1")
+	if err != nil {
+		t.Fatalf("cancelRun: %v", err)
+	}
+	if previous != "PAUSED_APPROVAL" || run.Status != "FAILED" || run.AgentID != "billing" {
+		t.Fatalf("unexpected result: previous=%q run=%+v", previous, run)
+	}
+	if fake.patchedPath != "/runs/run-1" || fake.patchedBody["status"] != "FAILED" {
+		t.Fatalf("unexpected patch: %s %v", fake.patchedPath, fake.patchedBody)
+	}
+}
+
+func TestCancelRunRefusesFinishedRuns(t *testing.T) {
+	fake := &fakeCancelClient{fakeRunsClient: fakeRunsClient{objects: map[string]any{
+		"/runs/run-1": cliRun{ID: "run-1", Status: "COMPLETED"},
+	}}}
+	if _, _, err := cancelRun(fake, "run-1"); err == nil {
+		t.Fatalf("expected an error for a completed run")
+	}
+	if fake.patchedPath != "" {
+		t.Fatalf("expected no patch, got %s", fake.patchedPath)
+	}
+}
+
+type fakeCancelClient struct {
+	fakeRunsClient
+	patchedPath string
+	patchedBody map[string]string
+}
+
+func (f *fakeCancelClient) Patch(path string, payload interface{}) ([]byte, error) {
+	f.patchedPath = path
+	f.patchedBody = payload.(map[string]string)
+	return json.Marshal(map[string]string{"id": "run-1", "status": "FAILED"})
+}
+
+func assertExitCode(t *testing.T, err error, want int) {
+	t.Helper()
+	var coded *exitCodeError
+	if !errors.As(err, &coded) {
+		t.Fatalf("expected exit code %d, got %v", want, err)
+	}
+	if coded.code != want {
+		t.Fatalf("expected exit code %d, got %d (%v)", want, coded.code, err)
+	}
+}
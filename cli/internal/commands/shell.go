@@ -0,0 +1,32 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// runLineShell reads one command per line from in, printing prompt before
+// each, until EOF or /exit, exit, or quit. Blank lines are skipped. Errors
+// from handle are printed and the shell keeps going.
+func runLineShell(in io.Reader, out io.Writer, prompt string, handle func(line string) error) error {
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(out, prompt)
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return scanner.Err()
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if line == "/exit" || line == "exit" || line == "quit" {
+			return nil
+		}
+		if err := handle(line); err != nil {
+			fmt.Fprintf(out, "error: %v\n", err)
+		}
+	}
+}
@@ -12,7 +12,7 @@ func newStarterKitCmd() *cobra.Command {
 		Use:   "starter-kit",
 		Short: "Seed the platform with starter resources (echo-tool, playground-llm)",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
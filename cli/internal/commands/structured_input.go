@@ -3,7 +3,9 @@ package commands
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -50,3 +52,90 @@ func decodeStructuredData(data []byte, ext string, out any) error {
 
 	return fmt.Errorf("unsupported structured document format %q", ext)
 }
+
+// decodeStructuredDocuments decodes every document in a JSON or YAML file.
+// Multi-document YAML streams and top-level arrays yield one entry per item.
+func decodeStructuredDocuments(path string) ([]map[string]any, error) {
+	trimmed := strings.TrimSpace(path)
+	if strings.EqualFold(filepath.Ext(trimmed), ".json") {
+		var raw any
+		if err := decodeStructuredFile(trimmed, &raw); err != nil {
+			return nil, err
+		}
+		docs, err := structuredDocumentsFromValue(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %q: %w", trimmed, err)
+		}
+		return docs, nil
+	}
+
+	data, err := os.ReadFile(trimmed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %q: %w", trimmed, err)
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	var docs []map[string]any
+	for {
+		var raw any
+		if err := decoder.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("failed to decode %q: %w", trimmed, err)
+		}
+		if raw == nil {
+			continue
+		}
+		items, err := structuredDocumentsFromValue(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %q: %w", trimmed, err)
+		}
+		docs = append(docs, items...)
+	}
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("failed to decode %q: structured document is empty", trimmed)
+	}
+	return docs, nil
+}
+
+func structuredDocumentsFromValue(raw any) ([]map[string]any, error) {
+	switch typed := raw.(type) {
+	case map[string]any:
+		return []map[string]any{typed}, nil
+	case []any:
+		docs := make([]map[string]any, 0, len(typed))
+		for i, item := range typed {
+			doc, ok := item.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("document %d is not an object", i+1)
+			}
+			docs = append(docs, doc)
+		}
+		return docs, nil
+	default:
+		return nil, fmt.Errorf("expected an object or a list of objects")
+	}
+}
+
+// decodeStructuredValue re-decodes a generic document into a typed value.
+func decodeStructuredValue(in any, out any) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// encodeStructuredYAML renders v as YAML with two-space indentation.
+func encodeStructuredYAML(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
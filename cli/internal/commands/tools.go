@@ -3,6 +3,7 @@ package commands
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
 
 	"github.com/spf13/cobra"
@@ -23,29 +24,31 @@ func newToolsCmd() *cobra.Command {
 }
 
 func newToolsListCmd() *cobra.Command {
-	return &cobra.Command{
+	var list listOptions
+	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all tools",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
 
-			data, err := c.Get("/tools")
+			query := url.Values{"limit": {list.pageSize()}}
+			items, truncated, err := fetchListItems(c, "/tools", query, list.limitOrAll(), nil)
 			if err != nil {
 				return err
 			}
+			items = trimListItems(items, list.limitOrAll())
 
 			if isJSONOutput() {
-				fmt.Println(string(data))
-				return nil
+				return printJSONValue(items)
 			}
 
-			var tools []map[string]interface{}
-			if err := json.Unmarshal(data, &tools); err != nil {
-				return fmt.Errorf("failed to parse response: %w", err)
+			tools, err := decodeListItems(items)
+			if err != nil {
+				return err
 			}
 
 			table := newTable("NAME", "TOPOLOGY", "BASE_URL", "ACCESS", "STATUS")
@@ -60,9 +63,12 @@ func newToolsListCmd() *cobra.Command {
 			}
 
 			table.Render()
+			printListTruncatedHint(truncated, len(tools), "tools")
 			return nil
 		},
 	}
+	addListFlags(cmd, &list, "tools", 0)
+	return cmd
 }
 
 func newToolsGetCmd() *cobra.Command {
@@ -73,7 +79,7 @@ func newToolsGetCmd() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := args[0]
 
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
@@ -125,7 +131,7 @@ func newToolsCreateCmd() *cobra.Command {
 				return fmt.Errorf("invalid JSON in %q: %w", filePath, err)
 			}
 
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
@@ -159,7 +165,7 @@ func newToolsDeleteCmd() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := args[0]
 
-			c, err := newAPIClient()
+			c, err := newAPIClient(cmd.Context())
 			if err != nil {
 				return err
 			}
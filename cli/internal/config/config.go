@@ -2,9 +2,11 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
@@ -14,11 +16,49 @@ const (
 	AssistantModeOff       = "off"
 )
 
-// Config holds the CLI configuration.
+const (
+	// DefaultProfile is used when no profile is selected and is the target
+	// of the migration from single-profile config files.
+	DefaultProfile = "default"
+
+	defaultEndpoint = "http://localhost:8092"
+)
+
+// Config holds the resolved CLI configuration for the active profile.
+// When the profile uses a credential store, APIKey stays empty until
+// ResolveAPIKey is called.
 type Config struct {
-	Endpoint      string `json:"endpoint"`
-	APIKey        string `json:"api_key"`
-	AssistantMode string `json:"assistant_mode"`
+	Profile         string `json:"-"`
+	Endpoint        string `json:"endpoint"`
+	APIKey          string `json:"api_key"`
+	AssistantMode   string `json:"assistant_mode"`
+	CredentialStore string `json:"credential_store,omitempty"`
+}
+
+// Profile holds the stored settings of one named workspace.
+type Profile struct {
+	Endpoint        string `json:"endpoint,omitempty"`
+	APIKey          string `json:"api_key,omitempty"`
+	AssistantMode   string `json:"assistant_mode,omitempty"`
+	CredentialStore string `json:"credential_store,omitempty"`
+}
+
+// MigratedSecret records a profile whose API key was moved out of config.json.
+type MigratedSecret struct {
+	Profile string `json:"profile"`
+	Store   string `json:"store"`
+}
+
+// File is the on-disk layout of ~/.runagents/config.json.
+type File struct {
+	CurrentProfile string              `json:"current_profile,omitempty"`
+	Profiles       map[string]*Profile `json:"profiles,omitempty"`
+
+	// Single-profile fields written by older CLI versions. LoadFile moves
+	// them into the default profile.
+	Endpoint      string `json:"endpoint,omitempty"`
+	APIKey        string `json:"api_key,omitempty"`
+	AssistantMode string `json:"assistant_mode,omitempty"`
 }
 
 // configDir returns the path to the runagents config directory.
@@ -39,62 +79,109 @@ func configPath() (string, error) {
 	return filepath.Join(dir, "config.json"), nil
 }
 
-// Load reads the config from ~/.runagents/config.json.
-// If the file does not exist, it returns a default config.
-func Load() (*Config, error) {
+// LoadFile reads ~/.runagents/config.json without applying environment
+// overrides. A missing file yields an empty default profile.
+func LoadFile() (*File, error) {
 	path, err := configPath()
 	if err != nil {
 		return nil, err
 	}
 
-	defaultCfg := &Config{
-		Endpoint:      "http://localhost:8092",
-		AssistantMode: AssistantModeExternal,
+	file := &File{}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, file); err != nil {
+			return nil, fmt.Errorf("failed to parse config file: %w", err)
+		}
 	}
+	file.migrate()
+	return file, nil
+}
 
-	data, err := os.ReadFile(path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			applyEnvOverrides(defaultCfg)
-			normalizedMode, normalizeErr := NormalizeAssistantMode(defaultCfg.AssistantMode)
-			if normalizeErr != nil {
-				return nil, normalizeErr
+// migrate moves single-profile fields into the default profile.
+func (f *File) migrate() {
+	if f.Profiles == nil {
+		f.Profiles = map[string]*Profile{}
+	}
+	if f.Endpoint != "" || f.APIKey != "" || f.AssistantMode != "" {
+		if _, exists := f.Profiles[DefaultProfile]; !exists {
+			f.Profiles[DefaultProfile] = &Profile{
+				Endpoint:      f.Endpoint,
+				APIKey:        f.APIKey,
+				AssistantMode: f.AssistantMode,
 			}
-			defaultCfg.AssistantMode = normalizedMode
-			return defaultCfg, nil
 		}
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+		f.Endpoint = ""
+		f.APIKey = ""
+		f.AssistantMode = ""
+	}
+	for name, profile := range f.Profiles {
+		if profile == nil {
+			f.Profiles[name] = &Profile{}
+		}
 	}
+	if f.CurrentProfile == "" {
+		f.CurrentProfile = DefaultProfile
+	}
+}
 
-	var cfg Config
-	if err := json.Unmarshal(data, &cfg); err != nil {
-		return nil, fmt.Errorf("failed to parse config file: %w", err)
+// ResolveProfileName picks the profile to use: the explicit name, then
+// RUNAGENTS_PROFILE, then the file's current profile.
+func (f *File) ResolveProfileName(name string) string {
+	if trimmed := strings.TrimSpace(name); trimmed != "" {
+		return trimmed
 	}
-	if cfg.Endpoint == "" {
-		cfg.Endpoint = defaultCfg.Endpoint
+	if env := strings.TrimSpace(os.Getenv("RUNAGENTS_PROFILE")); env != "" {
+		return env
 	}
-	if cfg.AssistantMode == "" {
-		cfg.AssistantMode = defaultCfg.AssistantMode
+	if f.CurrentProfile != "" {
+		return f.CurrentProfile
 	}
-	applyEnvOverrides(&cfg)
-	normalizedMode, err := NormalizeAssistantMode(cfg.AssistantMode)
-	if err != nil {
-		return nil, err
+	return DefaultProfile
+}
+
+// Profile returns the named profile, creating an empty one if needed.
+func (f *File) Profile(name string) *Profile {
+	if f.Profiles == nil {
+		f.Profiles = map[string]*Profile{}
 	}
-	cfg.AssistantMode = normalizedMode
-	return &cfg, nil
+	profile, exists := f.Profiles[name]
+	if !exists || profile == nil {
+		profile = &Profile{}
+		f.Profiles[name] = profile
+	}
+	return profile
 }
 
-// Save writes the config to ~/.runagents/config.json.
-func Save(cfg *Config) error {
-	if cfg == nil {
+// ProfileNames returns the stored profile names in sorted order.
+func (f *File) ProfileNames() []string {
+	names := make([]string, 0, len(f.Profiles))
+	for name := range f.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SaveFile writes the config file, always in the multi-profile layout.
+func SaveFile(f *File) error {
+	if f == nil {
 		return fmt.Errorf("config is required")
 	}
-	normalizedMode, err := NormalizeAssistantMode(cfg.AssistantMode)
-	if err != nil {
-		return err
+	f.migrate()
+	for name, profile := range f.Profiles {
+		if profile.AssistantMode == "" {
+			continue
+		}
+		normalizedMode, err := NormalizeAssistantMode(profile.AssistantMode)
+		if err != nil {
+			return fmt.Errorf("profile %q: %w", name, err)
+		}
+		profile.AssistantMode = normalizedMode
 	}
-	cfg.AssistantMode = normalizedMode
 
 	dir, err := configDir()
 	if err != nil {
@@ -110,7 +197,7 @@ func Save(cfg *Config) error {
 		return err
 	}
 
-	data, err := json.MarshalIndent(cfg, "", "  ")
+	data, err := json.MarshalIndent(f, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
@@ -121,6 +208,167 @@ func Save(cfg *Config) error {
 	return nil
 }
 
+// Load reads the config for the active profile from ~/.runagents/config.json.
+// If the file does not exist, it returns a default config.
+func Load() (*Config, error) {
+	return LoadProfile("")
+}
+
+// LoadProfile reads the config for the named profile, falling back to
+// RUNAGENTS_PROFILE and the current profile when name is empty.
+func LoadProfile(name string) (*Config, error) {
+	file, err := LoadFile()
+	if err != nil {
+		return nil, err
+	}
+
+	profileName := file.ResolveProfileName(name)
+	profile, exists := file.Profiles[profileName]
+	if !exists {
+		if profileName != DefaultProfile {
+			return nil, fmt.Errorf("profile %q not found; create it with 'runagents config set --profile %s endpoint <url>'", profileName, profileName)
+		}
+		profile = &Profile{}
+	}
+
+	cfg := &Config{
+		Profile:         profileName,
+		Endpoint:        profile.Endpoint,
+		APIKey:          profile.APIKey,
+		AssistantMode:   profile.AssistantMode,
+		CredentialStore: profile.CredentialStore,
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = defaultEndpoint
+	}
+	if cfg.AssistantMode == "" {
+		cfg.AssistantMode = AssistantModeExternal
+	}
+	applyEnvOverrides(cfg)
+	normalizedMode, err := NormalizeAssistantMode(cfg.AssistantMode)
+	if err != nil {
+		return nil, err
+	}
+	cfg.AssistantMode = normalizedMode
+	return cfg, nil
+}
+
+// SetAPIKey stores key in the profile's credential store, or in the profile
+// itself when no store is configured.
+func (p *Profile) SetAPIKey(profileName, key string) error {
+	if p.CredentialStore == "" {
+		p.APIKey = key
+		return nil
+	}
+	store, err := NewCredentialStore(p.CredentialStore)
+	if err != nil {
+		return err
+	}
+	if key == "" {
+		err = store.Erase(profileName)
+	} else {
+		err = store.Store(profileName, key)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update API key in %s credential store: %w", p.CredentialStore, err)
+	}
+	p.APIKey = ""
+	return nil
+}
+
+// SwitchCredentialStore moves the profile's API key from its current store
+// (or the plaintext config) to target, where "" means plaintext, and erases
+// it from the old store. It reports whether there was a key to move. On
+// error the profile is left pointing at the store that still holds the key.
+func (p *Profile) SwitchCredentialStore(profileName, target string) (bool, error) {
+	previous := p.CredentialStore
+	if target == previous {
+		return false, nil
+	}
+
+	key, fromStore := p.APIKey, false
+	if key == "" && previous != "" {
+		store, err := NewCredentialStore(previous)
+		if err != nil {
+			return false, err
+		}
+		key, err = store.Get(profileName)
+		if err != nil && !errors.Is(err, ErrCredentialNotFound) {
+			return false, fmt.Errorf("failed to read API key from %s credential store: %w", previous, err)
+		}
+		fromStore = key != ""
+	}
+
+	p.CredentialStore = target
+	if key == "" {
+		return false, nil
+	}
+	if err := p.SetAPIKey(profileName, key); err != nil {
+		p.CredentialStore = previous
+		return false, err
+	}
+	if fromStore {
+		store, err := NewCredentialStore(previous)
+		if err == nil {
+			err = store.Erase(profileName)
+		}
+		if err != nil {
+			p.APIKey, p.CredentialStore = "", previous
+			return false, fmt.Errorf("failed to remove API key from %s credential store: %w", previous, err)
+		}
+	}
+	return true, nil
+}
+
+// ResolveAPIKey returns the API key, reading it from the profile's
+// credential store on first use. A missing stored key is not an error.
+func (c *Config) ResolveAPIKey() (string, error) {
+	if c.APIKey != "" || c.CredentialStore == "" {
+		return c.APIKey, nil
+	}
+	store, err := NewCredentialStore(c.CredentialStore)
+	if err != nil {
+		return "", err
+	}
+	key, err := store.Get(c.Profile)
+	if errors.Is(err, ErrCredentialNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read API key from %s credential store: %w", c.CredentialStore, err)
+	}
+	c.APIKey = key
+	return key, nil
+}
+
+// MigrateSecrets moves plaintext API keys out of the config file. Each key
+// goes to storeName when set, else the profile's configured store, else
+// DefaultCredentialStore. The caller saves the file afterwards.
+func MigrateSecrets(file *File, storeName string) ([]MigratedSecret, error) {
+	var migrated []MigratedSecret
+	for _, name := range file.ProfileNames() {
+		profile := file.Profiles[name]
+		if profile.APIKey == "" {
+			continue
+		}
+		target := strings.TrimSpace(storeName)
+		if target == "" {
+			target = profile.CredentialStore
+		}
+		if target == "" {
+			target = DefaultCredentialStore()
+		}
+		key, previousStore := profile.APIKey, profile.CredentialStore
+		profile.CredentialStore = target
+		if err := profile.SetAPIKey(name, key); err != nil {
+			profile.APIKey, profile.CredentialStore = key, previousStore
+			return migrated, fmt.Errorf("profile %q: %w", name, err)
+		}
+		migrated = append(migrated, MigratedSecret{Profile: name, Store: target})
+	}
+	return migrated, nil
+}
+
 func applyEnvOverrides(cfg *Config) {
 	if cfg == nil {
 		return
@@ -1,6 +1,12 @@
 package config
 
-import "testing"
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
 
 func TestNormalizeAssistantMode(t *testing.T) {
 	tests := []struct {
@@ -59,3 +65,94 @@ func TestLoadRejectsInvalidAssistantModeFromEnv(t *testing.T) {
 		t.Fatalf("expected load error for invalid assistant mode")
 	}
 }
+
+func writeConfigFile(t *testing.T, home, contents string) {
+	t.Helper()
+	dir := filepath.Join(home, ".runagents")
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		t.Fatalf("create config dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(contents), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+}
+
+func TestLoadMigratesLegacyConfigIntoDefaultProfile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeConfigFile(t, home, `{"endpoint":"https://legacy.example.com","api_key":"ra_ws_legacy","assistant_mode":"off"}`)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("expected load to succeed, got error: %v", err)
+	}
+	if cfg.Profile != DefaultProfile || cfg.Endpoint != "https://legacy.example.com" || cfg.APIKey != "ra_ws_legacy" || cfg.AssistantMode != AssistantModeOff {
+		t.Fatalf("unexpected migrated config: %#v", cfg)
+	}
+
+	file, err := LoadFile()
+	if err != nil {
+		t.Fatalf("load file: %v", err)
+	}
+	file.Profile("staging").Endpoint = "https://staging.example.com"
+	if err := SaveFile(file); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(home, ".runagents", "config.json"))
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("parse config: %v", err)
+	}
+	if _, exists := raw["endpoint"]; exists {
+		t.Fatalf("expected legacy top-level fields to be rewritten, got:\n%s", data)
+	}
+
+	file, err = LoadFile()
+	if err != nil {
+		t.Fatalf("load file: %v", err)
+	}
+	if got := strings.Join(file.ProfileNames(), ","); got != "default,staging" {
+		t.Fatalf("expected default and staging profiles, got %q", got)
+	}
+	if file.Profiles[DefaultProfile].Endpoint != "https://legacy.example.com" {
+		t.Fatalf("expected default profile to be preserved, got %#v", file.Profiles[DefaultProfile])
+	}
+	if file.Endpoint != "" || file.APIKey != "" {
+		t.Fatalf("expected legacy fields to be cleared, got %#v", file)
+	}
+}
+
+func TestLoadProfileResolution(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeConfigFile(t, home, `{
+  "current_profile": "dev",
+  "profiles": {
+    "dev": {"endpoint": "https://dev.example.com"},
+    "prod": {"endpoint": "https://prod.example.com", "assistant_mode": "runagents"}
+  }
+}`)
+
+	cfg, err := LoadProfile("")
+	if err != nil || cfg.Profile != "dev" || cfg.Endpoint != "https://dev.example.com" {
+		t.Fatalf("expected current profile dev, got %#v (err %v)", cfg, err)
+	}
+
+	t.Setenv("RUNAGENTS_PROFILE", "prod")
+	cfg, err = LoadProfile("")
+	if err != nil || cfg.Profile != "prod" || cfg.AssistantMode != AssistantModeRunAgents {
+		t.Fatalf("expected env profile prod, got %#v (err %v)", cfg, err)
+	}
+
+	cfg, err = LoadProfile("dev")
+	if err != nil || cfg.Profile != "dev" {
+		t.Fatalf("expected explicit profile to win, got %#v (err %v)", cfg, err)
+	}
+
+	if _, err := LoadProfile("missing"); err == nil || !strings.Contains(err.Error(), `profile "missing" not found`) {
+		t.Fatalf("expected missing profile error, got %v", err)
+	}
+}
@@ -0,0 +1,387 @@
+package config
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const (
+	// CredentialStoreKeychain keeps API keys in the OS secret store (macOS
+	// Keychain via security, or the Secret Service via secret-tool on Linux).
+	CredentialStoreKeychain = "keychain"
+	// CredentialStoreFile keeps API keys in ~/.runagents/credentials.enc,
+	// encrypted with a key generated on first use.
+	CredentialStoreFile = "file"
+
+	// credentialHelperPrefix names external helpers, Docker style: the
+	// store "pass" runs runagents-credential-pass.
+	credentialHelperPrefix = "runagents-credential-"
+	credentialService      = "runagents"
+)
+
+// ErrCredentialNotFound is returned when a store has no key for a profile.
+var ErrCredentialNotFound = errors.New("credential not found")
+
+// CredentialStore keeps API keys outside config.json, keyed by profile.
+type CredentialStore interface {
+	Get(profile string) (string, error)
+	Store(profile, secret string) error
+	Erase(profile string) error
+}
+
+// credentialCommandError is a failed run of an external credential program.
+type credentialCommandError struct {
+	name   string
+	err    error
+	code   int
+	stderr string
+	stdout string
+}
+
+func (e *credentialCommandError) Error() string {
+	if message := strings.TrimSpace(firstNonEmptyString(e.stderr, e.stdout)); message != "" {
+		return fmt.Sprintf("%s: %v: %s", e.name, e.err, message)
+	}
+	return fmt.Sprintf("%s: %v", e.name, e.err)
+}
+
+func (e *credentialCommandError) Unwrap() error { return e.err }
+
+// runCredentialCommand runs an external credential program. Tests replace it.
+var runCredentialCommand = func(name string, args []string, stdin string) (string, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		cmdErr := &credentialCommandError{name: name, err: err, code: -1, stderr: stderr.String(), stdout: stdout.String()}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			cmdErr.code = exitErr.ExitCode()
+		}
+		return stdout.String(), cmdErr
+	}
+	return stdout.String(), nil
+}
+
+// NewCredentialStore returns the store for a credential_store value:
+// "keychain", "file", or the name of a runagents-credential-<name> helper.
+func NewCredentialStore(name string) (CredentialStore, error) {
+	switch trimmed := strings.TrimSpace(name); trimmed {
+	case "":
+		return nil, fmt.Errorf("credential store name is required")
+	case CredentialStoreKeychain:
+		return keychainStore{goos: runtime.GOOS}, nil
+	case CredentialStoreFile:
+		dir, err := configDir()
+		if err != nil {
+			return nil, err
+		}
+		return fileCredentialStore{dir: dir}, nil
+	default:
+		if strings.ContainsAny(trimmed, `/\ `) {
+			return nil, fmt.Errorf("invalid credential helper name %q", trimmed)
+		}
+		return helperCredentialStore{program: credentialHelperPrefix + trimmed}, nil
+	}
+}
+
+// DefaultCredentialStore returns keychain when the OS secret store tooling
+// is available and file otherwise.
+func DefaultCredentialStore() string {
+	if keychainAvailable(runtime.GOOS) {
+		return CredentialStoreKeychain
+	}
+	return CredentialStoreFile
+}
+
+func keychainAvailable(goos string) bool {
+	var tool string
+	switch goos {
+	case "darwin":
+		tool = "security"
+	case "linux":
+		tool = "secret-tool"
+	default:
+		return false
+	}
+	_, err := exec.LookPath(tool)
+	return err == nil
+}
+
+type helperCredentialStore struct {
+	program string
+}
+
+type helperCredential struct {
+	ServerURL string `json:"ServerURL"`
+	Username  string `json:"Username"`
+	Secret    string `json:"Secret"`
+}
+
+func credentialServerURL(profile string) string {
+	return "runagents://" + profile
+}
+
+func (s helperCredentialStore) Get(profile string) (string, error) {
+	out, err := runCredentialCommand(s.program, []string{"get"}, credentialServerURL(profile))
+	if err != nil {
+		if strings.Contains(strings.ToLower(out+err.Error()), "not found") {
+			return "", ErrCredentialNotFound
+		}
+		return "", err
+	}
+	var cred helperCredential
+	if err := json.Unmarshal([]byte(out), &cred); err != nil {
+		return "", fmt.Errorf("failed to parse %s output: %w", s.program, err)
+	}
+	if cred.Secret == "" {
+		return "", ErrCredentialNotFound
+	}
+	return cred.Secret, nil
+}
+
+func (s helperCredentialStore) Store(profile, secret string) error {
+	payload, err := json.Marshal(helperCredential{
+		ServerURL: credentialServerURL(profile),
+		Username:  profile,
+		Secret:    secret,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = runCredentialCommand(s.program, []string{"store"}, string(payload))
+	return err
+}
+
+func (s helperCredentialStore) Erase(profile string) error {
+	out, err := runCredentialCommand(s.program, []string{"erase"}, credentialServerURL(profile))
+	if err != nil && strings.Contains(strings.ToLower(out+err.Error()), "not found") {
+		return nil
+	}
+	return err
+}
+
+type keychainStore struct {
+	goos string
+}
+
+func (s keychainStore) Get(profile string) (string, error) {
+	var (
+		out string
+		err error
+	)
+	switch s.goos {
+	case "darwin":
+		out, err = runCredentialCommand("security", []string{"find-generic-password", "-s", credentialService, "-a", profile, "-w"}, "")
+	case "linux":
+		out, err = runCredentialCommand("secret-tool", []string{"lookup", "service", credentialService, "profile", profile}, "")
+	default:
+		return "", s.unsupported()
+	}
+	secret := strings.TrimRight(out, "\r\n")
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "could not be found") || (s.goos == "linux" && secretToolNotFound(err)) {
+			return "", ErrCredentialNotFound
+		}
+		return "", err
+	}
+	if secret == "" {
+		return "", ErrCredentialNotFound
+	}
+	return secret, nil
+}
+
+func (s keychainStore) Store(profile, secret string) error {
+	var err error
+	switch s.goos {
+	case "darwin":
+		// Run the command through security's interactive mode so the
+		// secret is read from stdin rather than appearing in argv.
+		command := fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n",
+			securityQuote(credentialService), securityQuote(profile), securityQuote(secret))
+		_, err = runCredentialCommand("security", []string{"-i"}, command)
+	case "linux":
+		label := fmt.Sprintf("RunAgents CLI (%s)", profile)
+		_, err = runCredentialCommand("secret-tool", []string{"store", "--label", label, "service", credentialService, "profile", profile}, secret)
+	default:
+		return s.unsupported()
+	}
+	return err
+}
+
+func (s keychainStore) Erase(profile string) error {
+	switch s.goos {
+	case "darwin":
+		_, err := runCredentialCommand("security", []string{"delete-generic-password", "-s", credentialService, "-a", profile}, "")
+		if err != nil && strings.Contains(strings.ToLower(err.Error()), "could not be found") {
+			return nil
+		}
+		return err
+	case "linux":
+		_, err := runCredentialCommand("secret-tool", []string{"clear", "service", credentialService, "profile", profile}, "")
+		return err
+	default:
+		return s.unsupported()
+	}
+}
+
+// secretToolNotFound reports whether a secret-tool lookup failed only
+// because there is no matching item: it then exits 1 and prints nothing.
+// Other failures, such as no D-Bus session, print to stderr.
+func secretToolNotFound(err error) bool {
+	var cmdErr *credentialCommandError
+	return errors.As(err, &cmdErr) && cmdErr.code == 1 && strings.TrimSpace(cmdErr.stderr) == ""
+}
+
+// securityQuote quotes a value for a security -i command line.
+func securityQuote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+func (s keychainStore) unsupported() error {
+	return fmt.Errorf("keychain credential store is not supported on %s; use %q or a credential helper", s.goos, CredentialStoreFile)
+}
+
+// fileCredentialStore encrypts all profile keys with AES-256-GCM. The data
+// key lives beside the ciphertext with owner-only permissions, so this
+// protects against the plaintext config being copied or printed, not
+// against an attacker with full access to the home directory.
+type fileCredentialStore struct {
+	dir string
+}
+
+func (s fileCredentialStore) keyPath() string {
+	return filepath.Join(s.dir, "credentials.key")
+}
+
+func (s fileCredentialStore) dataPath() string {
+	return filepath.Join(s.dir, "credentials.enc")
+}
+
+func (s fileCredentialStore) Get(profile string) (string, error) {
+	secrets, err := s.load()
+	if err != nil {
+		return "", err
+	}
+	secret, ok := secrets[profile]
+	if !ok || secret == "" {
+		return "", ErrCredentialNotFound
+	}
+	return secret, nil
+}
+
+func (s fileCredentialStore) Store(profile, secret string) error {
+	secrets, err := s.load()
+	if err != nil {
+		return err
+	}
+	secrets[profile] = secret
+	return s.save(secrets)
+}
+
+func (s fileCredentialStore) Erase(profile string) error {
+	secrets, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := secrets[profile]; !ok {
+		return nil
+	}
+	delete(secrets, profile)
+	return s.save(secrets)
+}
+
+func (s fileCredentialStore) load() (map[string]string, error) {
+	data, err := os.ReadFile(s.dataPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]string{}, nil
+		}
+		return nil, fmt.Errorf("failed to read credentials file: %w", err)
+	}
+	key, err := os.ReadFile(s.keyPath())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials key: %w", err)
+	}
+	aead, err := newCredentialCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < aead.NonceSize() {
+		return nil, fmt.Errorf("credentials file is corrupted")
+	}
+	plaintext, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt credentials file: %w", err)
+	}
+	secrets := map[string]string{}
+	if err := json.Unmarshal(plaintext, &secrets); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials file: %w", err)
+	}
+	return secrets, nil
+}
+
+func (s fileCredentialStore) save(secrets map[string]string) error {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	key, err := os.ReadFile(s.keyPath())
+	if os.IsNotExist(err) {
+		key = make([]byte, 32)
+		if _, err := io.ReadFull(rand.Reader, key); err != nil {
+			return fmt.Errorf("failed to generate credentials key: %w", err)
+		}
+		if err := os.WriteFile(s.keyPath(), key, 0600); err != nil {
+			return fmt.Errorf("failed to write credentials key: %w", err)
+		}
+	} else if err != nil {
+		return fmt.Errorf("failed to read credentials key: %w", err)
+	}
+	aead, err := newCredentialCipher(key)
+	if err != nil {
+		return err
+	}
+	plaintext, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := aead.Seal(nonce, nonce, plaintext, nil)
+	if err := os.WriteFile(s.dataPath(), sealed, 0600); err != nil {
+		return fmt.Errorf("failed to write credentials file: %w", err)
+	}
+	return nil
+}
+
+func newCredentialCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid credentials key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+func firstNonEmptyString(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return value
+		}
+	}
+	return ""
+}
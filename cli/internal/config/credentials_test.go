@@ -0,0 +1,245 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileCredentialStoreEncryptsAtRest(t *testing.T) {
+	store := fileCredentialStore{dir: t.TempDir()}
+
+	if _, err := store.Get("default"); !errors.Is(err, ErrCredentialNotFound) {
+		t.Fatalf("expected not found before storing, got %v", err)
+	}
+	if err := store.Store("default", "ra_ws_secret_value"); err != nil {
+		t.Fatalf("store: %v", err)
+	}
+	if err := store.Store("prod", "ra_ws_prod_value"); err != nil {
+		t.Fatalf("store prod: %v", err)
+	}
+
+	data, err := os.ReadFile(store.dataPath())
+	if err != nil {
+		t.Fatalf("read credentials file: %v", err)
+	}
+	if bytes.Contains(data, []byte("ra_ws_secret_value")) {
+		t.Fatalf("expected credentials file to be encrypted")
+	}
+	info, err := os.Stat(store.keyPath())
+	if err != nil {
+		t.Fatalf("stat key: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected key permissions 0600, got %v", info.Mode().Perm())
+	}
+
+	got, err := store.Get("default")
+	if err != nil || got != "ra_ws_secret_value" {
+		t.Fatalf("expected stored secret, got %q (err %v)", got, err)
+	}
+	if err := store.Erase("default"); err != nil {
+		t.Fatalf("erase: %v", err)
+	}
+	if _, err := store.Get("default"); !errors.Is(err, ErrCredentialNotFound) {
+		t.Fatalf("expected not found after erase, got %v", err)
+	}
+	if got, _ := store.Get("prod"); got != "ra_ws_prod_value" {
+		t.Fatalf("expected other profile to survive erase, got %q", got)
+	}
+}
+
+func TestHelperCredentialStoreProtocol(t *testing.T) {
+	stored := map[string]string{}
+	var calls []string
+	original := runCredentialCommand
+	runCredentialCommand = func(name string, args []string, stdin string) (string, error) {
+		calls = append(calls, name+" "+strings.Join(args, " "))
+		switch args[0] {
+		case "store":
+			var cred helperCredential
+			if err := json.Unmarshal([]byte(stdin), &cred); err != nil {
+				return "", err
+			}
+			stored[cred.ServerURL] = cred.Secret
+			return "", nil
+		case "get":
+			secret, ok := stored[stdin]
+			if !ok {
+				return "credentials not found in native keychain", fmt.Errorf("exit status 1")
+			}
+			data, _ := json.Marshal(helperCredential{ServerURL: stdin, Username: "prod", Secret: secret})
+			return string(data), nil
+		}
+		return "", nil
+	}
+	t.Cleanup(func() { runCredentialCommand = original })
+
+	store, err := NewCredentialStore("pass")
+	if err != nil {
+		t.Fatalf("NewCredentialStore: %v", err)
+	}
+	if _, err := store.Get("prod"); !errors.Is(err, ErrCredentialNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	if err := store.Store("prod", "ra_ws_helper"); err != nil {
+		t.Fatalf("store: %v", err)
+	}
+	got, err := store.Get("prod")
+	if err != nil || got != "ra_ws_helper" {
+		t.Fatalf("expected helper secret, got %q (err %v)", got, err)
+	}
+	if calls[0] != "runagents-credential-pass get" || calls[1] != "runagents-credential-pass store" {
+		t.Fatalf("unexpected helper calls %v", calls)
+	}
+}
+
+func TestMigrateSecretsMovesKeysOutOfConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeConfigFile(t, home, `{"endpoint":"https://legacy.example.com","api_key":"ra_ws_plaintext"}`)
+
+	file, err := LoadFile()
+	if err != nil {
+		t.Fatalf("load file: %v", err)
+	}
+	migrated, err := MigrateSecrets(file, CredentialStoreFile)
+	if err != nil {
+		t.Fatalf("MigrateSecrets: %v", err)
+	}
+	if len(migrated) != 1 || migrated[0].Profile != DefaultProfile || migrated[0].Store != CredentialStoreFile {
+		t.Fatalf("unexpected migration result %#v", migrated)
+	}
+	if err := SaveFile(file); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(home, ".runagents", "config.json"))
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	if strings.Contains(string(data), "ra_ws_plaintext") {
+		t.Fatalf("expected plaintext key to be removed, got:\n%s", data)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.APIKey != "" {
+		t.Fatalf("expected key to be resolved lazily, got %q", cfg.APIKey)
+	}
+	key, err := cfg.ResolveAPIKey()
+	if err != nil || key != "ra_ws_plaintext" {
+		t.Fatalf("expected resolved key, got %q (err %v)", key, err)
+	}
+
+	t.Setenv("RUNAGENTS_API_KEY", "ra_ws_env")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if key, _ := cfg.ResolveAPIKey(); key != "ra_ws_env" {
+		t.Fatalf("expected env override to win, got %q", key)
+	}
+}
+
+func TestKeychainStoreKeepsSecretOffTheCommandLine(t *testing.T) {
+	var calls []string
+	var input string
+	original := runCredentialCommand
+	runCredentialCommand = func(name string, args []string, stdin string) (string, error) {
+		calls = append(calls, name+" "+strings.Join(args, " "))
+		input = stdin
+		return "", nil
+	}
+	t.Cleanup(func() { runCredentialCommand = original })
+
+	if err := (keychainStore{goos: "darwin"}).Store("prod", `ra_ws_"secret"`); err != nil {
+		t.Fatalf("store: %v", err)
+	}
+	if len(calls) != 1 || calls[0] != "security -i" {
+		t.Fatalf("unexpected security calls %v", calls)
+	}
+	if want := `add-generic-password -U -s "runagents" -a "prod" -w "ra_ws_\"secret\""` + "\n"; input != want {
+		t.Fatalf("expected command on stdin %q, got %q", want, input)
+	}
+}
+
+func TestKeychainStoreLinuxOnlyTreatsSilentExitOneAsNotFound(t *testing.T) {
+	var result error
+	original := runCredentialCommand
+	runCredentialCommand = func(name string, args []string, stdin string) (string, error) {
+		return "", result
+	}
+	t.Cleanup(func() { runCredentialCommand = original })
+	store := keychainStore{goos: "linux"}
+
+	result = &credentialCommandError{name: "secret-tool", err: errors.New("exit status 1"), code: 1}
+	if _, err := store.Get("prod"); !errors.Is(err, ErrCredentialNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+
+	result = &credentialCommandError{name: "secret-tool", err: errors.New("exit status 1"), code: 1, stderr: "Cannot autolaunch D-Bus without X11 $DISPLAY"}
+	if _, err := store.Get("prod"); err == nil || errors.Is(err, ErrCredentialNotFound) || !strings.Contains(err.Error(), "D-Bus") {
+		t.Fatalf("expected the D-Bus error to be returned, got %v", err)
+	}
+}
+
+func TestSwitchCredentialStoreMovesKey(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	helper := map[string]string{}
+	original := runCredentialCommand
+	runCredentialCommand = func(name string, args []string, stdin string) (string, error) {
+		switch args[0] {
+		case "store":
+			var cred helperCredential
+			if err := json.Unmarshal([]byte(stdin), &cred); err != nil {
+				return "", err
+			}
+			helper[cred.ServerURL] = cred.Secret
+		case "get":
+			secret, ok := helper[stdin]
+			if !ok {
+				return "credentials not found", fmt.Errorf("exit status 1")
+			}
+			data, _ := json.Marshal(helperCredential{ServerURL: stdin, Secret: secret})
+			return string(data), nil
+		case "erase":
+			delete(helper, stdin)
+		}
+		return "", nil
+	}
+	t.Cleanup(func() { runCredentialCommand = original })
+
+	profile := &Profile{CredentialStore: CredentialStoreFile}
+	if err := profile.SetAPIKey("prod", "ra_ws_moving"); err != nil {
+		t.Fatalf("SetAPIKey: %v", err)
+	}
+
+	moved, err := profile.SwitchCredentialStore("prod", "pass")
+	if err != nil || !moved {
+		t.Fatalf("switch to helper: moved=%v err=%v", moved, err)
+	}
+	if profile.CredentialStore != "pass" || profile.APIKey != "" || helper["runagents://prod"] != "ra_ws_moving" {
+		t.Fatalf("expected the key in the helper, got profile %#v helper %#v", profile, helper)
+	}
+	fileStore, _ := NewCredentialStore(CredentialStoreFile)
+	if _, err := fileStore.Get("prod"); !errors.Is(err, ErrCredentialNotFound) {
+		t.Fatalf("expected the key to be erased from the file store, got %v", err)
+	}
+
+	moved, err = profile.SwitchCredentialStore("prod", "")
+	if err != nil || !moved {
+		t.Fatalf("switch to none: moved=%v err=%v", moved, err)
+	}
+	if profile.CredentialStore != "" || profile.APIKey != "ra_ws_moving" || len(helper) != 0 {
+		t.Fatalf("expected the key back in plaintext, got profile %#v helper %#v", profile, helper)
+	}
+}
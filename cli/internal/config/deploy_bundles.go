@@ -0,0 +1,71 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DeployedBundle records the source bundle last uploaded for an agent, so an
+// unchanged bundle can reuse its image instead of being uploaded again.
+type DeployedBundle struct {
+	Hash       string    `json:"hash"`
+	Image      string    `json:"image,omitempty"`
+	BuildID    string    `json:"build_id,omitempty"`
+	Files      int       `json:"files"`
+	DeployedAt time.Time `json:"deployed_at"`
+}
+
+func deployBundlesPath(cwd string) string {
+	return filepath.Join(projectDataDir(cwd), "bundles.json")
+}
+
+// DeployBundleKey identifies an agent within a workspace endpoint.
+func DeployBundleKey(endpoint, agent string) string {
+	return strings.TrimRight(strings.TrimSpace(endpoint), "/") + "#" + strings.TrimSpace(agent)
+}
+
+// LoadDeployedBundles loads .runagents/bundles.json from the project.
+// Missing file is treated as no recorded bundles.
+func LoadDeployedBundles(cwd string) (map[string]DeployedBundle, error) {
+	data, err := os.ReadFile(deployBundlesPath(cwd))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]DeployedBundle{}, nil
+		}
+		return nil, fmt.Errorf("failed to read deployed bundles: %w", err)
+	}
+	bundles := map[string]DeployedBundle{}
+	if err := json.Unmarshal(data, &bundles); err != nil {
+		return nil, fmt.Errorf("failed to parse deployed bundles: %w", err)
+	}
+	return bundles, nil
+}
+
+// SaveDeployedBundle records bundle under key in .runagents/bundles.json,
+// keeping the entries for other agents.
+func SaveDeployedBundle(cwd, key string, bundle DeployedBundle) error {
+	bundles, err := LoadDeployedBundles(cwd)
+	if err != nil {
+		return err
+	}
+	if bundle.DeployedAt.IsZero() {
+		bundle.DeployedAt = time.Now().UTC()
+	}
+	bundles[key] = bundle
+
+	if err := os.MkdirAll(projectDataDir(cwd), 0700); err != nil {
+		return fmt.Errorf("failed to create project state dir: %w", err)
+	}
+	data, err := json.MarshalIndent(bundles, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode deployed bundles: %w", err)
+	}
+	if err := os.WriteFile(deployBundlesPath(cwd), data, 0600); err != nil {
+		return fmt.Errorf("failed to write deployed bundles: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDeployedBundlesRoundTrip(t *testing.T) {
+	tmp := t.TempDir()
+
+	bundles, err := LoadDeployedBundles(tmp)
+	if err != nil {
+		t.Fatalf("load missing bundles: %v", err)
+	}
+	if len(bundles) != 0 {
+		t.Fatalf("expected no bundles, got %#v", bundles)
+	}
+
+	billing := DeployBundleKey("https://api.runagents.io/", "billing")
+	support := DeployBundleKey("https://api.runagents.io", "support")
+	if billing != "https://api.runagents.io#billing" {
+		t.Fatalf("unexpected key %q", billing)
+	}
+	if err := SaveDeployedBundle(tmp, billing, DeployedBundle{Hash: "sha256:aaa", Image: "registry/billing:1", Files: 3}); err != nil {
+		t.Fatalf("save billing: %v", err)
+	}
+	if err := SaveDeployedBundle(tmp, support, DeployedBundle{Hash: "sha256:bbb", Files: 1}); err != nil {
+		t.Fatalf("save support: %v", err)
+	}
+
+	bundles, err = LoadDeployedBundles(tmp)
+	if err != nil {
+		t.Fatalf("load bundles: %v", err)
+	}
+	if got := bundles[billing]; got.Hash != "sha256:aaa" || got.Image != "registry/billing:1" || got.DeployedAt.IsZero() {
+		t.Fatalf("unexpected billing bundle: %#v", got)
+	}
+	if bundles[support].Hash != "sha256:bbb" {
+		t.Fatalf("expected support bundle to be kept, got %#v", bundles)
+	}
+
+	info, err := os.Stat(filepath.Join(tmp, ".runagents", "bundles.json"))
+	if err != nil {
+		t.Fatalf("stat bundles file: %v", err)
+	}
+	if got := info.Mode().Perm(); got != 0600 {
+		t.Fatalf("expected bundles file mode 0600, got %o", got)
+	}
+}